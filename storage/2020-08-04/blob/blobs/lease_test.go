@@ -21,9 +21,9 @@ func TestLeaseLifecycle(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	resourceGroup := fmt.Sprintf("acctestrg-%d", testhelpers.RandomInt())
-	accountName := fmt.Sprintf("acctestsa%s", testhelpers.RandomString())
-	containerName := fmt.Sprintf("cont-%d", testhelpers.RandomInt())
+	resourceGroup := fmt.Sprintf("acctestrg-%d", client.RandomInt())
+	accountName := fmt.Sprintf("acctestsa%s", client.RandomString())
+	containerName := fmt.Sprintf("cont-%d", client.RandomInt())
 	fileName := "ubuntu.iso"
 
 	testData, err := client.BuildTestResources(ctx, resourceGroup, accountName, storageaccounts.KindBlobStorage)
@@ -20,9 +20,9 @@ func TestCreateHasNoTagsByDefault(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	resourceGroup := fmt.Sprintf("acctestrg-%d", testhelpers.RandomInt())
-	accountName := fmt.Sprintf("acctestsa%s", testhelpers.RandomString())
-	fileSystemName := fmt.Sprintf("acctestfs-%s", testhelpers.RandomString())
+	resourceGroup := fmt.Sprintf("acctestrg-%d", client.RandomInt())
+	accountName := fmt.Sprintf("acctestsa%s", client.RandomString())
+	fileSystemName := fmt.Sprintf("acctestfs-%s", client.RandomString())
 
 	testData, err := client.BuildTestResources(ctx, resourceGroup, accountName, storageaccounts.KindBlobStorage)
 	if err != nil {
@@ -21,9 +21,9 @@ func TestCreateDirectory(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	resourceGroup := fmt.Sprintf("acctestrg-%d", testhelpers.RandomInt())
-	accountName := fmt.Sprintf("acctestsa%s", testhelpers.RandomString())
-	fileSystemName := fmt.Sprintf("acctestfs-%s", testhelpers.RandomString())
+	resourceGroup := fmt.Sprintf("acctestrg-%d", client.RandomInt())
+	accountName := fmt.Sprintf("acctestsa%s", client.RandomString())
+	fileSystemName := fmt.Sprintf("acctestfs-%s", client.RandomString())
 	path := "test"
 
 	testData, err := client.BuildTestResourcesWithHns(ctx, resourceGroup, accountName, storageaccounts.KindBlobStorage)
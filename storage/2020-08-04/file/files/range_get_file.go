@@ -3,7 +3,6 @@ package files
 import (
 	"context"
 	"fmt"
-	"log"
 	"math"
 	"runtime"
 	"sync"
@@ -60,8 +59,6 @@ func (c Client) GetFile(ctx context.Context, shareName, path, fileName string, i
 
 	for i := 0; i < chunks; i++ {
 		go func(i int) {
-			log.Printf("[DEBUG] Downloading Chunk %d of %d", i+1, chunks)
-
 			dfci := downloadFileChunkInput{
 				thisChunk: i,
 				chunkSize: chunkSize,
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"math"
 	"os"
 	"sync"
@@ -42,8 +41,6 @@ func (c Client) PutFile(ctx context.Context, shareName, path, fileName string, f
 	for i := 0; i < workerCount; i++ {
 		go func() {
 			for i := range jobs {
-				log.Printf("[DEBUG] Chunk %d of %d", i+1, chunks)
-
 				uci := uploadChunkInput{
 					thisChunk: i,
 					chunkSize: chunkSize,
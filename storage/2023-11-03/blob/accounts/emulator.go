@@ -0,0 +1,80 @@
+package accounts
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+)
+
+const (
+	// EmulatorAccountName is the well-known name of the Storage Account exposed by Storage Emulators (such as Azurite)
+	EmulatorAccountName = "devstoreaccount1"
+
+	// EmulatorAccountKey is the well-known (and publicly documented) Account Key for the EmulatorAccountName Storage Account
+	EmulatorAccountKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+	// EmulatorHost is the default host on which Storage Emulators listen
+	EmulatorHost = "127.0.0.1"
+
+	// EmulatorBlobPort is the default port used by Storage Emulators for the Blob Service
+	EmulatorBlobPort = 10000
+
+	// EmulatorQueuePort is the default port used by Storage Emulators for the Queue Service
+	EmulatorQueuePort = 10001
+
+	// EmulatorTablePort is the default port used by Storage Emulators for the Table Service
+	EmulatorTablePort = 10002
+)
+
+// EmulatorPortForSubDomainType returns the default port used by Storage Emulators for the specified
+// `subDomainType` - Storage Emulators don't support the DataLakeStore or File Services.
+func EmulatorPortForSubDomainType(subDomainType SubDomainType) (*int, error) {
+	switch subDomainType {
+	case BlobSubDomainType:
+		return pointer.To(EmulatorBlobPort), nil
+	case QueueSubDomainType:
+		return pointer.To(EmulatorQueuePort), nil
+	case TableSubDomainType:
+		return pointer.To(EmulatorTablePort), nil
+	}
+
+	return nil, fmt.Errorf("the subdomain type %q isn't supported by the Storage Emulator", string(subDomainType))
+}
+
+// NewEmulatorAccountID returns the path-style Account ID for the well-known Storage Account exposed by a
+// Storage Emulator listening on the default host and port for the specified `subDomainType`,
+// for example `http://127.0.0.1:10000/devstoreaccount1`.
+func NewEmulatorAccountID(subDomainType SubDomainType) (*AccountId, error) {
+	port, err := EmulatorPortForSubDomainType(subDomainType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountId{
+		AccountName:   EmulatorAccountName,
+		SubDomainType: subDomainType,
+		DomainSuffix:  fmt.Sprintf("%s:%d", EmulatorHost, *port),
+		IsPathStyle:   true,
+		Scheme:        pointer.To("http"),
+	}, nil
+}
+
+// subDomainTypeForEmulatorPort determines the subdomain type of a path-style Storage Account from the port
+// of the Storage Emulator, since (unlike regular Storage Accounts) this isn't otherwise part of the URI. Storage
+// Emulators are commonly exposed on other ports (e.g. when the port is mapped by a container runtime), in which
+// case this falls back to the Blob subdomain type.
+func subDomainTypeForEmulatorPort(input string) SubDomainType {
+	port, err := strconv.Atoi(input)
+	if err != nil {
+		return BlobSubDomainType
+	}
+
+	for _, subDomainType := range []SubDomainType{BlobSubDomainType, QueueSubDomainType, TableSubDomainType} {
+		if emulatorPort, err := EmulatorPortForSubDomainType(subDomainType); err == nil && *emulatorPort == port {
+			return subDomainType
+		}
+	}
+
+	return BlobSubDomainType
+}
@@ -35,7 +35,7 @@ func (c Client) GetServiceProperties(ctx context.Context, accountName string) (r
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetServiceProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
 
@@ -39,9 +40,28 @@ type AccountId struct {
 	SubDomainType SubDomainType
 	DomainSuffix  string
 	IsEdgeZone    bool
+
+	// IsPathStyle specifies whether this Storage Account is addressed using a path-style URI, as used by
+	// Storage Emulators such as Azurite, in the format `{scheme}://{domainSuffix}/{accountname}` - where
+	// DomainSuffix is the host (and port) of the Emulator, e.g. `http://127.0.0.1:10000/devstoreaccount1`.
+	IsPathStyle bool
+
+	// Scheme specifies the URI scheme used for a path-style Storage Account, defaulting to `http` when unset.
+	// Storage Accounts which aren't path-style always use `https`.
+	Scheme *string
 }
 
 func (a AccountId) ID() string {
+	if a.IsPathStyle {
+		// Storage Accounts using a path-style URI (e.g. a Storage Emulator)
+		//   `{scheme}://{host}:{port}/{accountname}`
+		scheme := "http"
+		if a.Scheme != nil && *a.Scheme != "" {
+			scheme = *a.Scheme
+		}
+		return fmt.Sprintf("%s://%s/%s", scheme, a.DomainSuffix, a.AccountName)
+	}
+
 	components := []string{
 		a.AccountName,
 	}
@@ -73,20 +93,41 @@ func (a AccountId) String() string {
 		fmt.Sprintf("Subdomain Type %q", string(a.SubDomainType)),
 		fmt.Sprintf("DomainSuffix %q", a.DomainSuffix),
 	}
+	if a.IsPathStyle {
+		components = append(components, fmt.Sprintf("IsPathStyle %t", a.IsPathStyle))
+	}
 	return fmt.Sprintf("Account %q (%s)", a.AccountName, strings.Join(components, " / "))
 }
 
+// ParseAccountID parses `input` into an Account ID using a known `domainSuffix`. Since the subdomain type of
+// a path-style Account (e.g. a Storage Emulator) isn't part of the URI, this is determined from the default
+// port used by Storage Emulators for each service - falling back to Blob for any other port. Use
+// ParseAccountIDWithSubDomainType when the subdomain type is known.
 func ParseAccountID(input, domainSuffix string) (*AccountId, error) {
+	return parseAccountID(input, domainSuffix, nil)
+}
+
+// ParseAccountIDWithSubDomainType parses `input` into an Account ID using a known `domainSuffix`, where any
+// path-style Account (e.g. a Storage Emulator listening on a mapped or random port) uses the specified `subDomainType`.
+func ParseAccountIDWithSubDomainType(input, domainSuffix string, subDomainType SubDomainType) (*AccountId, error) {
+	return parseAccountID(input, domainSuffix, &subDomainType)
+}
+
+func parseAccountID(input, domainSuffix string, pathStyleSubDomainType *SubDomainType) (*AccountId, error) {
 	uri, err := url.Parse(input)
 	if err != nil {
 		return nil, fmt.Errorf("parsing %q as a URL: %s", input, err)
 	}
 
+	if isPathStyleHost(uri, domainSuffix) {
+		return parsePathStyleAccountID(input, uri, pathStyleSubDomainType)
+	}
+
 	if !strings.HasSuffix(uri.Host, domainSuffix) {
 		return nil, fmt.Errorf("expected the account %q to use a domain suffix of %q", uri.Host, domainSuffix)
 	}
 
-	// There's 3 different types of Storage Account ID:
+	// There's 3 different types of Storage Account ID (besides path-style Accounts, handled above):
 	// 1. Regular ol' Storage Accounts
 	//   `{name}.{component}.core.windows.net` (e.g. `example1.blob.core.windows.net`)
 	// 2. Storage Accounts using a DNS Zone
@@ -144,6 +185,54 @@ func ParseAccountID(input, domainSuffix string) (*AccountId, error) {
 	return nil, fmt.Errorf("unknown storage account domain type %q", input)
 }
 
+// ResourcePath returns the path of the resource within `uri` relative to this Storage Account, without a leading
+// slash - which for a path-style Storage Account excludes the leading Account Name segment.
+func (a AccountId) ResourcePath(uri *url.URL) (string, error) {
+	path := strings.TrimPrefix(uri.Path, "/")
+	if !a.IsPathStyle {
+		return path, nil
+	}
+
+	accountName, resourcePath, _ := strings.Cut(path, "/")
+	if accountName != a.AccountName {
+		return "", fmt.Errorf("expected the path %q to begin with the account name %q", uri.Path, a.AccountName)
+	}
+	return resourcePath, nil
+}
+
+// isPathStyleHost determines whether `uri` uses a path-style Storage Account, which is the case when the host
+// matches the `domainSuffix` exactly (e.g. `azurite:10000`) or is an IP Address or `localhost`.
+func isPathStyleHost(uri *url.URL, domainSuffix string) bool {
+	if uri.Host == "" {
+		return false
+	}
+	if strings.EqualFold(uri.Host, domainSuffix) {
+		return true
+	}
+	hostName := uri.Hostname()
+	return strings.EqualFold(hostName, "localhost") || net.ParseIP(hostName) != nil
+}
+
+func parsePathStyleAccountID(input string, uri *url.URL, subDomainType *SubDomainType) (*AccountId, error) {
+	// `{scheme}://{host}:{port}/{accountname}` (e.g. `http://127.0.0.1:10000/devstoreaccount1`)
+	accountName, _, _ := strings.Cut(strings.TrimPrefix(uri.Path, "/"), "/")
+	if accountName == "" {
+		return nil, fmt.Errorf("expected the path-style account %q to contain an account name", input)
+	}
+
+	if subDomainType == nil {
+		subDomainType = pointer.To(subDomainTypeForEmulatorPort(uri.Port()))
+	}
+
+	return &AccountId{
+		AccountName:   accountName,
+		SubDomainType: *subDomainType,
+		DomainSuffix:  uri.Host,
+		IsPathStyle:   true,
+		Scheme:        pointer.To(uri.Scheme),
+	}, nil
+}
+
 func parseSubDomainType(input string) (*SubDomainType, error) {
 	for _, k := range PossibleValuesForSubDomainType() {
 		if strings.EqualFold(input, string(k)) {
@@ -131,3 +131,108 @@ func TestFormatAccountIDInEdgeZone(t *testing.T) {
 		t.Fatalf("expected %q but got %q", expected, actual)
 	}
 }
+
+func TestParseAccountIDPathStyle(t *testing.T) {
+	input := "http://127.0.0.1:10001/devstoreaccount1"
+	actual, err := ParseAccountID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountName != "devstoreaccount1" {
+		t.Fatalf("expected AccountName to be %q but got %q", "devstoreaccount1", actual.AccountName)
+	}
+	if actual.SubDomainType != QueueSubDomainType {
+		t.Fatalf("expected SubDomainType to be %q but got %q", QueueSubDomainType, actual.SubDomainType)
+	}
+	if actual.DomainSuffix != "127.0.0.1:10001" {
+		t.Fatalf("expected DomainSuffix to be %q but got %q", "127.0.0.1:10001", actual.DomainSuffix)
+	}
+	if !actual.IsPathStyle {
+		t.Fatalf("expected IsPathStyle to be true but got %t", actual.IsPathStyle)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
+
+func TestParseAccountIDPathStyleMatchingDomainSuffix(t *testing.T) {
+	input := "https://azurite:10000/account1"
+	actual, err := ParseAccountID(input, "azurite:10000")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountName != "account1" {
+		t.Fatalf("expected AccountName to be %q but got %q", "account1", actual.AccountName)
+	}
+	if actual.SubDomainType != BlobSubDomainType {
+		t.Fatalf("expected SubDomainType to be %q but got %q", BlobSubDomainType, actual.SubDomainType)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
+
+func TestParseAccountIDPathStyleUnknownPort(t *testing.T) {
+	input := "http://127.0.0.1:1234/devstoreaccount1"
+	actual, err := ParseAccountID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.SubDomainType != BlobSubDomainType {
+		t.Fatalf("expected SubDomainType to default to %q but got %q", BlobSubDomainType, actual.SubDomainType)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
+
+func TestParseAccountIDWithSubDomainTypePathStyle(t *testing.T) {
+	input := "http://127.0.0.1:49153/devstoreaccount1"
+	actual, err := ParseAccountIDWithSubDomainType(input, "core.windows.net", QueueSubDomainType)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountName != "devstoreaccount1" {
+		t.Fatalf("expected AccountName to be %q but got %q", "devstoreaccount1", actual.AccountName)
+	}
+	if actual.SubDomainType != QueueSubDomainType {
+		t.Fatalf("expected SubDomainType to be %q but got %q", QueueSubDomainType, actual.SubDomainType)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
+
+func TestFormatAccountIDPathStyle(t *testing.T) {
+	actual := AccountId{
+		AccountName:   "devstoreaccount1",
+		SubDomainType: TableSubDomainType,
+		DomainSuffix:  "localhost:10002",
+		IsPathStyle:   true,
+	}.ID()
+	expected := "http://localhost:10002/devstoreaccount1"
+	if actual != expected {
+		t.Fatalf("expected %q but got %q", expected, actual)
+	}
+}
+
+func TestNewEmulatorAccountID(t *testing.T) {
+	testData := map[SubDomainType]string{
+		BlobSubDomainType:  "http://127.0.0.1:10000/devstoreaccount1",
+		QueueSubDomainType: "http://127.0.0.1:10001/devstoreaccount1",
+		TableSubDomainType: "http://127.0.0.1:10002/devstoreaccount1",
+	}
+	for subDomainType, expected := range testData {
+		actual, err := NewEmulatorAccountID(subDomainType)
+		if err != nil {
+			t.Fatalf("building Emulator Account ID for %q: %+v", subDomainType, err)
+		}
+		if actual.ID() != expected {
+			t.Fatalf("expected %q but got %q", expected, actual.ID())
+		}
+	}
+
+	if _, err := NewEmulatorAccountID(FileSubDomainType); err == nil {
+		t.Fatalf("expected an error for the File subdomain type but didn't get one")
+	}
+}
@@ -39,7 +39,7 @@ func (c Client) SetServiceProperties(ctx context.Context, accountName string, in
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetServiceProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -19,8 +19,8 @@ func TestContainerLifecycle(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	resourceGroup := fmt.Sprintf("acctestrg-%d", testhelpers.RandomInt())
-	accountName := fmt.Sprintf("acctestsa%s", testhelpers.RandomString())
+	resourceGroup := fmt.Sprintf("acctestrg-%d", client.RandomInt())
+	accountName := fmt.Sprintf("acctestsa%s", client.RandomString())
 
 	_, err = client.BuildTestResources(ctx, resourceGroup, accountName, storageaccounts.KindStorageVTwo)
 	if err != nil {
@@ -7,24 +7,34 @@ import (
 
 type StorageBlob interface {
 	AppendBlock(ctx context.Context, containerName string, blobName string, input AppendBlockInput) (AppendBlockResponse, error)
+	NewAppendBlobWriter(ctx context.Context, containerName string, blobName string, options AppendBlobWriterOptions) (*AppendBlobWriter, error)
 	Copy(ctx context.Context, containerName string, blobName string, input CopyInput) (CopyResponse, error)
+	CopyFromURL(ctx context.Context, containerName string, blobName string, input CopyFromURLInput) (CopyFromURLResponse, error)
+	CopyFromURLInBlocks(ctx context.Context, containerName string, blobName string, input CopyFromURLInBlocksInput) error
 	AbortCopy(ctx context.Context, containerName string, blobName string, input AbortCopyInput) (CopyAbortResponse, error)
 	CopyAndWait(ctx context.Context, containerName string, blobName string, input CopyInput) error
+	CopyAndWaitWithOptions(ctx context.Context, containerName string, blobName string, input CopyInput, options CopyAndWaitOptions) error
 	Delete(ctx context.Context, containerName string, blobName string, input DeleteInput) (DeleteResponse, error)
 	DeleteSnapshot(ctx context.Context, containerName string, blobName string, input DeleteSnapshotInput) (DeleteSnapshotResponse, error)
 	DeleteSnapshots(ctx context.Context, containerName string, blobName string, input DeleteSnapshotsInput) (DeleteSnapshotsResponse, error)
+	DeleteImmutabilityPolicy(ctx context.Context, containerName string, blobName string, input DeleteImmutabilityPolicyInput) (DeleteImmutabilityPolicyResponse, error)
 	Get(ctx context.Context, containerName string, blobName string, input GetInput) (GetResponse, error)
+	Query(ctx context.Context, containerName string, blobName string, input QueryInput) (QueryResponse, error)
 	GetBlockList(ctx context.Context, containerName string, blobName string, input GetBlockListInput) (GetBlockListResponse, error)
 	GetPageRanges(ctx context.Context, containerName, blobName string, input GetPageRangesInput) (GetPageRangesResponse, error)
+	CopyChangedPages(ctx context.Context, containerName string, blobName string, input CopyChangedPagesInput) (CopyChangedPagesResult, error)
 	IncrementalCopyBlob(ctx context.Context, containerName string, blobName string, input IncrementalCopyBlobInput) (IncrementalCopyBlob, error)
 	AcquireLease(ctx context.Context, containerName string, blobName string, input AcquireLeaseInput) (AcquireLeaseResponse, error)
 	BreakLease(ctx context.Context, containerName string, blobName string, input BreakLeaseInput) (BreakLeaseResponse, error)
 	ChangeLease(ctx context.Context, containerName string, blobName string, input ChangeLeaseInput) (ChangeLeaseResponse, error)
 	ReleaseLease(ctx context.Context, containerName string, blobName string, input ReleaseLeaseInput) (ReleaseLeaseResponse, error)
 	RenewLease(ctx context.Context, containerName string, blobName string, input RenewLeaseInput) (RenewLeaseResponse, error)
+	SetImmutabilityPolicy(ctx context.Context, containerName string, blobName string, input SetImmutabilityPolicyInput) (SetImmutabilityPolicyResponse, error)
+	SetLegalHold(ctx context.Context, containerName string, blobName string, input SetLegalHoldInput) (SetLegalHoldResponse, error)
 	SetMetaData(ctx context.Context, containerName string, blobName string, input SetMetaDataInput) (SetMetaDataResponse, error)
 	GetProperties(ctx context.Context, containerName string, blobName string, input GetPropertiesInput) (GetPropertiesResponse, error)
 	SetProperties(ctx context.Context, containerName string, blobName string, input SetPropertiesInput) (SetPropertiesResponse, error)
+	PutBlobFromURL(ctx context.Context, containerName string, blobName string, input PutBlobFromURLInput) (PutBlobFromURLResponse, error)
 	PutAppendBlob(ctx context.Context, containerName string, blobName string, input PutAppendBlobInput) (PutAppendBlobResponse, error)
 	PutBlock(ctx context.Context, containerName string, blobName string, input PutBlockInput) (PutBlockResponse, error)
 	PutBlockBlob(ctx context.Context, containerName string, blobName string, input PutBlockBlobInput) (PutBlockBlobResponse, error)
@@ -34,8 +44,14 @@ type StorageBlob interface {
 	PutPageBlob(ctx context.Context, containerName string, blobName string, input PutPageBlobInput) (PutPageBlobResponse, error)
 	PutPageClear(ctx context.Context, containerName string, blobName string, input PutPageClearInput) (PutPageClearResponse, error)
 	PutPageUpdate(ctx context.Context, containerName string, blobName string, input PutPageUpdateInput) (PutPageUpdateResponse, error)
+	PutPageFromURL(ctx context.Context, containerName string, blobName string, input PutPageFromURLInput) (PutPageFromURLResponse, error)
 	SetTier(ctx context.Context, containerName string, blobName string, input SetTierInput) (SetTierResponse, error)
+	RehydrateAndWait(ctx context.Context, containerName string, blobName string, input SetTierInput) error
+	RehydrateAndWaitWithOptions(ctx context.Context, containerName string, blobName string, input SetTierInput, options RehydrateAndWaitOptions) error
 	Snapshot(ctx context.Context, containerName string, blobName string, input SnapshotInput) (SnapshotResponse, error)
 	GetSnapshotProperties(ctx context.Context, containerName string, blobName string, input GetSnapshotPropertiesInput) (GetPropertiesResponse, error)
+	OpenPageBlobFile(ctx context.Context, containerName string, blobName string, options PageBlobFileOptions) (*PageBlobFile, error)
+	PromoteVersion(ctx context.Context, containerName string, blobName string, input PromoteVersionInput) error
+	PromoteVersionWithOptions(ctx context.Context, containerName string, blobName string, input PromoteVersionInput, options PromoteVersionOptions) error
 	Undelete(ctx context.Context, containerName string, blobName string) (UndeleteResponse, error)
 }
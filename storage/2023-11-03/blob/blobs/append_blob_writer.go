@@ -0,0 +1,323 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
+)
+
+const (
+	// maxAppendBlockBytes is the largest block which can be appended to an Append Blob in a single request
+	maxAppendBlockBytes = 4 * 1024 * 1024
+
+	// maxAppendBlocks is the maximum number of blocks which can be committed to an Append Blob
+	maxAppendBlocks = 50000
+)
+
+// ErrAppendPositionConditionNotMet is returned by the AppendBlobWriter when the Append Blob has been written to by
+// another writer - meaning the content of the Append Blob is no longer at the position the AppendBlobWriter expected.
+//
+// This can be checked using `errors.Is(err, blobs.ErrAppendPositionConditionNotMet)`.
+var ErrAppendPositionConditionNotMet = errors.New("the append blob has been written to by another writer")
+
+var _ io.WriteCloser = &AppendBlobWriter{}
+
+type AppendBlobWriterOptions struct {
+	// FlushSize is the number of bytes which are buffered before being appended to the Append Blob as a single
+	// block. Defaults to (and can be at most) 4 MiB.
+	FlushSize int
+
+	// FlushInterval optionally specifies how often any buffered bytes are appended to the Append Blob, regardless
+	// of FlushSize. When zero, buffered bytes are only appended once FlushSize is reached (or on Flush/Close).
+	FlushInterval time.Duration
+
+	// MaxBlocksPerBlob is the number of blocks which can be committed to an Append Blob before the AppendBlobWriter
+	// rolls over to a new Append Blob (named `{blobName}.1`, `{blobName}.2` etc). Defaults to (and can be at most) 50,000.
+	MaxBlocksPerBlob int64
+
+	// The properties used when creating each Append Blob
+	ContentType *string
+	MetaData    map[string]string
+
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+
+	// Optionally computes a checksum of each block using this Algorithm, which the Storage API validates on receipt
+	ChecksumAlgorithm *checksum.Algorithm
+}
+
+// AppendBlobWriter is an io.WriteCloser which buffers writes and appends them to an Append Blob - using the append
+// position condition to detect concurrent writers, and rolling over to a new Append Blob when the number of blocks
+// committed to the current Append Blob reaches the limit.
+type AppendBlobWriter struct {
+	ctx           context.Context
+	client        Client
+	containerName string
+	blobName      string
+	options       AppendBlobWriterOptions
+
+	mu          sync.Mutex
+	buffer      []byte
+	index       int
+	position    int64
+	blockCount  int64
+	err         error
+	closed      bool
+	stopFlusher chan struct{}
+	flusherDone chan struct{}
+}
+
+// NewAppendBlobWriter returns an AppendBlobWriter which appends to `blobName` - where `ctx` is used for each request
+// made by the AppendBlobWriter. The Append Blob is created if it doesn't exist, otherwise writes are appended to the
+// end of the existing Append Blob (or the latest Append Blob it has been rolled over to).
+func (c Client) NewAppendBlobWriter(ctx context.Context, containerName, blobName string, options AppendBlobWriterOptions) (*AppendBlobWriter, error) {
+	if options.FlushSize < 0 || options.FlushSize > maxAppendBlockBytes {
+		return nil, fmt.Errorf("`options.FlushSize` must be between 0 and %d bytes", maxAppendBlockBytes)
+	}
+	if options.FlushSize == 0 {
+		options.FlushSize = maxAppendBlockBytes
+	}
+	if options.FlushInterval < 0 {
+		return nil, fmt.Errorf("`options.FlushInterval` cannot be negative")
+	}
+	if options.MaxBlocksPerBlob < 0 || options.MaxBlocksPerBlob > maxAppendBlocks {
+		return nil, fmt.Errorf("`options.MaxBlocksPerBlob` must be between 0 and %d", maxAppendBlocks)
+	}
+	if options.MaxBlocksPerBlob == 0 {
+		options.MaxBlocksPerBlob = maxAppendBlocks
+	}
+	if options.ChecksumAlgorithm != nil {
+		if err := options.ChecksumAlgorithm.Validate(); err != nil {
+			return nil, fmt.Errorf("`options.ChecksumAlgorithm` is not valid: %+v", err)
+		}
+	}
+
+	w := &AppendBlobWriter{
+		ctx:           ctx,
+		client:        c,
+		containerName: containerName,
+		blobName:      blobName,
+		options:       options,
+		buffer:        make([]byte, 0, options.FlushSize),
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	if options.FlushInterval > 0 {
+		w.stopFlusher = make(chan struct{})
+		w.flusherDone = make(chan struct{})
+		go w.flushPeriodically()
+	}
+
+	return w, nil
+}
+
+// BlobName returns the name of the Append Blob currently being written to.
+func (w *AppendBlobWriter) BlobName() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.currentBlobName()
+}
+
+// Write buffers `p`, appending the buffered bytes to the Append Blob each time FlushSize is reached. Once an append
+// has failed the error is returned from all subsequent calls.
+func (w *AppendBlobWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, fmt.Errorf("the append blob writer has been closed")
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	for len(p) > 0 {
+		remaining := w.options.FlushSize - len(w.buffer)
+		if remaining > len(p) {
+			remaining = len(p)
+		}
+		w.buffer = append(w.buffer, p[:remaining]...)
+		p = p[remaining:]
+		n += remaining
+
+		if len(w.buffer) == w.options.FlushSize {
+			if err := w.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// Flush appends any buffered bytes to the Append Blob.
+func (w *AppendBlobWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	return w.flush()
+}
+
+// Close appends any buffered bytes to the Append Blob and stops the periodic flushing, if enabled.
+func (w *AppendBlobWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	if w.stopFlusher != nil {
+		close(w.stopFlusher)
+		<-w.flusherDone
+	}
+
+	return w.Flush()
+}
+
+func (w *AppendBlobWriter) flushPeriodically() {
+	defer close(w.flusherDone)
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stopFlusher:
+			return
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			// any error is retained and returned from the next call to Write, Flush or Close
+			_ = w.Flush()
+		}
+	}
+}
+
+// flush appends the buffered bytes to the Append Blob, rolling over to a new Append Blob when the current one is
+// full - the caller must hold the lock.
+func (w *AppendBlobWriter) flush() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+
+	if w.blockCount >= w.options.MaxBlocksPerBlob {
+		w.index++
+		if err := w.open(); err != nil {
+			w.err = err
+			return err
+		}
+	}
+
+	blobName := w.currentBlobName()
+	content := w.buffer
+	input := AppendBlockInput{
+		BlobConditionAppendPosition: pointer.To(w.position),
+		Content:                     &content,
+		EncryptionScope:             w.options.EncryptionScope,
+		CustomerProvidedKey:         w.options.CustomerProvidedKey,
+		ChecksumAlgorithm:           w.options.ChecksumAlgorithm,
+	}
+	resp, err := w.client.AppendBlock(w.ctx, w.containerName, blobName, input)
+	if err != nil {
+		if resp.HttpResponse != nil && resp.HttpResponse.StatusCode == http.StatusPreconditionFailed {
+			w.err = fmt.Errorf("%w: appending %d bytes to %q at position %d: %+v", ErrAppendPositionConditionNotMet, len(content), blobName, w.position, err)
+			return w.err
+		}
+		// the buffered bytes are retained, so that the append can be retried by calling Flush
+		return fmt.Errorf("appending %d bytes to %q: %+v", len(content), blobName, err)
+	}
+
+	w.position += int64(len(content))
+	w.blockCount = resp.BlobCommittedBlockCount
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+// open finds the Append Blob to write to, starting from the current index and moving on to the next Append Blob
+// whilst the block count limit has been reached - creating the Append Blob if it doesn't exist.
+func (w *AppendBlobWriter) open() error {
+	for {
+		blobName := w.currentBlobName()
+		props, err := w.client.GetProperties(w.ctx, w.containerName, blobName, GetPropertiesInput{
+			CustomerProvidedKey: w.options.CustomerProvidedKey,
+		})
+		if err != nil {
+			if props.HttpResponse == nil || props.HttpResponse.StatusCode != http.StatusNotFound {
+				return fmt.Errorf("retrieving properties for %q: %+v", blobName, err)
+			}
+
+			w.client.Logger.Log(w.ctx, slog.LevelDebug, "creating append blob", "container", w.containerName, "blob", blobName)
+			input := PutAppendBlobInput{
+				ContentType:         w.options.ContentType,
+				MetaData:            w.options.MetaData,
+				EncryptionScope:     w.options.EncryptionScope,
+				CustomerProvidedKey: w.options.CustomerProvidedKey,
+				IfNoneMatch:         pointer.To("*"),
+			}
+			resp, err := w.client.PutAppendBlob(w.ctx, w.containerName, blobName, input)
+			if err != nil {
+				if blobCreatedConcurrently(resp.HttpResponse) {
+					// another writer created the blob in the meantime (e.g. when rolling over to the same blob), which
+					// would otherwise be overwritten - so it's re-opened instead
+					w.client.Logger.Log(w.ctx, slog.LevelDebug, "append blob was created by another writer", "container", w.containerName, "blob", blobName)
+					continue
+				}
+				return fmt.Errorf("creating append blob %q: %+v", blobName, err)
+			}
+			w.position = 0
+			w.blockCount = 0
+			return nil
+		}
+
+		if props.BlobType != AppendBlob {
+			return fmt.Errorf("expected %q to be a %s but got %s", blobName, string(AppendBlob), string(props.BlobType))
+		}
+		blockCount := int64(0)
+		if props.BlobCommittedBlockCount != "" {
+			blockCount, err = strconv.ParseInt(props.BlobCommittedBlockCount, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parsing the committed block count %q for %q: %+v", props.BlobCommittedBlockCount, blobName, err)
+			}
+		}
+		if blockCount < w.options.MaxBlocksPerBlob {
+			w.position = props.ContentLength
+			w.blockCount = blockCount
+			return nil
+		}
+
+		w.index++
+	}
+}
+
+// blobCreatedConcurrently returns whether creating a blob using `If-None-Match: *` failed since the blob already exists
+func blobCreatedConcurrently(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusPreconditionFailed:
+		return true
+	case http.StatusConflict:
+		return resp.Header.Get("x-ms-error-code") == "BlobAlreadyExists"
+	}
+	return false
+}
+
+func (w *AppendBlobWriter) currentBlobName() string {
+	if w.index == 0 {
+		return w.blobName
+	}
+	return fmt.Sprintf("%s.%d", w.blobName, w.index)
+}
@@ -0,0 +1,160 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestAppendBlobWriter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	blobClient := buildBlobClient(t, ctx, server)
+	containerName := "container1"
+	fileName := "service.log"
+
+	t.Logf("[DEBUG] Writing with Rollover..")
+	writer, err := blobClient.NewAppendBlobWriter(ctx, containerName, fileName, AppendBlobWriterOptions{
+		FlushSize:        10,
+		MaxBlocksPerBlob: 2,
+		ContentType:      pointer.To("text/plain"),
+	})
+	if err != nil {
+		t.Fatalf("opening writer: %+v", err)
+	}
+	// 45 bytes are written as 4 full blocks, with the remaining 5 bytes buffered
+	content := strings.Repeat("abcdefghi\n", 4) + "jklmn"
+	if n, err := writer.Write([]byte(content)); err != nil || n != len(content) {
+		t.Fatalf("expected %d bytes to be written but got %d: %+v", len(content), n, err)
+	}
+	if name := writer.BlobName(); name != "service.log.1" {
+		t.Fatalf("expected the writer to have rolled over to %q but got %q", "service.log.1", name)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("closing writer: %+v", err)
+	}
+	assertBlobContent(t, ctx, blobClient, containerName, "service.log", []byte(content[0:20]))
+	assertBlobContent(t, ctx, blobClient, containerName, "service.log.1", []byte(content[20:40]))
+	assertBlobContent(t, ctx, blobClient, containerName, "service.log.2", []byte(content[40:]))
+	if _, err := writer.Write([]byte("closed")); err == nil {
+		t.Fatalf("expected an error writing to a closed writer but didn't get one")
+	}
+
+	t.Logf("[DEBUG] Reopening the Writer..")
+	writer, err = blobClient.NewAppendBlobWriter(ctx, containerName, fileName, AppendBlobWriterOptions{
+		MaxBlocksPerBlob: 2,
+		FlushInterval:    10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("reopening writer: %+v", err)
+	}
+	if name := writer.BlobName(); name != "service.log.2" {
+		t.Fatalf("expected the writer to resume writing to %q but got %q", "service.log.2", name)
+	}
+	if _, err := writer.Write([]byte("opqrs")); err != nil {
+		t.Fatalf("writing: %+v", err)
+	}
+	for {
+		props, err := blobClient.GetProperties(ctx, containerName, "service.log.2", GetPropertiesInput{})
+		if err != nil {
+			t.Fatalf("retrieving properties: %+v", err)
+		}
+		if props.ContentLength == 10 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for the buffered bytes to be flushed")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	assertBlobContent(t, ctx, blobClient, containerName, "service.log.2", []byte("jklmnopqrs"))
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("closing writer: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Detecting a Concurrent Writer..")
+	writer, err = blobClient.NewAppendBlobWriter(ctx, containerName, "other.log", AppendBlobWriterOptions{})
+	if err != nil {
+		t.Fatalf("opening writer: %+v", err)
+	}
+	if _, err := writer.Write([]byte("first")); err != nil {
+		t.Fatalf("writing: %+v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("flushing: %+v", err)
+	}
+	if _, err := blobClient.AppendBlock(ctx, containerName, "other.log", AppendBlockInput{Content: pointer.To([]byte("other"))}); err != nil {
+		t.Fatalf("appending block: %+v", err)
+	}
+	if _, err := writer.Write([]byte("second")); err != nil {
+		t.Fatalf("writing: %+v", err)
+	}
+	if err := writer.Close(); !errors.Is(err, ErrAppendPositionConditionNotMet) {
+		t.Fatalf("expected %v but got: %+v", ErrAppendPositionConditionNotMet, err)
+	}
+	assertBlobContent(t, ctx, blobClient, containerName, "other.log", []byte("firstother"))
+}
+
+func TestAppendBlobWriterBlobCreatedConcurrently(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	blobClient := buildBlobClient(t, ctx, server)
+	containerName := "container1"
+	fileName := "race.log"
+
+	// another writer creates (and appends to) the blob after this writer has found it doesn't exist, but
+	// before this writer creates it - which would otherwise overwrite the other writer's content
+	var once sync.Once
+	var otherErr error
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.Header.Get("x-ms-blob-type") == string(AppendBlob) {
+			once.Do(func() {
+				if _, otherErr = blobClient.PutAppendBlob(ctx, containerName, fileName, PutAppendBlobInput{}); otherErr != nil {
+					return
+				}
+				_, otherErr = blobClient.AppendBlock(ctx, containerName, fileName, AppendBlockInput{Content: pointer.To([]byte("other"))})
+			})
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	proxyClient, err := NewWithBaseUri(proxy.URL + "/" + server.AccountName)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	proxyClient.Client.SetAuthorizer(authorizer)
+
+	writer, err := proxyClient.NewAppendBlobWriter(ctx, containerName, fileName, AppendBlobWriterOptions{})
+	if err != nil {
+		t.Fatalf("opening writer: %+v", err)
+	}
+	if otherErr != nil {
+		t.Fatalf("writing using the other writer: %+v", otherErr)
+	}
+	if _, err := writer.Write([]byte("mine")); err != nil {
+		t.Fatalf("writing: %+v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("closing writer: %+v", err)
+	}
+	assertBlobContent(t, ctx, blobClient, containerName, fileName, []byte("othermine"))
+}
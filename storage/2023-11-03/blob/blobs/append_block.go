@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
 )
 
 type AppendBlockInput struct {
@@ -43,6 +44,12 @@ type AppendBlockInput struct {
 
 	// The encryption scope to set for the request content.
 	EncryptionScope *string
+
+	// The encryption key provided by the client, which is used to encrypt the blob (and is not stored by Azure)
+	CustomerProvidedKey *CustomerProvidedKey
+
+	// Optionally computes a checksum of the Content using this Algorithm, which the Storage API validates on receipt
+	ChecksumAlgorithm *checksum.Algorithm
 }
 
 type AppendBlockResponse struct {
@@ -77,6 +84,18 @@ func (c Client) AppendBlock(ctx context.Context, containerName, blobName string,
 		return
 	}
 
+	if input.ChecksumAlgorithm != nil {
+		if err = input.ChecksumAlgorithm.Validate(); err != nil {
+			err = fmt.Errorf("`input.ChecksumAlgorithm` is not valid: %+v", err)
+			return
+		}
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -101,7 +120,7 @@ func (c Client) AppendBlock(ctx context.Context, containerName, blobName string,
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "AppendBlock", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -152,6 +171,10 @@ func (a appendBlockOptions) ToHeaders() *client.Headers {
 	if a.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *a.input.EncryptionScope)
 	}
+	a.input.CustomerProvidedKey.appendHeaders(headers)
+	if a.input.ChecksumAlgorithm != nil && a.input.Content != nil {
+		headers.Append(a.input.ChecksumAlgorithm.Header(), a.input.ChecksumAlgorithm.Compute(*a.input.Content))
+	}
 	if a.input.Content != nil {
 		headers.Append("Content-Length", strconv.Itoa(len(*a.input.Content)))
 	}
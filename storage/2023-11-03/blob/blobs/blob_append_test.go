@@ -16,14 +16,14 @@ func TestAppendBlobLifecycle(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Hour)
 	defer cancel()
 
-	client, err := testhelpers.Build(ctx, t)
+	client, err := testhelpers.BuildWithBlobServer(ctx, t)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	resourceGroup := fmt.Sprintf("acctestrg-%d", testhelpers.RandomInt())
-	accountName := fmt.Sprintf("acctestsa%s", testhelpers.RandomString())
-	containerName := fmt.Sprintf("cont-%d", testhelpers.RandomInt())
+	resourceGroup := fmt.Sprintf("acctestrg-%d", client.RandomInt())
+	accountName := fmt.Sprintf("acctestsa%s", client.RandomString())
+	containerName := fmt.Sprintf("cont-%d", client.RandomInt())
 	fileName := "append-blob.txt"
 
 	testData, err := client.BuildTestResources(ctx, resourceGroup, accountName, storageaccounts.KindBlobStorage)
@@ -32,12 +32,12 @@ func TestAppendBlobLifecycle(t *testing.T) {
 	}
 	defer client.DestroyTestResources(ctx, resourceGroup, accountName)
 
-	domainSuffix, ok := client.Environment.Storage.DomainSuffix()
-	if !ok {
-		t.Fatalf("storage didn't return a domain suffix for this environment")
+	blobEndpoint, err := client.BlobEndpoint(testData)
+	if err != nil {
+		t.Fatalf("building blob endpoint: %+v", err)
 	}
 
-	containersClient, err := containers.NewWithBaseUri(fmt.Sprintf("https://%s.blob.%s", testData.StorageAccountName, *domainSuffix))
+	containersClient, err := containers.NewWithBaseUri(*blobEndpoint)
 	if err != nil {
 		t.Fatalf("building client for environment: %+v", err)
 	}
@@ -52,7 +52,7 @@ func TestAppendBlobLifecycle(t *testing.T) {
 	}
 	defer containersClient.Delete(ctx, containerName)
 
-	blobClient, err := NewWithBaseUri(fmt.Sprintf("https://%s.blob.%s", testData.StorageAccountName, *domainSuffix))
+	blobClient, err := NewWithBaseUri(*blobEndpoint)
 	if err != nil {
 		t.Fatalf("building client for environment: %+v", err)
 	}
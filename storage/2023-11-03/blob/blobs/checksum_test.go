@@ -0,0 +1,112 @@
+package blobs
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestTransactionalChecksums(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	blobClient, err := NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobClient.Client.SetAuthorizer(authorizer)
+
+	containerName := "container1"
+	if _, err := containersClient.Create(ctx, containerName, containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	for _, algorithm := range []checksum.Algorithm{checksum.MD5, checksum.CRC64} {
+		t.Logf("[DEBUG] Testing %s..", string(algorithm))
+
+		if _, err := blobClient.PutBlockBlob(ctx, containerName, "blob.txt", PutBlockBlobInput{Content: pointer.To([]byte("hello world")), ChecksumAlgorithm: pointer.To(algorithm)}); err != nil {
+			t.Fatalf("putting block blob: %+v", err)
+		}
+		blockId := base64.StdEncoding.EncodeToString([]byte("block1"))
+		if _, err := blobClient.PutBlock(ctx, containerName, "blocks.txt", PutBlockInput{BlockID: blockId, Content: []byte("hello"), ChecksumAlgorithm: pointer.To(algorithm)}); err != nil {
+			t.Fatalf("putting block: %+v", err)
+		}
+		if _, err := blobClient.PutAppendBlob(ctx, containerName, "append.txt", PutAppendBlobInput{}); err != nil {
+			t.Fatalf("putting append blob: %+v", err)
+		}
+		if _, err := blobClient.AppendBlock(ctx, containerName, "append.txt", AppendBlockInput{Content: pointer.To([]byte("hello")), ChecksumAlgorithm: pointer.To(algorithm)}); err != nil {
+			t.Fatalf("appending block: %+v", err)
+		}
+		if _, err := blobClient.PutPageBlob(ctx, containerName, "page.vhd", PutPageBlobInput{BlobContentLengthBytes: 512}); err != nil {
+			t.Fatalf("putting page blob: %+v", err)
+		}
+		if _, err := blobClient.PutPageUpdate(ctx, containerName, "page.vhd", PutPageUpdateInput{StartByte: 0, EndByte: 511, Content: make([]byte, 512), ChecksumAlgorithm: pointer.To(algorithm)}); err != nil {
+			t.Fatalf("putting page: %+v", err)
+		}
+
+		blob, err := blobClient.Get(ctx, containerName, "blob.txt", GetInput{StartByte: pointer.To(int64(0)), EndByte: pointer.To(int64(4)), ChecksumAlgorithm: pointer.To(algorithm)})
+		if err != nil {
+			t.Fatalf("retrieving blob: %+v", err)
+		}
+		if string(*blob.Contents) != "hello" {
+			t.Fatalf("expected the contents to be %q but got %q", "hello", string(*blob.Contents))
+		}
+	}
+
+	if _, err := blobClient.Get(ctx, containerName, "blob.txt", GetInput{ChecksumAlgorithm: pointer.To(checksum.MD5)}); err == nil {
+		t.Fatalf("expected an error requesting a checksum without a range but didn't get one")
+	}
+	if _, err := blobClient.Get(ctx, containerName, "blob.txt", GetInput{StartByte: pointer.To(int64(0)), EndByte: pointer.To(int64(5 * 1024 * 1024)), ChecksumAlgorithm: pointer.To(checksum.MD5)}); err == nil {
+		t.Fatalf("expected an error requesting a checksum for a range larger than 4MB but didn't get one")
+	}
+}
+
+func TestTransactionalChecksumMismatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-ms-range-get-content-crc64") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// the checksum is for different content, as if the response was corrupted in transit
+		w.Header().Set("x-ms-content-crc64", checksum.CRC64.Compute([]byte("hello")))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte("jello"))
+	}))
+	defer server.Close()
+
+	blobClient, err := NewWithBaseUri(server.URL + "/devstoreaccount1")
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	_, err = blobClient.Get(ctx, "container1", "blob.txt", GetInput{StartByte: pointer.To(int64(0)), EndByte: pointer.To(int64(4)), ChecksumAlgorithm: pointer.To(checksum.CRC64)})
+	var mismatch *checksum.MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected a checksum.MismatchError but got: %+v", err)
+	}
+	if mismatch.Actual != checksum.CRC64.Compute([]byte("jello")) {
+		t.Fatalf("expected the actual checksum to be for the content returned but got %q", mismatch.Actual)
+	}
+}
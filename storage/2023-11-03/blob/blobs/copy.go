@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
@@ -28,6 +29,10 @@ type CopyInput struct {
 	// copy from another storage account.
 	CopySource string
 
+	// The Version ID of the Source Blob to copy
+	// If not specified the current version of the Source Blob is copied.
+	SourceVersionID *string
+
 	// The ID of the Lease
 	// Required if the destination blob has an active lease.
 	// The lease ID specified for this header must match the lease ID of the destination blob.
@@ -42,9 +47,15 @@ type CopyInput struct {
 	// Specify to perform the Copy Blob operation only if the lease ID matches the active lease ID of the source blob.
 	SourceLeaseID *string
 
-	// For page blobs on a premium account only. Specifies the tier to be set on the target blob
+	// Specifies the tier to be set on the target blob.
+	// When copying an archived blob this must be an online tier (Hot, Cool or Cold), and the copy remains
+	// pending until the target blob has been rehydrated into this tier.
 	AccessTier *AccessTier
 
+	// The priority with which an archived source blob should be rehydrated into the target blob - either High or Standard.
+	// If not specified the target blob is rehydrated using Standard priority.
+	RehydratePriority *RehydratePriority
+
 	// A user-defined name-value pair associated with the blob.
 	// If no name-value pairs are specified, the operation will copy the metadata from the source blob or
 	// file to the destination blob.
@@ -113,6 +124,9 @@ type CopyResponse struct {
 
 	CopyID     string
 	CopyStatus string
+
+	// The Version ID of the destination blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
 }
 
 // Copy copies a blob to a destination within the storage account asynchronously.
@@ -133,6 +147,10 @@ func (c Client) Copy(ctx context.Context, containerName, blobName string, input
 		return result, fmt.Errorf("`input.CopySource` cannot be an empty string")
 	}
 
+	if input.SourceVersionID != nil && *input.SourceVersionID == "" {
+		return result, fmt.Errorf("`input.SourceVersionID` should either be specified or nil, not an empty string")
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusAccepted,
@@ -151,7 +169,7 @@ func (c Client) Copy(ctx context.Context, containerName, blobName string, input
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Copy", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -159,6 +177,7 @@ func (c Client) Copy(ctx context.Context, containerName, blobName string, input
 			if resp.Header != nil {
 				result.CopyID = resp.Header.Get("x-ms-copy-id")
 				result.CopyStatus = resp.Header.Get("x-ms-copy-status")
+				result.VersionID = resp.Header.Get("x-ms-version-id")
 			}
 		}
 	}
@@ -176,7 +195,7 @@ type copyOptions struct {
 
 func (c copyOptions) ToHeaders() *client.Headers {
 	headers := &client.Headers{}
-	headers.Append("x-ms-copy-source", c.input.CopySource)
+	headers.Append("x-ms-copy-source", copySourceWithVersion(c.input.CopySource, c.input.SourceVersionID))
 
 	if c.input.LeaseID != nil {
 		headers.Append("x-ms-lease-id", *c.input.LeaseID)
@@ -190,6 +209,10 @@ func (c copyOptions) ToHeaders() *client.Headers {
 		headers.Append("x-ms-access-tier", string(*c.input.AccessTier))
 	}
 
+	if c.input.RehydratePriority != nil {
+		headers.Append("x-ms-rehydrate-priority", string(*c.input.RehydratePriority))
+	}
+
 	if c.input.IfMatch != nil {
 		headers.Append("If-Match", *c.input.IfMatch)
 	}
@@ -238,3 +261,15 @@ func (c copyOptions) ToOData() *odata.Query {
 func (c copyOptions) ToQuery() *client.QueryParams {
 	return nil
 }
+
+// copySourceWithVersion appends the `versionid` query parameter to the Copy Source when a Version ID is specified
+func copySourceWithVersion(copySource string, versionId *string) string {
+	if versionId == nil {
+		return copySource
+	}
+	separator := "?"
+	if strings.Contains(copySource, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%sversionid=%s", copySource, separator, url.QueryEscape(*versionId))
+}
@@ -63,7 +63,7 @@ func (c Client) AbortCopy(ctx context.Context, containerName, blobName string, i
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "AbortCopy", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -2,15 +2,52 @@ package blobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client/pollers"
 )
 
+type CopyAndWaitOptions struct {
+	// PollInterval is how often the status of the copy is checked. Defaults to 10 seconds.
+	PollInterval time.Duration
+
+	// Progress is optionally called each time the status of the copy is checked, with the number of bytes
+	// which have been copied and the total number of bytes to copy.
+	Progress func(bytesCopied, totalBytes int64)
+
+	// AbortOnCancel specifies whether the copy should be aborted when `ctx` is cancelled (or its deadline
+	// is exceeded) before the copy has completed.
+	AbortOnCancel bool
+}
+
+// CopyFailedError is returned when a copy finishes with a status of either `failed` or `aborted`.
+type CopyFailedError struct {
+	// CopyID is the ID of the copy operation.
+	CopyID string
+
+	// CopyStatus is the final status of the copy operation.
+	CopyStatus CopyStatus
+
+	// CopyStatusDescription describes why the copy operation failed.
+	CopyStatusDescription string
+}
+
+func (e *CopyFailedError) Error() string {
+	return fmt.Sprintf("copy %q finished with the status %q: %s", e.CopyID, string(e.CopyStatus), e.CopyStatusDescription)
+}
+
 // CopyAndWait copies a blob to a destination within the storage account and waits for it to finish copying.
 func (c Client) CopyAndWait(ctx context.Context, containerName, blobName string, input CopyInput) error {
-	if _, err := c.Copy(ctx, containerName, blobName, input); err != nil {
+	return c.CopyAndWaitWithOptions(ctx, containerName, blobName, input, CopyAndWaitOptions{})
+}
+
+// CopyAndWaitWithOptions copies a blob to a destination within the storage account and waits for it to finish
+// copying, using the specified options - returning a *CopyFailedError if the copy fails or is aborted.
+func (c Client) CopyAndWaitWithOptions(ctx context.Context, containerName, blobName string, input CopyInput, options CopyAndWaitOptions) error {
+	copyResp, err := c.Copy(ctx, containerName, blobName, input)
+	if err != nil {
 		return fmt.Errorf("error copying: %s", err)
 	}
 
@@ -19,8 +56,27 @@ func (c Client) CopyAndWait(ctx context.Context, containerName, blobName string,
 	}
 
 	pollerType := NewCopyAndWaitPoller(&c, containerName, blobName, getInput)
-	poller := pollers.NewPoller(pollerType, 10*time.Second, pollers.DefaultNumberOfDroppedConnectionsToAllow)
+	pollerType.pollInterval = options.PollInterval
+	pollerType.progress = options.Progress
+	poller := pollers.NewPoller(pollerType, pollerType.interval(), pollers.DefaultNumberOfDroppedConnectionsToAllow)
 	if err := poller.PollUntilDone(ctx); err != nil {
+		var copyFailed *CopyFailedError
+		if errors.As(err, &copyFailed) {
+			return copyFailed
+		}
+
+		if options.AbortOnCancel && ctx.Err() != nil {
+			abortCtx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+			defer cancel()
+			abortInput := AbortCopyInput{
+				CopyID:  copyResp.CopyID,
+				LeaseID: input.LeaseID,
+			}
+			if _, abortErr := c.AbortCopy(abortCtx, containerName, blobName, abortInput); abortErr != nil {
+				return fmt.Errorf("waiting for file to copy: %+v (aborting the copy: %+v)", err, abortErr)
+			}
+		}
+
 		return fmt.Errorf("waiting for file to copy: %+v", err)
 	}
 
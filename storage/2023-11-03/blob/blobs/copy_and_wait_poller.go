@@ -3,6 +3,7 @@ package blobs
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,6 +26,19 @@ type copyAndWaitPoller struct {
 	containerName      string
 	blobName           string
 	getPropertiesInput GetPropertiesInput
+
+	// pollInterval is how often the status of the copy is checked, defaulting to 10 seconds
+	pollInterval time.Duration
+
+	// progress is optionally called with the number of bytes copied each time the status is checked
+	progress func(bytesCopied, totalBytes int64)
+}
+
+func (p *copyAndWaitPoller) interval() time.Duration {
+	if p.pollInterval > 0 {
+		return p.pollInterval
+	}
+	return 10 * time.Second
 }
 
 func (p *copyAndWaitPoller) Poll(ctx context.Context) (*pollers.PollResult, error) {
@@ -33,16 +47,47 @@ func (p *copyAndWaitPoller) Poll(ctx context.Context) (*pollers.PollResult, erro
 		return nil, fmt.Errorf("retrieving properties (container: %s blob: %s) : %+v", p.containerName, p.blobName, err)
 	}
 
-	if strings.EqualFold(string(props.CopyStatus), string(Success)) {
+	if p.progress != nil {
+		if bytesCopied, totalBytes, ok := parseCopyProgress(props.CopyProgress); ok {
+			p.progress(bytesCopied, totalBytes)
+		}
+	}
+
+	switch {
+	case strings.EqualFold(string(props.CopyStatus), string(Success)):
 		return &pollers.PollResult{
 			Status:       pollers.PollingStatusSucceeded,
-			PollInterval: 10 * time.Second,
+			PollInterval: p.interval(),
 		}, nil
+
+	case strings.EqualFold(string(props.CopyStatus), string(Aborted)), strings.EqualFold(string(props.CopyStatus), string(Failed)):
+		return nil, &CopyFailedError{
+			CopyID:                props.CopyID,
+			CopyStatus:            CopyStatus(strings.ToLower(string(props.CopyStatus))),
+			CopyStatusDescription: props.CopyStatusDescription,
+		}
 	}
 
 	// Processing
 	return &pollers.PollResult{
 		Status:       pollers.PollingStatusInProgress,
-		PollInterval: 10 * time.Second,
+		PollInterval: p.interval(),
 	}, nil
 }
+
+// parseCopyProgress parses the `x-ms-copy-progress` header, which is in the format `{bytesCopied}/{totalBytes}`
+func parseCopyProgress(input string) (int64, int64, bool) {
+	bytesCopiedRaw, totalBytesRaw, ok := strings.Cut(input, "/")
+	if !ok {
+		return 0, 0, false
+	}
+	bytesCopied, err := strconv.ParseInt(bytesCopiedRaw, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	totalBytes, err := strconv.ParseInt(totalBytesRaw, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return bytesCopied, totalBytes, true
+}
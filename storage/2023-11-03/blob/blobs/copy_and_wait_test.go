@@ -0,0 +1,116 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// copyStatusServer simulates a pending copy, which progresses by one step each time its status is checked
+type copyStatusServer struct {
+	mu        sync.Mutex
+	statuses  []string
+	polls     int
+	abortedID string
+}
+
+func (s *copyStatusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPut && r.URL.Query().Get("comp") == "copy":
+		s.abortedID = r.URL.Query().Get("copyid")
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPut:
+		w.Header().Set("x-ms-copy-id", "copy1")
+		w.Header().Set("x-ms-copy-status", "pending")
+		w.WriteHeader(http.StatusAccepted)
+
+	case r.Method == http.MethodHead:
+		status := s.statuses[len(s.statuses)-1]
+		if s.polls < len(s.statuses) {
+			status = s.statuses[s.polls]
+		}
+		s.polls++
+		w.Header().Set("x-ms-copy-id", "copy1")
+		w.Header().Set("x-ms-copy-status", status)
+		w.Header().Set("x-ms-copy-progress", "512/1024")
+		if status == "failed" {
+			w.Header().Set("x-ms-copy-status-description", "500 InternalError \"the source could not be read\"")
+		}
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestCopyAndWaitFailed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	handler := &copyStatusServer{statuses: []string{"pending", "pending", "failed"}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	blobClient, err := NewWithBaseUri(server.URL + "/devstoreaccount1")
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	progress := make([]int64, 0)
+	options := CopyAndWaitOptions{
+		PollInterval: 10 * time.Millisecond,
+		Progress: func(bytesCopied, totalBytes int64) {
+			if totalBytes != 1024 {
+				t.Errorf("expected the total bytes to be 1024 but got %d", totalBytes)
+			}
+			progress = append(progress, bytesCopied)
+		},
+	}
+	err = blobClient.CopyAndWaitWithOptions(ctx, "container1", "blob.txt", CopyInput{CopySource: "https://example.com/blob.txt"}, options)
+	var copyFailed *CopyFailedError
+	if !errors.As(err, &copyFailed) {
+		t.Fatalf("expected a CopyFailedError but got: %+v", err)
+	}
+	if copyFailed.CopyStatus != Failed || copyFailed.CopyID != "copy1" || copyFailed.CopyStatusDescription == "" {
+		t.Fatalf("expected the CopyFailedError to contain the copy status and description but got %+v", *copyFailed)
+	}
+	if len(progress) != 3 {
+		t.Fatalf("expected progress to be reported for each of the 3 polls but got %d", len(progress))
+	}
+}
+
+func TestCopyAndWaitAbortOnCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	handler := &copyStatusServer{statuses: []string{"pending"}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	blobClient, err := NewWithBaseUri(server.URL + "/devstoreaccount1")
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	options := CopyAndWaitOptions{
+		PollInterval:  10 * time.Millisecond,
+		AbortOnCancel: true,
+	}
+	if err := blobClient.CopyAndWaitWithOptions(ctx, "container1", "blob.txt", CopyInput{CopySource: "https://example.com/blob.txt"}, options); err == nil {
+		t.Fatalf("expected an error when the context is done before the copy completes")
+	}
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	if handler.abortedID != "copy1" {
+		t.Fatalf("expected the copy %q to be aborted but got %q", "copy1", handler.abortedID)
+	}
+}
@@ -0,0 +1,124 @@
+package blobs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestCopyChangedPages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	blobClient := buildBlobClient(t, ctx, server)
+	containerName := "container1"
+	fileName := "disk.vhd"
+
+	putPage := func(page int64, value byte) {
+		content := bytes.Repeat([]byte{value}, 512)
+		if _, err := blobClient.PutPageUpdate(ctx, containerName, fileName, PutPageUpdateInput{StartByte: page * 512, EndByte: page*512 + 511, Content: content}); err != nil {
+			t.Fatalf("putting page %d: %+v", page, err)
+		}
+	}
+	snapshot := func() string {
+		result, err := blobClient.Snapshot(ctx, containerName, fileName, SnapshotInput{})
+		if err != nil {
+			t.Fatalf("snapshotting blob: %+v", err)
+		}
+		return result.SnapshotDateTime
+	}
+
+	t.Logf("[DEBUG] Taking the first Snapshot..")
+	if _, err := blobClient.PutPageBlob(ctx, containerName, fileName, PutPageBlobInput{BlobContentLengthBytes: 4096}); err != nil {
+		t.Fatalf("putting page blob: %+v", err)
+	}
+	putPage(0, 'a')
+	putPage(1, 'b')
+	putPage(5, 'c')
+	first := snapshot()
+
+	t.Logf("[DEBUG] Taking a full backup..")
+	for _, backup := range []string{"backup1.vhd", "backup2.vhd"} {
+		result, err := blobClient.CopyChangedPages(ctx, containerName, backup, CopyChangedPagesInput{
+			SourceContainerName: containerName,
+			SourceBlobName:      fileName,
+			Snapshot:            first,
+		})
+		if err != nil {
+			t.Fatalf("copying pages: %+v", err)
+		}
+		if result.BytesCopied != 1536 || result.BytesCleared != 0 {
+			t.Fatalf("expected 1536 bytes to be copied but got %+v", result)
+		}
+	}
+
+	t.Logf("[DEBUG] Taking the second Snapshot..")
+	putPage(0, 'a')
+	putPage(1, 'B')
+	putPage(6, 'd')
+	if _, err := blobClient.PutPageClear(ctx, containerName, fileName, PutPageClearInput{StartByte: 2560, EndByte: 3071}); err != nil {
+		t.Fatalf("clearing page: %+v", err)
+	}
+	second := snapshot()
+
+	t.Logf("[DEBUG] Retrieving the Changed Page Ranges..")
+	diff, err := blobClient.GetPageRanges(ctx, containerName, fileName, GetPageRangesInput{Snapshot: pointer.To(second), PrevSnapshot: pointer.To(first)})
+	if err != nil {
+		t.Fatalf("retrieving page ranges: %+v", err)
+	}
+	expectedPages := []PageRange{{Start: 512, End: 1023}, {Start: 3072, End: 3583}}
+	expectedClear := []PageRange{{Start: 2560, End: 3071}}
+	if !reflect.DeepEqual(diff.PageRanges, expectedPages) || !reflect.DeepEqual(diff.ClearRanges, expectedClear) {
+		t.Fatalf("expected the changed pages to be %+v and cleared pages to be %+v but got %+v and %+v", expectedPages, expectedClear, diff.PageRanges, diff.ClearRanges)
+	}
+
+	t.Logf("[DEBUG] Paging through the Changed Page Ranges..")
+	var marker *string
+	requests, ranges := 0, 0
+	for {
+		page, err := blobClient.GetPageRanges(ctx, containerName, fileName, GetPageRangesInput{Snapshot: pointer.To(second), PrevSnapshot: pointer.To(first), MaxResults: pointer.To(1), Marker: marker})
+		if err != nil {
+			t.Fatalf("retrieving page ranges: %+v", err)
+		}
+		requests++
+		ranges += len(page.PageRanges) + len(page.ClearRanges)
+		if page.NextMarker == "" {
+			break
+		}
+		marker = pointer.To(page.NextMarker)
+	}
+	if requests != 3 || ranges != 3 {
+		t.Fatalf("expected 3 ranges to be returned across 3 requests but got %d across %d", ranges, requests)
+	}
+
+	t.Logf("[DEBUG] Taking incremental backups..")
+	copySource := fmt.Sprintf("%s/%s/%s?snapshot=%s", server.BaseUri(), containerName, fileName, second)
+	for backup, source := range map[string]*string{"backup1.vhd": nil, "backup2.vhd": pointer.To(copySource)} {
+		result, err := blobClient.CopyChangedPages(ctx, containerName, backup, CopyChangedPagesInput{
+			SourceContainerName: containerName,
+			SourceBlobName:      fileName,
+			Snapshot:            second,
+			PrevSnapshot:        pointer.To(first),
+			CopySource:          source,
+		})
+		if err != nil {
+			t.Fatalf("copying pages to %q: %+v", backup, err)
+		}
+		if result.BytesCopied != 1024 || result.BytesCleared != 512 {
+			t.Fatalf("expected 1024 bytes to be copied and 512 to be cleared for %q but got %+v", backup, result)
+		}
+
+		expected, err := blobClient.Get(ctx, containerName, fileName, GetInput{Snapshot: pointer.To(second)})
+		if err != nil {
+			t.Fatalf("retrieving snapshot: %+v", err)
+		}
+		assertBlobContent(t, ctx, blobClient, containerName, backup, *expected.Contents)
+	}
+}
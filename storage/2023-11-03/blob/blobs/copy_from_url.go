@@ -0,0 +1,251 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/internal/metadata"
+)
+
+type CopyFromURLInput struct {
+	// The URL of the Source Blob, which can be up to 256 MiB in size.
+	// The Source Blob must either be public, be authenticated via a shared access signature,
+	// or be authenticated using an OAuth token specified in CopySourceAuthorization.
+	CopySource string
+
+	// The Version ID of the Source Blob to copy
+	// If not specified the current version of the Source Blob is copied.
+	SourceVersionID *string
+
+	// The value of the Authorization header used to access the Source Blob, in the format `Bearer {token}`.
+	// Only OAuth access tokens are supported.
+	CopySourceAuthorization *string
+
+	// The base64-encoded MD5 hash of the content of the Source Blob
+	// If specified, the Blob service confirms that the content read from the Source Blob matches this hash.
+	SourceContentMD5 *string
+
+	// The base64-encoded CRC64 checksum of the content of the Source Blob
+	// If specified, the Blob service confirms that the content read from the Source Blob matches this checksum.
+	SourceContentCRC64 *string
+
+	// The ID of the Lease
+	// Required if the destination blob has an active lease.
+	LeaseID *string
+
+	// The tier to be set on the destination blob
+	AccessTier *AccessTier
+
+	// The Blob Index Tags to set on the destination blob
+	// If not specified the tags are not copied from the Source Blob.
+	Tags map[string]string
+
+	// A user-defined name-value pair associated with the blob.
+	// If not specified the metadata is copied from the Source Blob.
+	MetaData map[string]string
+
+	// Specify an ETag value to copy the blob only if it matches the ETag of the destination blob.
+	IfMatch *string
+
+	// Specify an ETag value (or the wildcard character (*)) to copy the blob only if it doesn't match the
+	// ETag of the destination blob - or, when the wildcard character is specified, only if the destination blob doesn't exist.
+	IfNoneMatch *string
+
+	// Specify a DateTime value to copy the blob only if the destination blob has been modified since this date/time.
+	IfModifiedSince *string
+
+	// Specify a DateTime value to copy the blob only if the destination blob hasn't been modified since this date/time.
+	IfUnmodifiedSince *string
+
+	// Specify an ETag value to copy the blob only if it matches the ETag of the Source Blob.
+	SourceIfMatch *string
+
+	// Specify an ETag value to copy the blob only if it doesn't match the ETag of the Source Blob.
+	SourceIfNoneMatch *string
+
+	// Specify a DateTime value to copy the blob only if the Source Blob has been modified since this date/time.
+	SourceIfModifiedSince *string
+
+	// Specify a DateTime value to copy the blob only if the Source Blob hasn't been modified since this date/time.
+	SourceIfUnmodifiedSince *string
+
+	// The encryption scope to set for the request content.
+	EncryptionScope *string
+}
+
+type CopyFromURLResponse struct {
+	HttpResponse *http.Response
+
+	CopyID     string
+	CopyStatus CopyStatus
+
+	// The base64-encoded MD5 hash and CRC64 checksum of the content which was copied
+	ContentMD5   string
+	ContentCRC64 string
+
+	ETag         string
+	LastModified string
+
+	// The Version ID of the destination blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
+}
+
+// CopyFromURL copies a blob of up to 256 MiB to a destination within the storage account synchronously,
+// returning once the copy has completed.
+func (c Client) CopyFromURL(ctx context.Context, containerName, blobName string, input CopyFromURLInput) (result CopyFromURLResponse, err error) {
+	if containerName == "" {
+		return result, fmt.Errorf("`containerName` cannot be an empty string")
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		return result, fmt.Errorf("`containerName` must be a lower-cased string")
+	}
+
+	if blobName == "" {
+		return result, fmt.Errorf("`blobName` cannot be an empty string")
+	}
+
+	if input.CopySource == "" {
+		return result, fmt.Errorf("`input.CopySource` cannot be an empty string")
+	}
+
+	if input.SourceVersionID != nil && *input.SourceVersionID == "" {
+		return result, fmt.Errorf("`input.SourceVersionID` should either be specified or nil, not an empty string")
+	}
+
+	if err = validateCopySourceAuthorization(input.CopySourceAuthorization); err != nil {
+		return result, fmt.Errorf("`input.CopySourceAuthorization` is not valid: %+v", err)
+	}
+
+	if err = validateTags(input.Tags); err != nil {
+		return result, fmt.Errorf("`input.Tags` is not valid: %+v", err)
+	}
+
+	if err = metadata.Validate(input.MetaData); err != nil {
+		return result, fmt.Errorf("`input.MetaData` is not valid: %+v", err)
+	}
+
+	opts := client.RequestOptions{
+		ExpectedStatusCodes: []int{
+			http.StatusAccepted,
+		},
+		HttpMethod: http.MethodPut,
+		OptionsObject: copyFromURLOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "CopyFromURL", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.CopyID = resp.Header.Get("x-ms-copy-id")
+				result.CopyStatus = CopyStatus(resp.Header.Get("x-ms-copy-status"))
+				result.ContentMD5 = resp.Header.Get("Content-MD5")
+				result.ContentCRC64 = resp.Header.Get("x-ms-content-crc64")
+				result.ETag = resp.Header.Get("ETag")
+				result.LastModified = resp.Header.Get("Last-Modified")
+				result.VersionID = resp.Header.Get("x-ms-version-id")
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type copyFromURLOptions struct {
+	input CopyFromURLInput
+}
+
+func (c copyFromURLOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	headers.Append("x-ms-copy-source", copySourceWithVersion(c.input.CopySource, c.input.SourceVersionID))
+	headers.Append("x-ms-requires-sync", "true")
+
+	if c.input.CopySourceAuthorization != nil {
+		headers.Append("x-ms-copy-source-authorization", *c.input.CopySourceAuthorization)
+	}
+	if c.input.SourceContentMD5 != nil {
+		headers.Append("x-ms-source-content-md5", *c.input.SourceContentMD5)
+	}
+	if c.input.SourceContentCRC64 != nil {
+		headers.Append("x-ms-source-content-crc64", *c.input.SourceContentCRC64)
+	}
+	if c.input.LeaseID != nil {
+		headers.Append("x-ms-lease-id", *c.input.LeaseID)
+	}
+	if c.input.AccessTier != nil {
+		headers.Append("x-ms-access-tier", string(*c.input.AccessTier))
+	}
+	if c.input.IfMatch != nil {
+		headers.Append("If-Match", *c.input.IfMatch)
+	}
+	if c.input.IfNoneMatch != nil {
+		headers.Append("If-None-Match", *c.input.IfNoneMatch)
+	}
+	if c.input.IfModifiedSince != nil {
+		headers.Append("If-Modified-Since", *c.input.IfModifiedSince)
+	}
+	if c.input.IfUnmodifiedSince != nil {
+		headers.Append("If-Unmodified-Since", *c.input.IfUnmodifiedSince)
+	}
+	if c.input.SourceIfMatch != nil {
+		headers.Append("x-ms-source-if-match", *c.input.SourceIfMatch)
+	}
+	if c.input.SourceIfNoneMatch != nil {
+		headers.Append("x-ms-source-if-none-match", *c.input.SourceIfNoneMatch)
+	}
+	if c.input.SourceIfModifiedSince != nil {
+		headers.Append("x-ms-source-if-modified-since", *c.input.SourceIfModifiedSince)
+	}
+	if c.input.SourceIfUnmodifiedSince != nil {
+		headers.Append("x-ms-source-if-unmodified-since", *c.input.SourceIfUnmodifiedSince)
+	}
+	if c.input.EncryptionScope != nil {
+		headers.Append("x-ms-encryption-scope", *c.input.EncryptionScope)
+	}
+	appendTagsHeader(headers, c.input.Tags)
+
+	headers.Merge(metadata.SetMetaDataHeaders(c.input.MetaData))
+
+	return headers
+}
+
+func (c copyFromURLOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (c copyFromURLOptions) ToQuery() *client.QueryParams {
+	return nil
+}
+
+// validateCopySourceAuthorization confirms that the Authorization header for the Copy Source (if specified)
+// uses the Bearer scheme, which is the only scheme supported by the Blob service
+func validateCopySourceAuthorization(input *string) error {
+	if input == nil {
+		return nil
+	}
+	scheme, token, ok := strings.Cut(*input, " ")
+	if !ok || scheme != "Bearer" || token == "" {
+		return fmt.Errorf("expected the value to be in the format `Bearer {token}`")
+	}
+	return nil
+}
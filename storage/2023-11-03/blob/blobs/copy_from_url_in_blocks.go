@@ -0,0 +1,178 @@
+package blobs
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+)
+
+const (
+	// defaultCopyBlockSize is the default size of each Block copied by CopyFromURLInBlocks
+	defaultCopyBlockSize = 64 * 1024 * 1024
+
+	// maxCopyBlockSize is the largest Block which can be copied using Put Block From URL
+	maxCopyBlockSize = 4000 * 1024 * 1024
+
+	// maxCopyBlocks is the maximum number of Blocks within a Block Blob
+	maxCopyBlocks = 50000
+
+	// defaultCopyParallelism is the default number of Blocks copied concurrently by CopyFromURLInBlocks
+	defaultCopyParallelism = 8
+)
+
+type CopyFromURLInBlocksInput struct {
+	// The URL of the Source Blob (or File), which can be in another Storage Account.
+	// The Source must either be public, be authenticated via a shared access signature,
+	// or be authenticated using an OAuth token specified in CopySourceAuthorization.
+	CopySource string
+
+	// The size of the Source in bytes, which is used to determine the range of each Block.
+	SourceContentLength int64
+
+	// The value of the Authorization header used to access the Source, in the format `Bearer {token}`.
+	// Only OAuth access tokens are supported.
+	CopySourceAuthorization *string
+
+	// The size of each Block in bytes. Defaults to 64 MiB - or the smallest size which allows the Source
+	// to be copied within 50,000 Blocks, whichever is larger.
+	BlockSize int64
+
+	// The number of Blocks to copy concurrently. Defaults to 8.
+	Parallelism int
+
+	// The properties of the destination blob, which are set once all of the Blocks have been copied
+	ContentType         *string
+	LeaseID             *string
+	AccessTier          *AccessTier
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+	Tags                map[string]string
+	MetaData            map[string]string
+}
+
+// CopyFromURLInBlocks is a helper method which copies a blob of any size from a URL (for example, in another
+// Storage Account) by copying each Block in parallel using PutBlockFromURL, then committing these using PutBlockList
+func (c Client) CopyFromURLInBlocks(ctx context.Context, containerName, blobName string, input CopyFromURLInBlocksInput) error {
+	if input.CopySource == "" {
+		return fmt.Errorf("`input.CopySource` cannot be an empty string")
+	}
+	if input.SourceContentLength <= 0 {
+		return fmt.Errorf("`input.SourceContentLength` must be greater than 0")
+	}
+	if err := validateCopySourceAuthorization(input.CopySourceAuthorization); err != nil {
+		return fmt.Errorf("`input.CopySourceAuthorization` is not valid: %+v", err)
+	}
+	if err := validateTags(input.Tags); err != nil {
+		return fmt.Errorf("`input.Tags` is not valid: %+v", err)
+	}
+
+	blockSize := input.BlockSize
+	if blockSize == 0 {
+		blockSize = defaultCopyBlockSize
+		if minimum := (input.SourceContentLength + maxCopyBlocks - 1) / maxCopyBlocks; minimum > blockSize {
+			blockSize = minimum
+		}
+	}
+	if blockSize < 0 || blockSize > maxCopyBlockSize {
+		return fmt.Errorf("`input.BlockSize` must be between 1 and %d bytes but got %d", int64(maxCopyBlockSize), blockSize)
+	}
+	blocks := int((input.SourceContentLength + blockSize - 1) / blockSize)
+	if blocks > maxCopyBlocks {
+		return fmt.Errorf("copying %d bytes using a `input.BlockSize` of %d bytes would require %d blocks, but at most %d are supported", input.SourceContentLength, blockSize, blocks, maxCopyBlocks)
+	}
+
+	workerCount := input.Parallelism
+	if workerCount <= 0 {
+		workerCount = defaultCopyParallelism
+	}
+	if workerCount > blocks {
+		workerCount = blocks
+	}
+
+	copyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	blockIds := make([]BlockID, blocks)
+	for i := 0; i < blocks; i++ {
+		// each Block ID must be the same length, so these are zero-padded
+		blockIds[i] = BlockID{Value: base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", i)))}
+	}
+	jobs := make(chan int)
+	var (
+		waitGroup sync.WaitGroup
+		mu        sync.Mutex
+		copyErr   error
+	)
+	for i := 0; i < workerCount; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for block := range jobs {
+				c.Logger.Log(copyCtx, slog.LevelDebug, "copying block", "container", containerName, "blob", blobName, "block", block+1, "blocks", blocks)
+
+				startByte := int64(block) * blockSize
+				endByte := startByte + blockSize - 1
+				if endByte >= input.SourceContentLength {
+					endByte = input.SourceContentLength - 1
+				}
+				blockInput := PutBlockFromURLInput{
+					BlockID:                 blockIds[block].Value,
+					CopySource:              input.CopySource,
+					CopySourceAuthorization: input.CopySourceAuthorization,
+					Range:                   pointer.To(fmt.Sprintf("bytes=%d-%d", startByte, endByte)),
+					LeaseID:                 input.LeaseID,
+					EncryptionScope:         input.EncryptionScope,
+					CustomerProvidedKey:     input.CustomerProvidedKey,
+				}
+				if _, err := c.PutBlockFromURL(copyCtx, containerName, blobName, blockInput); err != nil {
+					mu.Lock()
+					if copyErr == nil {
+						copyErr = fmt.Errorf("copying block %d (bytes %d to %d): %+v", block+1, startByte, endByte, err)
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < blocks; i++ {
+		select {
+		case jobs <- i:
+		case <-copyCtx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	waitGroup.Wait()
+
+	if copyErr != nil {
+		return copyErr
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("copying blocks: %+v", err)
+	}
+
+	blockListInput := PutBlockListInput{
+		BlockList: BlockList{
+			LatestBlockIDs: blockIds,
+		},
+		ContentType:         input.ContentType,
+		LeaseID:             input.LeaseID,
+		AccessTier:          input.AccessTier,
+		EncryptionScope:     input.EncryptionScope,
+		CustomerProvidedKey: input.CustomerProvidedKey,
+		Tags:                input.Tags,
+		MetaData:            input.MetaData,
+	}
+	if _, err := c.PutBlockList(ctx, containerName, blobName, blockListInput); err != nil {
+		return fmt.Errorf("committing %d blocks: %+v", blocks, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package blobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestCopyFromURLAcrossAccounts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	key := make([]byte, 64)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("generating account key: %+v", err)
+	}
+	source := blobserver.NewServer("sourceaccount", base64.StdEncoding.EncodeToString(key))
+	source.BearerToken = "abc123"
+	defer source.Close()
+	destination := blobserver.New(t)
+
+	sourceClient := buildBlobClient(t, ctx, source)
+	destinationClient := buildBlobClient(t, ctx, destination)
+
+	containerName := "container1"
+	content := make([]byte, 10*1024+100)
+	if _, err := rand.Read(content); err != nil {
+		t.Fatalf("generating content: %+v", err)
+	}
+	sourceUri := fmt.Sprintf("%s/%s/source.bin", source.BaseUri(), containerName)
+	if _, err := sourceClient.PutBlockBlob(ctx, containerName, "source.bin", PutBlockBlobInput{Content: pointer.To(content), ContentType: pointer.To("application/x-test")}); err != nil {
+		t.Fatalf("putting source blob: %+v", err)
+	}
+	authorization := pointer.To(fmt.Sprintf("Bearer %s", source.BearerToken))
+
+	t.Logf("[DEBUG] Copying synchronously..")
+	copied, err := destinationClient.CopyFromURL(ctx, containerName, "copied.bin", CopyFromURLInput{
+		CopySource:              sourceUri,
+		CopySourceAuthorization: authorization,
+		SourceContentMD5:        pointer.To(checksum.MD5.Compute(content)),
+		AccessTier:              pointer.To(Cool),
+		Tags: map[string]string{
+			"project": "giovanni",
+			"env":     "test",
+		},
+	})
+	if err != nil {
+		t.Fatalf("copying from url: %+v", err)
+	}
+	if copied.CopyStatus != Success {
+		t.Fatalf("expected the copy status to be %q but got %q", string(Success), string(copied.CopyStatus))
+	}
+	if copied.ContentCRC64 != checksum.CRC64.Compute(content) {
+		t.Fatalf("expected the CRC64 of the copied content to be %q but got %q", checksum.CRC64.Compute(content), copied.ContentCRC64)
+	}
+	assertBlobContent(t, ctx, destinationClient, containerName, "copied.bin", content)
+	props, err := destinationClient.GetProperties(ctx, containerName, "copied.bin", GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.AccessTier != Cool || props.TagCount != 2 {
+		t.Fatalf("expected the copied blob to be in the %q tier with 2 tags but got %q with %d", string(Cool), string(props.AccessTier), props.TagCount)
+	}
+
+	t.Logf("[DEBUG] Copying with an incorrect MD5 or without Authorization should fail..")
+	if _, err := destinationClient.CopyFromURL(ctx, containerName, "copied.bin", CopyFromURLInput{CopySource: sourceUri, CopySourceAuthorization: authorization, SourceContentMD5: pointer.To(checksum.MD5.Compute([]byte("nope")))}); err == nil {
+		t.Fatalf("expected an error when the source MD5 doesn't match but didn't get one")
+	}
+	if _, err := destinationClient.CopyFromURL(ctx, containerName, "copied.bin", CopyFromURLInput{CopySource: sourceUri}); err == nil {
+		t.Fatalf("expected an error when the source isn't authorized but didn't get one")
+	}
+	if _, err := destinationClient.CopyFromURL(ctx, containerName, "copied.bin", CopyFromURLInput{CopySource: sourceUri, CopySourceAuthorization: pointer.To("SharedKey abc")}); err == nil {
+		t.Fatalf("expected an error when the source authorization doesn't use the Bearer scheme but didn't get one")
+	}
+
+	t.Logf("[DEBUG] Putting a Blob from a URL..")
+	put, err := destinationClient.PutBlobFromURL(ctx, containerName, "put.bin", PutBlobFromURLInput{
+		CopySource:              sourceUri,
+		CopySourceAuthorization: authorization,
+		SourceContentCRC64:      pointer.To(checksum.CRC64.Compute(content)),
+		Tags:                    map[string]string{"project": "giovanni"},
+	})
+	if err != nil {
+		t.Fatalf("putting blob from url: %+v", err)
+	}
+	if put.ContentMD5 != checksum.MD5.Compute(content) {
+		t.Fatalf("expected the MD5 of the content to be %q but got %q", checksum.MD5.Compute(content), put.ContentMD5)
+	}
+	assertBlobContent(t, ctx, destinationClient, containerName, "put.bin", content)
+	props, err = destinationClient.GetProperties(ctx, containerName, "put.bin", GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.ContentType != "application/x-test" || props.TagCount != 1 {
+		t.Fatalf("expected the source properties to be copied with 1 tag but got %q with %d", props.ContentType, props.TagCount)
+	}
+
+	t.Logf("[DEBUG] Copying in Blocks..")
+	err = destinationClient.CopyFromURLInBlocks(ctx, containerName, "blocks.bin", CopyFromURLInBlocksInput{
+		CopySource:              sourceUri,
+		CopySourceAuthorization: authorization,
+		SourceContentLength:     int64(len(content)),
+		BlockSize:               1024,
+		Parallelism:             4,
+		AccessTier:              pointer.To(Cool),
+		Tags:                    map[string]string{"project": "giovanni"},
+	})
+	if err != nil {
+		t.Fatalf("copying in blocks: %+v", err)
+	}
+	assertBlobContent(t, ctx, destinationClient, containerName, "blocks.bin", content)
+	blockList, err := destinationClient.GetBlockList(ctx, containerName, "blocks.bin", GetBlockListInput{BlockListType: Committed})
+	if err != nil {
+		t.Fatalf("retrieving block list: %+v", err)
+	}
+	if len(blockList.CommittedBlocks.Blocks) != 11 {
+		t.Fatalf("expected 11 blocks to be committed but got %d", len(blockList.CommittedBlocks.Blocks))
+	}
+	props, err = destinationClient.GetProperties(ctx, containerName, "blocks.bin", GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.AccessTier != Cool || props.TagCount != 1 {
+		t.Fatalf("expected the blob to be in the %q tier with 1 tag but got %q with %d", string(Cool), string(props.AccessTier), props.TagCount)
+	}
+
+	t.Logf("[DEBUG] Copying in Blocks without Authorization should fail..")
+	err = destinationClient.CopyFromURLInBlocks(ctx, containerName, "unauthorized.bin", CopyFromURLInBlocksInput{
+		CopySource:          sourceUri,
+		SourceContentLength: int64(len(content)),
+		BlockSize:           1024,
+	})
+	if err == nil {
+		t.Fatalf("expected an error when the source isn't authorized but didn't get one")
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	testData := []struct {
+		tags  map[string]string
+		valid bool
+	}{
+		{
+			tags:  nil,
+			valid: true,
+		},
+		{
+			tags:  map[string]string{"project": "giovanni", "path": "a/b:c=d_e-f.g+h"},
+			valid: true,
+		},
+		{
+			tags:  map[string]string{"": "empty"},
+			valid: false,
+		},
+		{
+			tags:  map[string]string{"project": "hello!"},
+			valid: false,
+		},
+		{
+			tags:  map[string]string{"1": "", "2": "", "3": "", "4": "", "5": "", "6": "", "7": "", "8": "", "9": "", "10": "", "11": ""},
+			valid: false,
+		},
+	}
+	for i, v := range testData {
+		t.Logf("[DEBUG] Testing %d", i)
+		err := validateTags(v.tags)
+		if v.valid && err != nil {
+			t.Fatalf("expected the tags to be valid but got: %+v", err)
+		}
+		if !v.valid && err == nil {
+			t.Fatalf("expected the tags to be invalid but didn't get an error")
+		}
+	}
+}
+
+// buildBlobClient returns a Client for the Server, with a Container named `container1`
+func buildBlobClient(t *testing.T, ctx context.Context, server *blobserver.Server) *Client {
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	if _, err := containersClient.Create(ctx, "container1", containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	blobClient, err := NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobClient.Client.SetAuthorizer(authorizer)
+	return blobClient
+}
+
+func assertBlobContent(t *testing.T, ctx context.Context, client *Client, containerName, blobName string, expected []byte) {
+	blob, err := client.Get(ctx, containerName, blobName, GetInput{})
+	if err != nil {
+		t.Fatalf("retrieving %q: %+v", blobName, err)
+	}
+	if blob.Contents == nil || !bytes.Equal(*blob.Contents, expected) {
+		t.Fatalf("expected the content of %q to match the source", blobName)
+	}
+}
@@ -0,0 +1,82 @@
+package blobs
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+)
+
+type EncryptionAlgorithm string
+
+var (
+	AES256 EncryptionAlgorithm = "AES256"
+)
+
+// CustomerProvidedKey is an encryption key which is provided with each request, rather than being managed
+// by Azure. Azure doesn't store this key, so the same key must be provided to read (or update) the Blob.
+type CustomerProvidedKey struct {
+	// The base64-encoded AES-256 encryption key
+	Key string
+
+	// The base64-encoded SHA-256 hash of the encryption key - when unset this is computed from the Key
+	KeySHA256 *string
+
+	// The algorithm used to encrypt the Blob - defaults to AES256
+	Algorithm *EncryptionAlgorithm
+}
+
+// NewCustomerProvidedKey returns a CustomerProvidedKey using the specified AES-256 encryption key
+func NewCustomerProvidedKey(key []byte) CustomerProvidedKey {
+	return CustomerProvidedKey{
+		Key: base64.StdEncoding.EncodeToString(key),
+	}
+}
+
+func (k *CustomerProvidedKey) validate() error {
+	if k == nil {
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(k.Key)
+	if err != nil {
+		return fmt.Errorf("`Key` must be base64-encoded: %+v", err)
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("`Key` must be a 256-bit key but got %d bits", len(key)*8)
+	}
+	hash := sha256.Sum256(key)
+	if k.KeySHA256 != nil && *k.KeySHA256 != base64.StdEncoding.EncodeToString(hash[:]) {
+		return fmt.Errorf("`KeySHA256` doesn't match the SHA-256 hash of `Key`")
+	}
+	if k.Algorithm != nil && *k.Algorithm != AES256 {
+		return fmt.Errorf("`Algorithm` must be %q but got %q", string(AES256), string(*k.Algorithm))
+	}
+
+	return nil
+}
+
+func (k *CustomerProvidedKey) keySHA256() string {
+	if k.KeySHA256 != nil {
+		return *k.KeySHA256
+	}
+	key, _ := base64.StdEncoding.DecodeString(k.Key)
+	hash := sha256.Sum256(key)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// appendHeaders appends the headers for the Customer-Provided Key to `headers` - this is a no-op when `k` is nil
+func (k *CustomerProvidedKey) appendHeaders(headers *client.Headers) {
+	if k == nil {
+		return
+	}
+
+	algorithm := AES256
+	if k.Algorithm != nil {
+		algorithm = *k.Algorithm
+	}
+	headers.Append("x-ms-encryption-key", k.Key)
+	headers.Append("x-ms-encryption-key-sha256", k.keySHA256())
+	headers.Append("x-ms-encryption-algorithm", string(algorithm))
+}
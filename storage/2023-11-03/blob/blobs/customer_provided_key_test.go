@@ -0,0 +1,166 @@
+package blobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestCustomerProvidedKeyValidate(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	hash := sha256.Sum256(key)
+	keySHA256 := base64.StdEncoding.EncodeToString(hash[:])
+
+	testData := []struct {
+		input *CustomerProvidedKey
+		valid bool
+	}{
+		{
+			input: nil,
+			valid: true,
+		},
+		{
+			input: pointer.To(NewCustomerProvidedKey(key)),
+			valid: true,
+		},
+		{
+			input: &CustomerProvidedKey{Key: base64.StdEncoding.EncodeToString(key), KeySHA256: pointer.To(keySHA256), Algorithm: pointer.To(AES256)},
+			valid: true,
+		},
+		{
+			input: &CustomerProvidedKey{Key: base64.StdEncoding.EncodeToString(key), KeySHA256: pointer.To("abc123")},
+			valid: false,
+		},
+		{
+			input: pointer.To(NewCustomerProvidedKey([]byte("too-short"))),
+			valid: false,
+		},
+		{
+			input: &CustomerProvidedKey{Key: "not-base64!"},
+			valid: false,
+		},
+	}
+	for i, v := range testData {
+		t.Logf("[DEBUG] Testing %d", i)
+		err := v.input.validate()
+		if v.valid && err != nil {
+			t.Fatalf("expected the key to be valid but got: %+v", err)
+		}
+		if !v.valid && err == nil {
+			t.Fatalf("expected the key to be invalid but didn't get an error")
+		}
+	}
+}
+
+func TestCustomerProvidedKeyLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	blobClient, err := NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobClient.Client.SetAuthorizer(authorizer)
+
+	containerName := "container1"
+	if _, err := containersClient.Create(ctx, containerName, containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	key := NewCustomerProvidedKey(bytes.Repeat([]byte{1}, 32))
+	otherKey := NewCustomerProvidedKey(bytes.Repeat([]byte{2}, 32))
+
+	t.Log("[DEBUG] Putting Block Blob using a Customer-Provided Key..")
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, "blob.txt", PutBlockBlobInput{Content: pointer.To([]byte("hello")), CustomerProvidedKey: &key}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+
+	t.Log("[DEBUG] Retrieving Blob without the Customer-Provided Key..")
+	if _, err := blobClient.Get(ctx, containerName, "blob.txt", GetInput{}); err == nil {
+		t.Fatalf("expected an error retrieving the blob without the key but didn't get one")
+	}
+	if _, err := blobClient.Get(ctx, containerName, "blob.txt", GetInput{CustomerProvidedKey: &otherKey}); err == nil {
+		t.Fatalf("expected an error retrieving the blob using a different key but didn't get one")
+	}
+
+	t.Log("[DEBUG] Retrieving Blob using the Customer-Provided Key..")
+	blob, err := blobClient.Get(ctx, containerName, "blob.txt", GetInput{CustomerProvidedKey: &key})
+	if err != nil {
+		t.Fatalf("retrieving blob: %+v", err)
+	}
+	if string(*blob.Contents) != "hello" {
+		t.Fatalf("expected the contents to be %q but got %q", "hello", string(*blob.Contents))
+	}
+
+	t.Log("[DEBUG] Setting MetaData using the Customer-Provided Key..")
+	if _, err := blobClient.SetMetaData(ctx, containerName, "blob.txt", SetMetaDataInput{MetaData: map[string]string{"hello": "world"}, CustomerProvidedKey: &key}); err != nil {
+		t.Fatalf("setting metadata: %+v", err)
+	}
+
+	t.Log("[DEBUG] Retrieving Properties using the Customer-Provided Key..")
+	props, err := blobClient.GetProperties(ctx, containerName, "blob.txt", GetPropertiesInput{CustomerProvidedKey: &key})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.EncryptionKeySHA256 != key.keySHA256() {
+		t.Fatalf("expected the SHA-256 hash of the key to be %q but got %q", key.keySHA256(), props.EncryptionKeySHA256)
+	}
+	if props.MetaData["hello"] != "world" {
+		t.Fatalf("expected the MetaData to be updated but got %+v", props.MetaData)
+	}
+
+	t.Log("[DEBUG] Snapshotting Blob using the Customer-Provided Key..")
+	snapshot, err := blobClient.Snapshot(ctx, containerName, "blob.txt", SnapshotInput{CustomerProvidedKey: &key})
+	if err != nil {
+		t.Fatalf("snapshotting blob: %+v", err)
+	}
+	if _, err := blobClient.GetSnapshotProperties(ctx, containerName, "blob.txt", GetSnapshotPropertiesInput{SnapshotID: snapshot.SnapshotDateTime, CustomerProvidedKey: &key}); err != nil {
+		t.Fatalf("retrieving snapshot properties: %+v", err)
+	}
+
+	t.Log("[DEBUG] Putting Blocks using a Customer-Provided Key..")
+	blockId := base64.StdEncoding.EncodeToString([]byte("block1"))
+	if _, err := blobClient.PutBlock(ctx, containerName, "blocks.txt", PutBlockInput{BlockID: blockId, Content: []byte("blocks"), CustomerProvidedKey: &key}); err != nil {
+		t.Fatalf("putting block: %+v", err)
+	}
+	blockList := BlockList{LatestBlockIDs: []BlockID{{Value: blockId}}}
+	if _, err := blobClient.PutBlockList(ctx, containerName, "blocks.txt", PutBlockListInput{BlockList: blockList, CustomerProvidedKey: &key}); err != nil {
+		t.Fatalf("putting block list: %+v", err)
+	}
+	if _, err := blobClient.GetProperties(ctx, containerName, "blocks.txt", GetPropertiesInput{}); err == nil {
+		t.Fatalf("expected an error retrieving the properties without the key but didn't get one")
+	}
+
+	t.Log("[DEBUG] Appending Blocks using a Customer-Provided Key..")
+	if _, err := blobClient.PutAppendBlob(ctx, containerName, "append.txt", PutAppendBlobInput{CustomerProvidedKey: &key}); err != nil {
+		t.Fatalf("putting append blob: %+v", err)
+	}
+	if _, err := blobClient.AppendBlock(ctx, containerName, "append.txt", AppendBlockInput{Content: pointer.To([]byte("hello")), CustomerProvidedKey: &key}); err != nil {
+		t.Fatalf("appending block: %+v", err)
+	}
+	if _, err := blobClient.AppendBlock(ctx, containerName, "append.txt", AppendBlockInput{Content: pointer.To([]byte("hello"))}); err == nil {
+		t.Fatalf("expected an error appending a block without the key but didn't get one")
+	}
+
+	t.Log("[DEBUG] Using an invalid Customer-Provided Key..")
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, "invalid.txt", PutBlockBlobInput{CustomerProvidedKey: pointer.To(NewCustomerProvidedKey([]byte("too-short")))}); err == nil {
+		t.Fatalf("expected an error using an invalid key but didn't get one")
+	}
+}
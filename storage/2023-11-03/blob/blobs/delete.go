@@ -18,6 +18,10 @@ type DeleteInput struct {
 	// The ID of the Lease
 	// This must be specified if a Lease is present on the Blob, else a 403 is returned
 	LeaseID *string
+
+	// The Version ID of the blob to delete
+	// If specified only this (previous) version of the blob is deleted.
+	VersionID *string
 }
 
 type DeleteResponse struct {
@@ -38,6 +42,10 @@ func (c Client) Delete(ctx context.Context, containerName, blobName string, inpu
 		return result, fmt.Errorf("`blobName` cannot be an empty string")
 	}
 
+	if input.VersionID != nil && *input.VersionID == "" {
+		return result, fmt.Errorf("`input.VersionID` should either be specified or nil, not an empty string")
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusAccepted,
@@ -56,7 +64,7 @@ func (c Client) Delete(ctx context.Context, containerName, blobName string, inpu
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Delete", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -91,5 +99,9 @@ func (d deleteOptions) ToOData() *odata.Query {
 }
 
 func (d deleteOptions) ToQuery() *client.QueryParams {
-	return nil
+	out := &client.QueryParams{}
+	if d.input.VersionID != nil {
+		out.Append("versionid", *d.input.VersionID)
+	}
+	return out
 }
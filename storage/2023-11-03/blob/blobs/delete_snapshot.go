@@ -63,7 +63,7 @@ func (c Client) DeleteSnapshot(ctx context.Context, containerName, blobName stri
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "DeleteSnapshot", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
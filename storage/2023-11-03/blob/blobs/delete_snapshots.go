@@ -55,7 +55,7 @@ func (c Client) DeleteSnapshots(ctx context.Context, containerName, blobName str
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "DeleteSnapshots", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -9,12 +9,25 @@ import (
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
 )
 
 type GetInput struct {
-	LeaseID   *string
-	StartByte *int64
-	EndByte   *int64
+	LeaseID             *string
+	StartByte           *int64
+	EndByte             *int64
+	CustomerProvidedKey *CustomerProvidedKey
+
+	// Snapshot optionally specifies the Snapshot of the blob to retrieve, rather than the base blob.
+	Snapshot *string
+
+	// VersionID optionally specifies the Version of the blob to retrieve, rather than the current version.
+	VersionID *string
+
+	// ChecksumAlgorithm optionally requests a checksum for the range specified in StartByte and EndByte
+	// (which can be at most 4MB), which is verified against the contents that are returned - a
+	// *checksum.MismatchError is returned if these don't match.
+	ChecksumAlgorithm *checksum.Algorithm
 }
 
 type GetResponse struct {
@@ -41,10 +54,38 @@ func (c Client) Get(ctx context.Context, containerName, blobName string, input G
 		return result, fmt.Errorf("`input.LeaseID` should either be specified or nil, not an empty string")
 	}
 
+	if input.VersionID != nil && *input.VersionID == "" {
+		return result, fmt.Errorf("`input.VersionID` should either be specified or nil, not an empty string")
+	}
+
+	if input.Snapshot != nil && *input.Snapshot == "" {
+		return result, fmt.Errorf("`input.Snapshot` should either be specified or nil, not an empty string")
+	}
+
+	if input.Snapshot != nil && input.VersionID != nil {
+		return result, fmt.Errorf("only one of `input.Snapshot` and `input.VersionID` can be specified")
+	}
+
 	if (input.StartByte != nil && input.EndByte == nil) || input.StartByte == nil && input.EndByte != nil {
 		return result, fmt.Errorf("`input.StartByte` and `input.EndByte` must both be specified, or both be nil")
 	}
 
+	if err := input.CustomerProvidedKey.validate(); err != nil {
+		return result, fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+	}
+
+	if input.ChecksumAlgorithm != nil {
+		if err := input.ChecksumAlgorithm.Validate(); err != nil {
+			return result, fmt.Errorf("`input.ChecksumAlgorithm` is not valid: %+v", err)
+		}
+		if input.StartByte == nil || input.EndByte == nil {
+			return result, fmt.Errorf("`input.StartByte` and `input.EndByte` must be specified when `input.ChecksumAlgorithm` is specified")
+		}
+		if err := checksum.ValidateRange(*input.EndByte - *input.StartByte + 1); err != nil {
+			return result, fmt.Errorf("`input.ChecksumAlgorithm` is not valid: %+v", err)
+		}
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusOK,
@@ -64,7 +105,7 @@ func (c Client) Get(ctx context.Context, containerName, blobName string, input G
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Get", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -76,6 +117,12 @@ func (c Client) Get(ctx context.Context, containerName, blobName string, input G
 					return result, fmt.Errorf("could not parse response body")
 				}
 
+				if input.ChecksumAlgorithm != nil {
+					if err := input.ChecksumAlgorithm.Verify(resp.Header, respBody); err != nil {
+						return result, err
+					}
+				}
+
 				result.Contents = &respBody
 			}
 		}
@@ -97,6 +144,10 @@ func (g getOptions) ToHeaders() *client.Headers {
 	if g.input.StartByte != nil && g.input.EndByte != nil {
 		headers.Append("x-ms-range", fmt.Sprintf("bytes=%d-%d", *g.input.StartByte, *g.input.EndByte))
 	}
+	g.input.CustomerProvidedKey.appendHeaders(headers)
+	if g.input.ChecksumAlgorithm != nil {
+		headers.Append(g.input.ChecksumAlgorithm.RangeHeader(), "true")
+	}
 	return headers
 
 }
@@ -106,5 +157,12 @@ func (g getOptions) ToOData() *odata.Query {
 }
 
 func (g getOptions) ToQuery() *client.QueryParams {
-	return nil
+	out := &client.QueryParams{}
+	if g.input.Snapshot != nil {
+		out.Append("snapshot", *g.input.Snapshot)
+	}
+	if g.input.VersionID != nil {
+		out.Append("versionid", *g.input.VersionID)
+	}
+	return out
 }
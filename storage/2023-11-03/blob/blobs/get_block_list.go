@@ -14,6 +14,9 @@ import (
 type GetBlockListInput struct {
 	BlockListType BlockListType
 	LeaseID       *string
+
+	// Snapshot optionally specifies the Snapshot of the blob whose block list should be retrieved
+	Snapshot *string
 }
 
 type GetBlockListResponse struct {
@@ -49,6 +52,10 @@ func (c Client) GetBlockList(ctx context.Context, containerName, blobName string
 		return result, fmt.Errorf("`blobName` cannot be an empty string")
 	}
 
+	if input.Snapshot != nil && *input.Snapshot == "" {
+		return result, fmt.Errorf("`input.Snapshot` should either be specified or nil, not an empty string")
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusOK,
@@ -67,7 +74,7 @@ func (c Client) GetBlockList(ctx context.Context, containerName, blobName string
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetBlockList", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -123,5 +130,8 @@ func (g getBlockListOptions) ToQuery() *client.QueryParams {
 	out := &client.QueryParams{}
 	out.Append("blocklisttype", string(g.input.BlockListType))
 	out.Append("comp", "blocklist")
+	if g.input.Snapshot != nil {
+		out.Append("snapshot", *g.input.Snapshot)
+	}
 	return out
 }
@@ -16,6 +16,23 @@ type GetPageRangesInput struct {
 
 	StartByte *int64
 	EndByte   *int64
+
+	// Snapshot optionally specifies the Snapshot of the blob whose page ranges should be retrieved
+	Snapshot *string
+
+	// PrevSnapshot optionally specifies a previous Snapshot of the blob, in which case only the pages which
+	// have changed since this Snapshot are returned in PageRanges - and the pages which have been cleared in ClearRanges
+	PrevSnapshot *string
+
+	// PrevSnapshotURL optionally specifies the URL of a previous Snapshot of the blob, as with PrevSnapshot.
+	// This is only supported for Managed Disks.
+	PrevSnapshotURL *string
+
+	// Marker is the NextMarker returned from a previous request, to retrieve the next page of results
+	Marker *string
+
+	// MaxResults is the maximum number of ranges to return
+	MaxResults *int
 }
 
 type GetPageRangesResponse struct {
@@ -31,6 +48,12 @@ type GetPageRangesResponse struct {
 	ETag string
 
 	PageRanges []PageRange `xml:"PageRange"`
+
+	// The ranges which have been cleared since the PrevSnapshot (or PrevSnapshotURL), if specified
+	ClearRanges []PageRange `xml:"ClearRange"`
+
+	// NextMarker is returned when there are further results, which can be retrieved by specifying this as the Marker
+	NextMarker string `xml:"NextMarker"`
 }
 
 type PageRange struct {
@@ -41,7 +64,8 @@ type PageRange struct {
 	End int64 `xml:"End"`
 }
 
-// GetPageRanges returns the list of valid page ranges for a page blob or snapshot of a page blob.
+// GetPageRanges returns the list of valid page ranges for a page blob or snapshot of a page blob - or, when a
+// previous Snapshot is specified, the page ranges which have changed (or been cleared) since that Snapshot.
 func (c Client) GetPageRanges(ctx context.Context, containerName, blobName string, input GetPageRangesInput) (result GetPageRangesResponse, err error) {
 	if containerName == "" {
 		return result, fmt.Errorf("`containerName` cannot be an empty string")
@@ -59,6 +83,26 @@ func (c Client) GetPageRanges(ctx context.Context, containerName, blobName strin
 		return result, fmt.Errorf("`input.StartByte` and `input.EndByte` must both be specified, or both be nil")
 	}
 
+	if input.Snapshot != nil && *input.Snapshot == "" {
+		return result, fmt.Errorf("`input.Snapshot` should either be specified or nil, not an empty string")
+	}
+
+	if input.PrevSnapshot != nil && *input.PrevSnapshot == "" {
+		return result, fmt.Errorf("`input.PrevSnapshot` should either be specified or nil, not an empty string")
+	}
+
+	if input.PrevSnapshotURL != nil && *input.PrevSnapshotURL == "" {
+		return result, fmt.Errorf("`input.PrevSnapshotURL` should either be specified or nil, not an empty string")
+	}
+
+	if input.PrevSnapshot != nil && input.PrevSnapshotURL != nil {
+		return result, fmt.Errorf("only one of `input.PrevSnapshot` and `input.PrevSnapshotURL` can be specified")
+	}
+
+	if input.MaxResults != nil && *input.MaxResults <= 0 {
+		return result, fmt.Errorf("`input.MaxResults` must be greater than 0")
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusOK,
@@ -77,7 +121,7 @@ func (c Client) GetPageRanges(ctx context.Context, containerName, blobName strin
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetPageRanges", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -128,6 +172,10 @@ func (g getPageRangesOptions) ToHeaders() *client.Headers {
 		headers.Append("x-ms-range", fmt.Sprintf("bytes=%d-%d", *g.input.StartByte, *g.input.EndByte))
 	}
 
+	if g.input.PrevSnapshotURL != nil {
+		headers.Append("x-ms-previous-snapshot-url", *g.input.PrevSnapshotURL)
+	}
+
 	return headers
 }
 
@@ -138,5 +186,17 @@ func (g getPageRangesOptions) ToOData() *odata.Query {
 func (g getPageRangesOptions) ToQuery() *client.QueryParams {
 	out := &client.QueryParams{}
 	out.Append("comp", "pagelist")
+	if g.input.Snapshot != nil {
+		out.Append("snapshot", *g.input.Snapshot)
+	}
+	if g.input.PrevSnapshot != nil {
+		out.Append("prevsnapshot", *g.input.PrevSnapshot)
+	}
+	if g.input.Marker != nil {
+		out.Append("marker", *g.input.Marker)
+	}
+	if g.input.MaxResults != nil {
+		out.Append("maxresults", strconv.Itoa(*g.input.MaxResults))
+	}
 	return out
 }
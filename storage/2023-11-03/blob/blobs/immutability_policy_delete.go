@@ -0,0 +1,112 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+type DeleteImmutabilityPolicyInput struct {
+	// The Snapshot of the blob from which the Immutability Policy should be deleted
+	// If not specified the policy is deleted from the base blob.
+	Snapshot *string
+
+	// The Version ID of the blob from which the Immutability Policy should be deleted
+	// If not specified the policy is deleted from the current version of the blob.
+	VersionID *string
+}
+
+type DeleteImmutabilityPolicyResponse struct {
+	HttpResponse *http.Response
+}
+
+// DeleteImmutabilityPolicy deletes the Unlocked Immutability Policy from a blob (or a version of a blob).
+// A Locked Immutability Policy can't be deleted.
+func (c Client) DeleteImmutabilityPolicy(ctx context.Context, containerName, blobName string, input DeleteImmutabilityPolicyInput) (result DeleteImmutabilityPolicyResponse, err error) {
+	if containerName == "" {
+		err = fmt.Errorf("`containerName` cannot be an empty string")
+		return
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		err = fmt.Errorf("`containerName` must be a lower-cased string")
+		return
+	}
+
+	if blobName == "" {
+		err = fmt.Errorf("`blobName` cannot be an empty string")
+		return
+	}
+
+	if input.VersionID != nil && *input.VersionID == "" {
+		err = fmt.Errorf("`input.VersionID` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if input.Snapshot != nil && *input.Snapshot == "" {
+		err = fmt.Errorf("`input.Snapshot` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if input.Snapshot != nil && input.VersionID != nil {
+		err = fmt.Errorf("only one of `input.Snapshot` and `input.VersionID` can be specified")
+		return
+	}
+
+	opts := client.RequestOptions{
+		ExpectedStatusCodes: []int{
+			http.StatusOK,
+		},
+		HttpMethod: http.MethodDelete,
+		OptionsObject: deleteImmutabilityPolicyOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "DeleteImmutabilityPolicy", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type deleteImmutabilityPolicyOptions struct {
+	input DeleteImmutabilityPolicyInput
+}
+
+func (d deleteImmutabilityPolicyOptions) ToHeaders() *client.Headers {
+	return nil
+}
+
+func (d deleteImmutabilityPolicyOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (d deleteImmutabilityPolicyOptions) ToQuery() *client.QueryParams {
+	out := &client.QueryParams{}
+	out.Append("comp", "immutabilityPolicies")
+	if d.input.Snapshot != nil {
+		out.Append("snapshot", *d.input.Snapshot)
+	}
+	if d.input.VersionID != nil {
+		out.Append("versionid", *d.input.VersionID)
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+type SetImmutabilityPolicyInput struct {
+	// The time until which the blob (version) can't be modified or deleted
+	ExpiresOn time.Time
+
+	// The mode of the Immutability Policy. An Unlocked policy can be modified or deleted, whereas a Locked
+	// policy can only have its expiry extended. Defaults to Unlocked.
+	Mode *ImmutabilityPolicyMode
+
+	// The Snapshot of the blob on which the Immutability Policy should be set
+	// If not specified the policy is set on the base blob.
+	Snapshot *string
+
+	// The Version ID of the blob on which the Immutability Policy should be set
+	// If not specified the policy is set on the current version of the blob.
+	VersionID *string
+
+	IfUnmodifiedSince *string
+}
+
+type SetImmutabilityPolicyResponse struct {
+	HttpResponse *http.Response
+
+	// The time until which the blob (version) can't be modified or deleted, in RFC 1123 format
+	ExpiresOn string
+
+	Mode ImmutabilityPolicyMode
+}
+
+// SetImmutabilityPolicy sets a time-based retention policy on a blob (or a version of a blob), which prevents it
+// from being modified or deleted until the policy expires. Version-level immutability must be enabled for the Container.
+func (c Client) SetImmutabilityPolicy(ctx context.Context, containerName, blobName string, input SetImmutabilityPolicyInput) (result SetImmutabilityPolicyResponse, err error) {
+	if containerName == "" {
+		err = fmt.Errorf("`containerName` cannot be an empty string")
+		return
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		err = fmt.Errorf("`containerName` must be a lower-cased string")
+		return
+	}
+
+	if blobName == "" {
+		err = fmt.Errorf("`blobName` cannot be an empty string")
+		return
+	}
+
+	if input.ExpiresOn.IsZero() {
+		err = fmt.Errorf("`input.ExpiresOn` must be specified")
+		return
+	}
+
+	if input.Mode != nil && *input.Mode != LockedImmutabilityPolicy && *input.Mode != UnlockedImmutabilityPolicy {
+		err = fmt.Errorf("`input.Mode` must be either %q or %q but got %q", string(LockedImmutabilityPolicy), string(UnlockedImmutabilityPolicy), string(*input.Mode))
+		return
+	}
+
+	if input.VersionID != nil && *input.VersionID == "" {
+		err = fmt.Errorf("`input.VersionID` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if input.Snapshot != nil && *input.Snapshot == "" {
+		err = fmt.Errorf("`input.Snapshot` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if input.Snapshot != nil && input.VersionID != nil {
+		err = fmt.Errorf("only one of `input.Snapshot` and `input.VersionID` can be specified")
+		return
+	}
+
+	opts := client.RequestOptions{
+		ExpectedStatusCodes: []int{
+			http.StatusOK,
+		},
+		HttpMethod: http.MethodPut,
+		OptionsObject: setImmutabilityPolicyOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "SetImmutabilityPolicy", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.ExpiresOn = resp.Header.Get("x-ms-immutability-policy-until-date")
+				result.Mode = ImmutabilityPolicyMode(resp.Header.Get("x-ms-immutability-policy-mode"))
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type setImmutabilityPolicyOptions struct {
+	input SetImmutabilityPolicyInput
+}
+
+func (s setImmutabilityPolicyOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	headers.Append("x-ms-immutability-policy-until-date", s.input.ExpiresOn.UTC().Format(http.TimeFormat))
+	mode := UnlockedImmutabilityPolicy
+	if s.input.Mode != nil {
+		mode = *s.input.Mode
+	}
+	headers.Append("x-ms-immutability-policy-mode", string(mode))
+	if s.input.IfUnmodifiedSince != nil {
+		headers.Append("If-Unmodified-Since", *s.input.IfUnmodifiedSince)
+	}
+	return headers
+}
+
+func (s setImmutabilityPolicyOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (s setImmutabilityPolicyOptions) ToQuery() *client.QueryParams {
+	out := &client.QueryParams{}
+	out.Append("comp", "immutabilityPolicies")
+	if s.input.Snapshot != nil {
+		out.Append("snapshot", *s.input.Snapshot)
+	}
+	if s.input.VersionID != nil {
+		out.Append("versionid", *s.input.VersionID)
+	}
+	return out
+}
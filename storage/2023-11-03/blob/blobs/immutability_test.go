@@ -0,0 +1,121 @@
+package blobs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestImmutabilityPolicyAndLegalHold(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	blobClient := buildBlobClient(t, ctx, server)
+	containerName := "container1"
+	fileName := "audit.log"
+
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, fileName, PutBlockBlobInput{Content: pointer.To([]byte("audit"))}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Setting an Unlocked Immutability Policy..")
+	expiresOn := time.Now().Add(1 * time.Hour).UTC().Truncate(time.Second)
+	policy, err := blobClient.SetImmutabilityPolicy(ctx, containerName, fileName, SetImmutabilityPolicyInput{ExpiresOn: expiresOn})
+	if err != nil {
+		t.Fatalf("setting immutability policy: %+v", err)
+	}
+	if policy.Mode != UnlockedImmutabilityPolicy || policy.ExpiresOn != expiresOn.Format(http.TimeFormat) {
+		t.Fatalf("expected an %q policy expiring at %q but got %q expiring at %q", string(UnlockedImmutabilityPolicy), expiresOn.Format(http.TimeFormat), string(policy.Mode), policy.ExpiresOn)
+	}
+	props, err := blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.ImmutabilityPolicyMode != UnlockedImmutabilityPolicy || props.ImmutabilityPolicyExpiresOn != policy.ExpiresOn {
+		t.Fatalf("expected the properties to contain the immutability policy but got %q expiring at %q", string(props.ImmutabilityPolicyMode), props.ImmutabilityPolicyExpiresOn)
+	}
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, fileName, PutBlockBlobInput{Content: pointer.To([]byte("changed"))}); err == nil {
+		t.Fatalf("expected an error overwriting an immutable blob but didn't get one")
+	}
+	if _, err := blobClient.Delete(ctx, containerName, fileName, DeleteInput{}); err == nil {
+		t.Fatalf("expected an error deleting an immutable blob but didn't get one")
+	}
+	if _, err := blobClient.DeleteImmutabilityPolicy(ctx, containerName, fileName, DeleteImmutabilityPolicyInput{}); err != nil {
+		t.Fatalf("deleting immutability policy: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Setting a Legal Hold..")
+	legalHold, err := blobClient.SetLegalHold(ctx, containerName, fileName, SetLegalHoldInput{LegalHold: true})
+	if err != nil {
+		t.Fatalf("setting legal hold: %+v", err)
+	}
+	if !legalHold.LegalHold {
+		t.Fatalf("expected the legal hold to be set")
+	}
+	if _, err := blobClient.SetMetaData(ctx, containerName, fileName, SetMetaDataInput{MetaData: map[string]string{"hello": "world"}}); err == nil {
+		t.Fatalf("expected an error modifying a blob with a legal hold but didn't get one")
+	}
+
+	t.Logf("[DEBUG] Setting a Locked Immutability Policy..")
+	if _, err := blobClient.SetImmutabilityPolicy(ctx, containerName, fileName, SetImmutabilityPolicyInput{ExpiresOn: expiresOn, Mode: pointer.To(LockedImmutabilityPolicy)}); err != nil {
+		t.Fatalf("setting immutability policy: %+v", err)
+	}
+	if _, err := blobClient.DeleteImmutabilityPolicy(ctx, containerName, fileName, DeleteImmutabilityPolicyInput{}); err == nil {
+		t.Fatalf("expected an error deleting a locked immutability policy but didn't get one")
+	}
+	if _, err := blobClient.SetImmutabilityPolicy(ctx, containerName, fileName, SetImmutabilityPolicyInput{ExpiresOn: expiresOn.Add(-1 * time.Minute), Mode: pointer.To(LockedImmutabilityPolicy)}); err == nil {
+		t.Fatalf("expected an error shortening a locked immutability policy but didn't get one")
+	}
+	expiresOn = expiresOn.Add(24 * time.Hour)
+	if _, err := blobClient.SetImmutabilityPolicy(ctx, containerName, fileName, SetImmutabilityPolicyInput{ExpiresOn: expiresOn, Mode: pointer.To(LockedImmutabilityPolicy)}); err != nil {
+		t.Fatalf("extending a locked immutability policy: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Listing Blobs..")
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	list, err := containersClient.ListBlobs(ctx, containerName, containers.ListBlobsInput{
+		Include: &[]containers.Dataset{containers.ImmutabilityPolicy, containers.LegalHold},
+	})
+	if err != nil {
+		t.Fatalf("listing blobs: %+v", err)
+	}
+	if len(list.Blobs.Blobs) != 1 || list.Blobs.Blobs[0].Properties == nil {
+		t.Fatalf("expected 1 blob to be listed but got %d", len(list.Blobs.Blobs))
+	}
+	properties := list.Blobs.Blobs[0].Properties
+	if properties.ImmutabilityPolicyMode == nil || *properties.ImmutabilityPolicyMode != string(LockedImmutabilityPolicy) {
+		t.Fatalf("expected the immutability policy mode to be %q but got %v", string(LockedImmutabilityPolicy), properties.ImmutabilityPolicyMode)
+	}
+	if properties.ImmutabilityPolicyUntilDate == nil || *properties.ImmutabilityPolicyUntilDate != expiresOn.Format(http.TimeFormat) {
+		t.Fatalf("expected the immutability policy to expire at %q but got %v", expiresOn.Format(http.TimeFormat), properties.ImmutabilityPolicyUntilDate)
+	}
+	if properties.LegalHold == nil || !*properties.LegalHold {
+		t.Fatalf("expected the legal hold to be listed")
+	}
+
+	t.Logf("[DEBUG] Removing the Legal Hold..")
+	if _, err := blobClient.SetLegalHold(ctx, containerName, fileName, SetLegalHoldInput{LegalHold: false}); err != nil {
+		t.Fatalf("removing legal hold: %+v", err)
+	}
+	props, err = blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.LegalHold {
+		t.Fatalf("expected the legal hold to have been removed")
+	}
+}
@@ -65,7 +65,7 @@ func (c Client) IncrementalCopyBlob(ctx context.Context, containerName, blobName
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "IncrementalCopyBlob", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -56,7 +56,7 @@ func (c Client) AcquireLease(ctx context.Context, containerName, blobName string
 		return
 	}
 	// An infinite lease duration is -1 seconds. A non-infinite lease can be between 15 and 60 seconds
-	if input.LeaseDuration != -1 && (input.LeaseDuration <= 15 || input.LeaseDuration >= 60) {
+	if input.LeaseDuration != -1 && (input.LeaseDuration < 15 || input.LeaseDuration > 60) {
 		err = fmt.Errorf("`input.LeaseDuration` must be -1 (infinite), or between 15 and 60 seconds")
 		return
 	}
@@ -79,7 +79,7 @@ func (c Client) AcquireLease(ctx context.Context, containerName, blobName string
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "AcquireLease", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
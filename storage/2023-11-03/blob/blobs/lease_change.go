@@ -66,7 +66,7 @@ func (c Client) ChangeLease(ctx context.Context, containerName, blobName string,
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "ChangeLease", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -0,0 +1,78 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tombuildsstuff/giovanni/storage/lease"
+)
+
+// NewLeaseKeeper returns a Keeper which acquires a Lease on the specified Blob and renews it in the background,
+// allowing the Blob to be used as a distributed lock.
+func (c Client) NewLeaseKeeper(containerName, blobName string, options lease.Options) (*lease.Keeper, error) {
+	if containerName == "" {
+		return nil, fmt.Errorf("`containerName` cannot be an empty string")
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		return nil, fmt.Errorf("`containerName` must be a lower-cased string")
+	}
+
+	if blobName == "" {
+		return nil, fmt.Errorf("`blobName` cannot be an empty string")
+	}
+
+	lessor := blobLessor{
+		client:        c,
+		containerName: containerName,
+		blobName:      blobName,
+	}
+	return lease.NewKeeper(lessor, options)
+}
+
+var _ lease.Lessor = blobLessor{}
+
+type blobLessor struct {
+	client        Client
+	containerName string
+	blobName      string
+}
+
+func (l blobLessor) AcquireLease(ctx context.Context, duration int, proposedLeaseId string) error {
+	input := AcquireLeaseInput{
+		LeaseDuration:   duration,
+		ProposedLeaseID: &proposedLeaseId,
+	}
+	resp, err := l.client.AcquireLease(ctx, l.containerName, l.blobName, input)
+	if err != nil {
+		if resp.HttpResponse != nil && resp.HttpResponse.StatusCode == http.StatusConflict {
+			return fmt.Errorf("%w: %+v", lease.ErrLeaseAlreadyPresent, err)
+		}
+		return fmt.Errorf("acquiring lease: %+v", err)
+	}
+	return nil
+}
+
+func (l blobLessor) RenewLease(ctx context.Context, leaseId string) error {
+	resp, err := l.client.RenewLease(ctx, l.containerName, l.blobName, RenewLeaseInput{LeaseID: leaseId})
+	if err != nil {
+		if resp.HttpResponse != nil && resp.HttpResponse.StatusCode == http.StatusConflict {
+			return fmt.Errorf("%w: %+v", lease.ErrLeaseLost, err)
+		}
+		return fmt.Errorf("renewing lease: %+v", err)
+	}
+	return nil
+}
+
+func (l blobLessor) ReleaseLease(ctx context.Context, leaseId string) error {
+	resp, err := l.client.ReleaseLease(ctx, l.containerName, l.blobName, ReleaseLeaseInput{LeaseID: leaseId})
+	if err != nil {
+		if resp.HttpResponse != nil && resp.HttpResponse.StatusCode == http.StatusConflict {
+			return fmt.Errorf("%w: %+v", lease.ErrLeaseLost, err)
+		}
+		return fmt.Errorf("releasing lease: %+v", err)
+	}
+	return nil
+}
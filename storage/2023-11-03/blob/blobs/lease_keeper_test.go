@@ -0,0 +1,101 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+	"github.com/tombuildsstuff/giovanni/storage/lease"
+)
+
+func TestLeaseKeeper(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	blobClient, err := NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobClient.Client.SetAuthorizer(authorizer)
+
+	containerName := "container1"
+	fileName := "leader.lock"
+	if _, err := containersClient.Create(ctx, containerName, containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, fileName, PutBlockBlobInput{}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+
+	options := lease.Options{
+		Duration:      15 * time.Second,
+		RenewInterval: 10 * time.Millisecond,
+		RetryDelay:    10 * time.Millisecond,
+	}
+	leader, err := blobClient.NewLeaseKeeper(containerName, fileName, options)
+	if err != nil {
+		t.Fatalf("building keeper: %+v", err)
+	}
+	follower, err := blobClient.NewLeaseKeeper(containerName, fileName, options)
+	if err != nil {
+		t.Fatalf("building keeper: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Acquiring the Lease..")
+	if err := leader.Lock(ctx); err != nil {
+		t.Fatalf("acquiring lease: %+v", err)
+	}
+	if err := follower.TryLock(ctx); !errors.Is(err, lease.ErrLeaseAlreadyPresent) {
+		t.Fatalf("expected ErrLeaseAlreadyPresent but got: %+v", err)
+	}
+	props, err := blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.LeaseState != Leased {
+		t.Fatalf("expected the blob to be leased but got %q", string(props.LeaseState))
+	}
+
+	t.Logf("[DEBUG] Releasing the Lease hands it to the follower..")
+	acquired := make(chan error)
+	go func() {
+		acquired <- follower.Lock(ctx)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if err := leader.Close(); err != nil {
+		t.Fatalf("closing keeper: %+v", err)
+	}
+	if err := <-acquired; err != nil {
+		t.Fatalf("acquiring lease: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Breaking the Lease cancels the follower's Context..")
+	if _, err := blobClient.BreakLease(ctx, containerName, fileName, BreakLeaseInput{BreakPeriod: pointer.To(0), LeaseID: follower.LeaseID()}); err != nil {
+		t.Fatalf("breaking lease: %+v", err)
+	}
+	select {
+	case <-follower.Context().Done():
+	case <-ctx.Done():
+		t.Fatalf("expected the context to be cancelled once the lease was broken")
+	}
+	if !errors.Is(context.Cause(follower.Context()), lease.ErrLeaseLost) {
+		t.Fatalf("expected the lease to be lost but got: %+v", context.Cause(follower.Context()))
+	}
+	if err := follower.Close(); err != nil {
+		t.Fatalf("closing keeper: %+v", err)
+	}
+}
@@ -57,7 +57,7 @@ func (c Client) RenewLease(ctx context.Context, containerName, blobName string,
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "RenewLease", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -0,0 +1,133 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+type SetLegalHoldInput struct {
+	// Whether a Legal Hold should be applied to (or removed from) the blob (version)
+	LegalHold bool
+
+	// The Snapshot of the blob on which the Legal Hold should be set
+	// If not specified the Legal Hold is set on the base blob.
+	Snapshot *string
+
+	// The Version ID of the blob on which the Legal Hold should be set
+	// If not specified the Legal Hold is set on the current version of the blob.
+	VersionID *string
+}
+
+type SetLegalHoldResponse struct {
+	HttpResponse *http.Response
+
+	LegalHold bool
+}
+
+// SetLegalHold applies (or removes) a Legal Hold on a blob (or a version of a blob), which prevents it from being
+// modified or deleted until the Legal Hold is removed. Version-level immutability must be enabled for the Container.
+func (c Client) SetLegalHold(ctx context.Context, containerName, blobName string, input SetLegalHoldInput) (result SetLegalHoldResponse, err error) {
+	if containerName == "" {
+		err = fmt.Errorf("`containerName` cannot be an empty string")
+		return
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		err = fmt.Errorf("`containerName` must be a lower-cased string")
+		return
+	}
+
+	if blobName == "" {
+		err = fmt.Errorf("`blobName` cannot be an empty string")
+		return
+	}
+
+	if input.VersionID != nil && *input.VersionID == "" {
+		err = fmt.Errorf("`input.VersionID` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if input.Snapshot != nil && *input.Snapshot == "" {
+		err = fmt.Errorf("`input.Snapshot` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if input.Snapshot != nil && input.VersionID != nil {
+		err = fmt.Errorf("only one of `input.Snapshot` and `input.VersionID` can be specified")
+		return
+	}
+
+	opts := client.RequestOptions{
+		ExpectedStatusCodes: []int{
+			http.StatusOK,
+		},
+		HttpMethod: http.MethodPut,
+		OptionsObject: setLegalHoldOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "SetLegalHold", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				if v := resp.Header.Get("x-ms-legal-hold"); v != "" {
+					b, innerErr := strconv.ParseBool(v)
+					if innerErr != nil {
+						err = fmt.Errorf("parsing `x-ms-legal-hold` header value %q: %s", v, innerErr)
+						return
+					}
+					result.LegalHold = b
+				}
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type setLegalHoldOptions struct {
+	input SetLegalHoldInput
+}
+
+func (s setLegalHoldOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	headers.Append("x-ms-legal-hold", strconv.FormatBool(s.input.LegalHold))
+	return headers
+}
+
+func (s setLegalHoldOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (s setLegalHoldOptions) ToQuery() *client.QueryParams {
+	out := &client.QueryParams{}
+	out.Append("comp", "legalhold")
+	if s.input.Snapshot != nil {
+		out.Append("snapshot", *s.input.Snapshot)
+	}
+	if s.input.VersionID != nil {
+		out.Append("versionid", *s.input.VersionID)
+	}
+	return out
+}
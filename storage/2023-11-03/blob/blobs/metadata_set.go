@@ -21,10 +21,16 @@ type SetMetaDataInput struct {
 
 	// The encryption scope for the blob.
 	EncryptionScope *string
+
+	// The encryption key provided by the client, which is used to encrypt the blob (and is not stored by Azure)
+	CustomerProvidedKey *CustomerProvidedKey
 }
 
 type SetMetaDataResponse struct {
 	HttpResponse *http.Response
+
+	// The Version ID of the blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
 }
 
 // SetMetaData marks the specified blob or snapshot for deletion. The blob is later deleted during garbage collection.
@@ -49,6 +55,11 @@ func (c Client) SetMetaData(ctx context.Context, containerName, blobName string,
 		return
 	}
 
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusOK,
@@ -67,9 +78,15 @@ func (c Client) SetMetaData(ctx context.Context, containerName, blobName string,
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetMetaData", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.VersionID = resp.Header.Get("x-ms-version-id")
+			}
+		}
 	}
 	if err != nil {
 		err = fmt.Errorf("executing request: %+v", err)
@@ -91,6 +108,7 @@ func (s setMetadataOptions) ToHeaders() *client.Headers {
 	if s.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *s.input.EncryptionScope)
 	}
+	s.input.CustomerProvidedKey.appendHeaders(headers)
 	headers.Merge(metadata.SetMetaDataHeaders(s.input.MetaData))
 	return headers
 }
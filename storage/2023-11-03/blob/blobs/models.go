@@ -4,14 +4,29 @@ type AccessTier string
 
 var (
 	Archive AccessTier = "Archive"
+	Cold    AccessTier = "Cold"
 	Cool    AccessTier = "Cool"
 	Hot     AccessTier = "Hot"
+
+	// The Premium tiers are only applicable to Page Blobs within a Premium Storage Account
+	P4  AccessTier = "P4"
+	P6  AccessTier = "P6"
+	P10 AccessTier = "P10"
+	P15 AccessTier = "P15"
+	P20 AccessTier = "P20"
+	P30 AccessTier = "P30"
+	P40 AccessTier = "P40"
+	P50 AccessTier = "P50"
+	P60 AccessTier = "P60"
+	P70 AccessTier = "P70"
+	P80 AccessTier = "P80"
 )
 
 type ArchiveStatus string
 
 var (
 	None                   ArchiveStatus = ""
+	RehydratePendingToCold ArchiveStatus = "rehydrate-pending-to-cold"
 	RehydratePendingToCool ArchiveStatus = "rehydrate-pending-to-cool"
 	RehydratePendingToHot  ArchiveStatus = "rehydrate-pending-to-hot"
 )
@@ -40,6 +55,15 @@ var (
 	PageBlob   BlobType = "PageBlob"
 )
 
+type QueryFormatType string
+
+var (
+	DelimitedQueryFormat QueryFormatType = "delimited"
+	JSONQueryFormat      QueryFormatType = "json"
+	ArrowQueryFormat     QueryFormatType = "arrow"
+	ParquetQueryFormat   QueryFormatType = "parquet"
+)
+
 type CommittedBlocks struct {
 	Blocks []Block `xml:"Block"`
 }
@@ -53,6 +77,13 @@ var (
 	Success CopyStatus = "success"
 )
 
+type ImmutabilityPolicyMode string
+
+var (
+	LockedImmutabilityPolicy   ImmutabilityPolicyMode = "Locked"
+	UnlockedImmutabilityPolicy ImmutabilityPolicyMode = "Unlocked"
+)
+
 type LeaseDuration string
 
 var (
@@ -77,6 +108,13 @@ var (
 	Unlocked LeaseStatus = "unlocked"
 )
 
+type RehydratePriority string
+
+var (
+	HighRehydratePriority     RehydratePriority = "High"
+	StandardRehydratePriority RehydratePriority = "Standard"
+)
+
 type UncommittedBlocks struct {
 	Blocks []Block `xml:"Block"`
 }
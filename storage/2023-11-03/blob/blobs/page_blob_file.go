@@ -0,0 +1,247 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+)
+
+// pageSize is the size of each Page within a Page Blob, to which writes must be aligned
+const pageSize = 512
+
+var (
+	_ io.ReaderAt = &PageBlobFile{}
+	_ io.WriterAt = &PageBlobFile{}
+)
+
+type PageBlobFileOptions struct {
+	// The ID of the Lease on the Page Blob, if any
+	LeaseID *string
+
+	CustomerProvidedKey *CustomerProvidedKey
+
+	// The sequence number conditions applied to each write, allowing concurrent writers to be detected -
+	// where a write fails if the sequence number of the Page Blob doesn't meet the condition.
+	IfSequenceNumberEQ *int64
+	IfSequenceNumberLE *int64
+	IfSequenceNumberLT *int64
+}
+
+// PageBlobFile provides access to a Page Blob as an io.ReaderAt and io.WriterAt, handling the alignment of writes
+// to 512-byte Pages and splitting reads and writes into requests of at most 4 MiB.
+type PageBlobFile struct {
+	ctx           context.Context
+	client        Client
+	containerName string
+	blobName      string
+	options       PageBlobFileOptions
+
+	mu   sync.RWMutex
+	size int64
+}
+
+// OpenPageBlobFile opens the existing Page Blob `blobName` as a PageBlobFile - where `ctx` is used for each request
+// made by the PageBlobFile.
+func (c Client) OpenPageBlobFile(ctx context.Context, containerName, blobName string, options PageBlobFileOptions) (*PageBlobFile, error) {
+	props, err := c.GetProperties(ctx, containerName, blobName, GetPropertiesInput{
+		LeaseID:             options.LeaseID,
+		CustomerProvidedKey: options.CustomerProvidedKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("retrieving properties: %+v", err)
+	}
+	if props.BlobType != PageBlob {
+		return nil, fmt.Errorf("expected %q to be a %s but got %s", blobName, string(PageBlob), string(props.BlobType))
+	}
+
+	return &PageBlobFile{
+		ctx:           ctx,
+		client:        c,
+		containerName: containerName,
+		blobName:      blobName,
+		options:       options,
+		size:          props.ContentLength,
+	}, nil
+}
+
+// Size returns the size of the Page Blob in bytes.
+func (f *PageBlobFile) Size() int64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.size
+}
+
+// ReadAt reads len(p) bytes from the Page Blob starting at the offset `off`, returning io.EOF if fewer bytes
+// were read as the end of the Page Blob was reached.
+func (f *PageBlobFile) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("the offset %d cannot be negative", off)
+	}
+	size := f.Size()
+	if off >= size {
+		return 0, io.EOF
+	}
+	length := int64(len(p))
+	if off+length > size {
+		length = size - off
+	}
+
+	for n < int(length) {
+		chunk := length - int64(n)
+		if chunk > maxPageWriteBytes {
+			chunk = maxPageWriteBytes
+		}
+		start := off + int64(n)
+		content, _, err := f.read(start, start+chunk-1)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], content)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt writes len(p) bytes to the Page Blob starting at the offset `off` - where any Pages which are only
+// partially written are read, updated and written back. The Page Blob must be resized using Truncate before
+// writing beyond its current size.
+func (f *PageBlobFile) WriteAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("the offset %d cannot be negative", off)
+	}
+	end := off + int64(len(p))
+	if size := f.Size(); end > size {
+		return 0, fmt.Errorf("writing %d bytes at offset %d would exceed the size of the page blob (%d bytes) - the page blob must be resized using Truncate first", len(p), off, size)
+	}
+
+	alignedStart := off - off%pageSize
+	alignedEnd := end
+	if remainder := end % pageSize; remainder != 0 {
+		alignedEnd += pageSize - remainder
+	}
+
+	for chunkStart := alignedStart; chunkStart < alignedEnd; chunkStart += maxPageWriteBytes {
+		chunkEnd := chunkStart + maxPageWriteBytes
+		if chunkEnd > alignedEnd {
+			chunkEnd = alignedEnd
+		}
+
+		// the range of `p` within this chunk
+		dataStart := off
+		if chunkStart > dataStart {
+			dataStart = chunkStart
+		}
+		dataEnd := end
+		if chunkEnd < dataEnd {
+			dataEnd = chunkEnd
+		}
+
+		content := make([]byte, chunkEnd-chunkStart)
+		var etag *string
+		if dataStart > chunkStart || dataEnd < chunkEnd {
+			// the first and/or last Page is only partially written, so the existing content of the chunk is read first
+			existing, existingETag, err := f.read(chunkStart, chunkEnd-1)
+			if err != nil {
+				return n, fmt.Errorf("reading the existing pages: %+v", err)
+			}
+			copy(content, existing)
+			etag = pointer.To(existingETag)
+		}
+		copy(content[dataStart-chunkStart:], p[dataStart-off:dataEnd-off])
+
+		input := PutPageUpdateInput{
+			StartByte:           chunkStart,
+			EndByte:             chunkEnd - 1,
+			Content:             content,
+			IfMatch:             etag,
+			LeaseID:             f.options.LeaseID,
+			CustomerProvidedKey: f.options.CustomerProvidedKey,
+		}
+		if f.options.IfSequenceNumberEQ != nil {
+			input.IfSequenceNumberEQ = pointer.To(strconv.FormatInt(*f.options.IfSequenceNumberEQ, 10))
+		}
+		if f.options.IfSequenceNumberLE != nil {
+			input.IfSequenceNumberLE = pointer.To(strconv.FormatInt(*f.options.IfSequenceNumberLE, 10))
+		}
+		if f.options.IfSequenceNumberLT != nil {
+			input.IfSequenceNumberLT = pointer.To(strconv.FormatInt(*f.options.IfSequenceNumberLT, 10))
+		}
+		if _, err := f.client.PutPageUpdate(f.ctx, f.containerName, f.blobName, input); err != nil {
+			return n, fmt.Errorf("writing bytes %d to %d: %+v", chunkStart, chunkEnd-1, err)
+		}
+		n += int(dataEnd - dataStart)
+	}
+
+	return n, nil
+}
+
+// Truncate resizes the Page Blob to `size` bytes, which must be aligned to a 512-byte boundary.
+func (f *PageBlobFile) Truncate(size int64) error {
+	if size < 0 || size%pageSize != 0 {
+		return fmt.Errorf("the size of a page blob must be aligned to a 512-byte boundary but got %d", size)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Set Blob Properties clears any of the `x-ms-blob-content-*` properties which aren't specified, so the current
+	// values are retrieved and sent along with the new size
+	props, err := f.client.GetProperties(f.ctx, f.containerName, f.blobName, GetPropertiesInput{
+		LeaseID:             f.options.LeaseID,
+		CustomerProvidedKey: f.options.CustomerProvidedKey,
+	})
+	if err != nil {
+		return fmt.Errorf("retrieving the properties of the page blob: %+v", err)
+	}
+	input := SetPropertiesInput{
+		CacheControl:       optionalString(props.CacheControl),
+		ContentDisposition: optionalString(props.ContentDisposition),
+		ContentEncoding:    optionalString(props.ContentEncoding),
+		ContentLanguage:    optionalString(props.ContentLanguage),
+		ContentLength:      pointer.To(size),
+		ContentMD5:         optionalString(props.ContentMD5),
+		ContentType:        optionalString(props.ContentType),
+		LeaseID:            f.options.LeaseID,
+	}
+	if _, err := f.client.SetProperties(f.ctx, f.containerName, f.blobName, input); err != nil {
+		return fmt.Errorf("resizing the page blob to %d bytes: %+v", size, err)
+	}
+	f.size = size
+	return nil
+}
+
+// optionalString returns a pointer to `input`, or nil when it's empty
+func optionalString(input string) *string {
+	if input == "" {
+		return nil
+	}
+	return &input
+}
+
+// read returns the content of the Page Blob between `start` and `end` (inclusive) along with its ETag
+func (f *PageBlobFile) read(start, end int64) ([]byte, string, error) {
+	result, err := f.client.Get(f.ctx, f.containerName, f.blobName, GetInput{
+		StartByte:           pointer.To(start),
+		EndByte:             pointer.To(end),
+		LeaseID:             f.options.LeaseID,
+		CustomerProvidedKey: f.options.CustomerProvidedKey,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("reading bytes %d to %d: %+v", start, end, err)
+	}
+	if result.Contents == nil || int64(len(*result.Contents)) != end-start+1 {
+		return nil, "", fmt.Errorf("reading bytes %d to %d: expected %d bytes to be returned", start, end, end-start+1)
+	}
+	etag := ""
+	if result.HttpResponse != nil {
+		etag = result.HttpResponse.Header.Get("ETag")
+	}
+	return *result.Contents, etag, nil
+}
@@ -0,0 +1,110 @@
+package blobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestPageBlobFile(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	blobClient := buildBlobClient(t, ctx, server)
+	containerName := "container1"
+	fileName := "disk.vhd"
+	putInput := PutPageBlobInput{
+		BlobContentLengthBytes: 4096,
+		CacheControl:           pointer.To("no-cache"),
+		ContentType:            pointer.To("application/octet-stream"),
+	}
+	if _, err := blobClient.PutPageBlob(ctx, containerName, fileName, putInput); err != nil {
+		t.Fatalf("putting page blob: %+v", err)
+	}
+	file, err := blobClient.OpenPageBlobFile(ctx, containerName, fileName, PageBlobFileOptions{})
+	if err != nil {
+		t.Fatalf("opening page blob file: %+v", err)
+	}
+	expected := make([]byte, 4096)
+
+	t.Logf("[DEBUG] Writing an unaligned range..")
+	data := bytes.Repeat([]byte("hello"), 200)
+	if n, err := file.WriteAt(data, 100); err != nil || n != len(data) {
+		t.Fatalf("expected %d bytes to be written but got %d: %+v", len(data), n, err)
+	}
+	copy(expected[100:], data)
+	data = []byte("world")
+	if _, err := file.WriteAt(data, 510); err != nil {
+		t.Fatalf("writing across a page boundary: %+v", err)
+	}
+	copy(expected[510:], data)
+	actual := make([]byte, 4096)
+	if n, err := file.ReadAt(actual, 0); err != nil || n != 4096 {
+		t.Fatalf("expected 4096 bytes to be read but got %d: %+v", n, err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf("expected the content to be updated without overwriting the rest of the pages")
+	}
+
+	t.Logf("[DEBUG] Reading beyond the end..")
+	actual = make([]byte, 100)
+	if n, err := file.ReadAt(actual, 4050); !errors.Is(err, io.EOF) || n != 46 {
+		t.Fatalf("expected 46 bytes and io.EOF but got %d: %+v", n, err)
+	}
+	if _, err := file.WriteAt([]byte("beyond"), 4093); err == nil {
+		t.Fatalf("expected an error writing beyond the end of the page blob but didn't get one")
+	}
+
+	t.Logf("[DEBUG] Resizing and writing across multiple chunks..")
+	if err := file.Truncate(9 * 1024 * 1024); err != nil {
+		t.Fatalf("resizing: %+v", err)
+	}
+	props, err := blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.ContentLength != 9*1024*1024 || props.CacheControl != "no-cache" || props.ContentType != "application/octet-stream" {
+		t.Fatalf("expected the page blob to be resized without clearing its properties but got %d bytes, %q and %q", props.ContentLength, props.CacheControl, props.ContentType)
+	}
+	if err := file.Truncate(1000); err == nil {
+		t.Fatalf("expected an error resizing to an unaligned size but didn't get one")
+	}
+	large := make([]byte, 5*1024*1024+3)
+	if _, err := rand.Read(large); err != nil {
+		t.Fatalf("generating content: %+v", err)
+	}
+	if n, err := file.WriteAt(large, 3); err != nil || n != len(large) {
+		t.Fatalf("expected %d bytes to be written but got %d: %+v", len(large), n, err)
+	}
+	actual = make([]byte, len(large))
+	if _, err := file.ReadAt(actual, 3); err != nil {
+		t.Fatalf("reading: %+v", err)
+	}
+	if !bytes.Equal(actual, large) {
+		t.Fatalf("expected the content read to match the content written")
+	}
+	props, err = blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.ContentLength != 9*1024*1024 || file.Size() != props.ContentLength {
+		t.Fatalf("expected the page blob to be %d bytes but got %d (%d)", 9*1024*1024, props.ContentLength, file.Size())
+	}
+
+	t.Logf("[DEBUG] Writing with a Sequence Number condition..")
+	conditional, err := blobClient.OpenPageBlobFile(ctx, containerName, fileName, PageBlobFileOptions{IfSequenceNumberEQ: pointer.To(int64(5))})
+	if err != nil {
+		t.Fatalf("opening page blob file: %+v", err)
+	}
+	if _, err := conditional.WriteAt([]byte("conditional"), 0); err == nil {
+		t.Fatalf("expected an error when the sequence number condition isn't met but didn't get one")
+	}
+}
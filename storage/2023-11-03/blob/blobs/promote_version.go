@@ -0,0 +1,99 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client/pollers"
+)
+
+type PromoteVersionInput struct {
+	// The Version ID of the previous version of the blob which should become the current version
+	VersionID string
+
+	// The ID of the Lease
+	// This must be specified if a Lease is present on the Blob
+	LeaseID *string
+}
+
+type PromoteVersionOptions struct {
+	// PollInterval is how often the status of the copy is checked, when the copy doesn't complete synchronously.
+	// Defaults to 10 seconds.
+	PollInterval time.Duration
+
+	// Progress is optionally called each time the status of the copy is checked, with the number of bytes
+	// which have been copied and the total number of bytes to copy.
+	Progress func(bytesCopied, totalBytes int64)
+}
+
+// PromoteVersion restores a previous version of a blob by copying it over the current version of the blob (which is
+// retained as a previous version), and waits for the copy to finish. This requires that Versioning is enabled.
+func (c Client) PromoteVersion(ctx context.Context, containerName, blobName string, input PromoteVersionInput) error {
+	return c.PromoteVersionWithOptions(ctx, containerName, blobName, input, PromoteVersionOptions{})
+}
+
+// PromoteVersionWithOptions restores a previous version of a blob by copying it over the current version of the blob,
+// and waits for the copy to finish using the specified options. This requires that Versioning is enabled.
+func (c Client) PromoteVersionWithOptions(ctx context.Context, containerName, blobName string, input PromoteVersionInput, options PromoteVersionOptions) error {
+	if containerName == "" {
+		return fmt.Errorf("`containerName` cannot be an empty string")
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		return fmt.Errorf("`containerName` must be a lower-cased string")
+	}
+
+	if blobName == "" {
+		return fmt.Errorf("`blobName` cannot be an empty string")
+	}
+
+	if input.VersionID == "" {
+		return fmt.Errorf("`input.VersionID` cannot be an empty string")
+	}
+
+	copyInput := CopyInput{
+		CopySource:      c.blobUri(containerName, blobName),
+		SourceVersionID: &input.VersionID,
+		LeaseID:         input.LeaseID,
+	}
+	resp, err := c.Copy(ctx, containerName, blobName, copyInput)
+	if err != nil {
+		return fmt.Errorf("copying version %q: %+v", input.VersionID, err)
+	}
+
+	// copies within a Storage Account generally complete synchronously
+	if strings.EqualFold(resp.CopyStatus, string(Success)) {
+		return nil
+	}
+
+	getInput := GetPropertiesInput{
+		LeaseID: input.LeaseID,
+	}
+	pollerType := NewCopyAndWaitPoller(&c, containerName, blobName, getInput)
+	pollerType.pollInterval = options.PollInterval
+	pollerType.progress = options.Progress
+	poller := pollers.NewPoller(pollerType, pollerType.interval(), pollers.DefaultNumberOfDroppedConnectionsToAllow)
+	if err := poller.PollUntilDone(ctx); err != nil {
+		var copyFailed *CopyFailedError
+		if errors.As(err, &copyFailed) {
+			return copyFailed
+		}
+		return fmt.Errorf("waiting for version %q to be promoted: %+v", input.VersionID, err)
+	}
+
+	return nil
+}
+
+// blobUri returns the URI of the blob `blobName` within the container `containerName`, with each segment of the
+// path escaped - so that (for example) a `?` within the blob name isn't treated as the start of the query string
+func (c Client) blobUri(containerName, blobName string) string {
+	segments := strings.Split(blobName, "/")
+	for i, v := range segments {
+		segments[i] = url.PathEscape(v)
+	}
+	return fmt.Sprintf("%s/%s/%s", c.Client.BaseUri, url.PathEscape(containerName), strings.Join(segments, "/"))
+}
@@ -0,0 +1,68 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPromoteVersionEscapesTheCopySource(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var copySource string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		copySource = r.Header.Get("x-ms-copy-source")
+		w.Header().Set("x-ms-copy-status", "success")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	input := PromoteVersionInput{
+		VersionID: "2024-01-01T00:00:00.0000000Z",
+	}
+	if err := client.PromoteVersion(ctx, "container1", "some folder/blob #1.txt", input); err != nil {
+		t.Fatalf("promoting version: %+v", err)
+	}
+
+	// the `#` would otherwise be parsed as a fragment, removing the `versionid` from the Copy Source
+	expected := server.URL + "/container1/some%20folder/blob%20%231.txt?versionid=2024-01-01T00%3A00%3A00.0000000Z"
+	if copySource != expected {
+		t.Fatalf("expected the Copy Source to be %q but got %q", expected, copySource)
+	}
+}
+
+func TestPromoteVersionFailed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	handler := &copyStatusServer{statuses: []string{"pending", "failed"}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL + "/devstoreaccount1")
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	input := PromoteVersionInput{
+		VersionID: "2024-01-01T00:00:00.0000000Z",
+	}
+	options := PromoteVersionOptions{
+		PollInterval: 10 * time.Millisecond,
+	}
+	err = client.PromoteVersionWithOptions(ctx, "container1", "blob.txt", input, options)
+	var copyFailed *CopyFailedError
+	if !errors.As(err, &copyFailed) {
+		t.Fatalf("expected a CopyFailedError but got: %+v", err)
+	}
+	if copyFailed.CopyStatus != Failed || copyFailed.CopyID != "copy1" {
+		t.Fatalf("expected the CopyFailedError to contain the copy status but got %+v", *copyFailed)
+	}
+}
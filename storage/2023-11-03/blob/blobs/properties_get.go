@@ -16,6 +16,14 @@ type GetPropertiesInput struct {
 	// The ID of the Lease
 	// This must be specified if a Lease is present on the Blob, else a 403 is returned
 	LeaseID *string
+
+	// The encryption key provided by the client when the blob was written
+	// This must be specified if the blob is encrypted using a Customer-Provided Key
+	CustomerProvidedKey *CustomerProvidedKey
+
+	// The Version ID of the blob
+	// If not specified the properties of the current version of the blob are returned.
+	VersionID *string
 }
 
 type GetPropertiesResponse struct {
@@ -139,6 +147,13 @@ type GetPropertiesResponse struct {
 	// The ETag contains a value that you can use to perform operations conditionally
 	ETag string
 
+	// The time until which the blob (version) can't be modified or deleted, if an Immutability Policy is set.
+	// The date format follows RFC 1123.
+	ImmutabilityPolicyExpiresOn string
+
+	// The mode of the Immutability Policy, if one is set
+	ImmutabilityPolicyMode ImmutabilityPolicyMode
+
 	// Included if the blob is incremental copy blob.
 	IncrementalCopy bool
 
@@ -153,14 +168,32 @@ type GetPropertiesResponse struct {
 
 	LeaseStatus LeaseStatus
 
+	// Does the blob (version) have a Legal Hold, preventing it from being modified or deleted?
+	LegalHold bool
+
 	// A set of name-value pairs that correspond to the user-defined metadata associated with this blob
 	MetaData map[string]string
 
+	// The priority with which the blob is being rehydrated, if the blob is being rehydrated from the Archive tier
+	RehydratePriority RehydratePriority
+
 	// Is the Storage Account encrypted using server-side encryption? This should always return true
 	ServerEncrypted bool
 
+	// The number of Blob Index Tags associated with this blob
+	TagCount int
+
 	// The encryption scope for the request content.
 	EncryptionScope string
+
+	// The SHA-256 hash of the Customer-Provided Key used to encrypt the blob (if any)
+	EncryptionKeySHA256 string
+
+	// The Version ID of the blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
+
+	// Is this the current version of the blob? This is only returned when Versioning is enabled
+	IsCurrentVersion bool
 }
 
 // GetProperties returns all user-defined metadata, standard HTTP properties, and system properties for the blob
@@ -178,13 +211,25 @@ func (c Client) GetProperties(ctx context.Context, containerName, blobName strin
 		return
 	}
 
+	if input.VersionID != nil && *input.VersionID == "" {
+		err = fmt.Errorf("`input.VersionID` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusOK,
 		},
 		HttpMethod: http.MethodHead,
 		OptionsObject: getPropertiesOptions{
-			leaseID: input.LeaseID,
+			leaseID:             input.LeaseID,
+			customerProvidedKey: input.CustomerProvidedKey,
+			versionID:           input.VersionID,
 		},
 		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
 	}
@@ -196,7 +241,7 @@ func (c Client) GetProperties(ctx context.Context, containerName, blobName strin
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -224,10 +269,15 @@ func (c Client) GetProperties(ctx context.Context, containerName, blobName strin
 				result.CreationTime = resp.Header.Get("x-ms-creation-time")
 				result.ETag = resp.Header.Get("Etag")
 				result.LastModified = resp.Header.Get("Last-Modified")
+				result.ImmutabilityPolicyExpiresOn = resp.Header.Get("x-ms-immutability-policy-until-date")
+				result.ImmutabilityPolicyMode = ImmutabilityPolicyMode(resp.Header.Get("x-ms-immutability-policy-mode"))
 				result.LeaseDuration = LeaseDuration(resp.Header.Get("x-ms-lease-duration"))
 				result.LeaseState = LeaseState(resp.Header.Get("x-ms-lease-state"))
 				result.LeaseStatus = LeaseStatus(resp.Header.Get("x-ms-lease-status"))
+				result.RehydratePriority = RehydratePriority(resp.Header.Get("x-ms-rehydrate-priority"))
 				result.EncryptionScope = resp.Header.Get("x-ms-encryption-scope")
+				result.EncryptionKeySHA256 = resp.Header.Get("x-ms-encryption-key-sha256")
+				result.VersionID = resp.Header.Get("x-ms-version-id")
 				result.MetaData = metadata.ParseFromHeaders(resp.Header)
 
 				if v := resp.Header.Get("x-ms-access-tier-inferred"); v != "" {
@@ -256,6 +306,24 @@ func (c Client) GetProperties(ctx context.Context, containerName, blobName strin
 					result.IncrementalCopy = b
 				}
 
+				if v := resp.Header.Get("x-ms-is-current-version"); v != "" {
+					b, innerErr := strconv.ParseBool(v)
+					if innerErr != nil {
+						err = fmt.Errorf("parsing `x-ms-is-current-version` header value %q: %s", v, innerErr)
+						return
+					}
+					result.IsCurrentVersion = b
+				}
+
+				if v := resp.Header.Get("x-ms-legal-hold"); v != "" {
+					b, innerErr := strconv.ParseBool(v)
+					if innerErr != nil {
+						err = fmt.Errorf("parsing `x-ms-legal-hold` header value %q: %s", v, innerErr)
+						return
+					}
+					result.LegalHold = b
+				}
+
 				if v := resp.Header.Get("x-ms-server-encrypted"); v != "" {
 					b, innerErr := strconv.ParseBool(v)
 					if innerErr != nil {
@@ -264,6 +332,15 @@ func (c Client) GetProperties(ctx context.Context, containerName, blobName strin
 					}
 					result.ServerEncrypted = b
 				}
+
+				if v := resp.Header.Get("x-ms-tag-count"); v != "" {
+					i, innerErr := strconv.Atoi(v)
+					if innerErr != nil {
+						err = fmt.Errorf("parsing `x-ms-tag-count` header value %q: %s", v, innerErr)
+						return
+					}
+					result.TagCount = i
+				}
 			}
 		}
 	}
@@ -276,7 +353,9 @@ func (c Client) GetProperties(ctx context.Context, containerName, blobName strin
 }
 
 type getPropertiesOptions struct {
-	leaseID *string
+	leaseID             *string
+	customerProvidedKey *CustomerProvidedKey
+	versionID           *string
 }
 
 func (g getPropertiesOptions) ToHeaders() *client.Headers {
@@ -285,6 +364,7 @@ func (g getPropertiesOptions) ToHeaders() *client.Headers {
 	if g.leaseID != nil {
 		headers.Append("x-ms-lease-id", *g.leaseID)
 	}
+	g.customerProvidedKey.appendHeaders(headers)
 	return headers
 }
 
@@ -293,5 +373,9 @@ func (g getPropertiesOptions) ToOData() *odata.Query {
 }
 
 func (g getPropertiesOptions) ToQuery() *client.QueryParams {
-	return nil
+	out := &client.QueryParams{}
+	if g.versionID != nil {
+		out.Append("versionid", *g.versionID)
+	}
+	return out
 }
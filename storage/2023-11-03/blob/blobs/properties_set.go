@@ -63,7 +63,7 @@ func (c Client) SetProperties(ctx context.Context, containerName, blobName strin
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
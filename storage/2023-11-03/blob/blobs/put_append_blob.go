@@ -12,19 +12,29 @@ import (
 )
 
 type PutAppendBlobInput struct {
-	CacheControl       *string
-	ContentDisposition *string
-	ContentEncoding    *string
-	ContentLanguage    *string
-	ContentMD5         *string
-	ContentType        *string
-	LeaseID            *string
-	EncryptionScope    *string
-	MetaData           map[string]string
+	CacheControl        *string
+	ContentDisposition  *string
+	ContentEncoding     *string
+	ContentLanguage     *string
+	ContentMD5          *string
+	ContentType         *string
+	LeaseID             *string
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+	MetaData            map[string]string
+
+	// An ETag value, or the wildcard character (*).
+	// Specify the wildcard character (*) to create the blob only if it does not already exist.
+	// If the specified condition isn't met, the Blob service returns status code 409 (Conflict)
+	// or 412 (Precondition Failed).
+	IfNoneMatch *string
 }
 
 type PutAppendBlobResponse struct {
 	HttpResponse *http.Response
+
+	// The Version ID of the blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
 }
 
 // PutAppendBlob is a wrapper around the Put API call (with a stricter input object)
@@ -50,6 +60,11 @@ func (c Client) PutAppendBlob(ctx context.Context, containerName, blobName strin
 		return
 	}
 
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -68,9 +83,15 @@ func (c Client) PutAppendBlob(ctx context.Context, containerName, blobName strin
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutAppendBlob", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.VersionID = resp.Header.Get("x-ms-version-id")
+			}
+		}
 	}
 	if err != nil {
 		err = fmt.Errorf("executing request: %+v", err)
@@ -114,6 +135,10 @@ func (p putAppendBlobOptions) ToHeaders() *client.Headers {
 	if p.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
 	}
+	if p.input.IfNoneMatch != nil {
+		headers.Append("If-None-Match", *p.input.IfNoneMatch)
+	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
 
 	headers.Merge(metadata.SetMetaDataHeaders(p.input.MetaData))
 	return headers
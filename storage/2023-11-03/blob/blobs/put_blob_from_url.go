@@ -0,0 +1,236 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/internal/metadata"
+)
+
+type PutBlobFromURLInput struct {
+	// The URL of the Source Blob (or File), which can be up to 5000 MiB in size.
+	// The Source must either be public, be authenticated via a shared access signature,
+	// or be authenticated using an OAuth token specified in CopySourceAuthorization.
+	CopySource string
+
+	// The value of the Authorization header used to access the Source, in the format `Bearer {token}`.
+	// Only OAuth access tokens are supported.
+	CopySourceAuthorization *string
+
+	// Whether the properties of the Source Blob should be copied to the destination blob. Defaults to true.
+	// The properties specified in this input take precedence over those of the Source Blob.
+	CopySourceBlobProperties *bool
+
+	// The base64-encoded MD5 hash of the content of the Source
+	// If specified, the Blob service confirms that the content read from the Source matches this hash.
+	SourceContentMD5 *string
+
+	// The base64-encoded CRC64 checksum of the content of the Source
+	// If specified, the Blob service confirms that the content read from the Source matches this checksum.
+	SourceContentCRC64 *string
+
+	CacheControl        *string
+	ContentDisposition  *string
+	ContentEncoding     *string
+	ContentLanguage     *string
+	ContentMD5          *string
+	ContentType         *string
+	LeaseID             *string
+	AccessTier          *AccessTier
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+	Tags                map[string]string
+	MetaData            map[string]string
+
+	// Specify an ETag value to write the blob only if it matches the ETag of the destination blob.
+	IfMatch *string
+
+	// Specify an ETag value (or the wildcard character (*)) to write the blob only if it doesn't match the
+	// ETag of the destination blob - or, when the wildcard character is specified, only if the destination blob doesn't exist.
+	IfNoneMatch *string
+
+	// Specify an ETag value to write the blob only if it matches the ETag of the Source Blob.
+	SourceIfMatch *string
+
+	// Specify an ETag value to write the blob only if it doesn't match the ETag of the Source Blob.
+	SourceIfNoneMatch *string
+}
+
+type PutBlobFromURLResponse struct {
+	HttpResponse *http.Response
+
+	// The base64-encoded MD5 hash and CRC64 checksum of the content which was written
+	ContentMD5   string
+	ContentCRC64 string
+
+	ETag         string
+	LastModified string
+
+	// The Version ID of the blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
+}
+
+// PutBlobFromURL creates a new block blob (or replaces the content of an existing block blob) where the contents
+// are read synchronously from a URL.
+func (c Client) PutBlobFromURL(ctx context.Context, containerName, blobName string, input PutBlobFromURLInput) (result PutBlobFromURLResponse, err error) {
+	if containerName == "" {
+		err = fmt.Errorf("`containerName` cannot be an empty string")
+		return
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		err = fmt.Errorf("`containerName` must be a lower-cased string")
+		return
+	}
+
+	if blobName == "" {
+		err = fmt.Errorf("`blobName` cannot be an empty string")
+		return
+	}
+
+	if input.CopySource == "" {
+		err = fmt.Errorf("`input.CopySource` cannot be an empty string")
+		return
+	}
+
+	if err = validateCopySourceAuthorization(input.CopySourceAuthorization); err != nil {
+		err = fmt.Errorf("`input.CopySourceAuthorization` is not valid: %+v", err)
+		return
+	}
+
+	if err = validateTags(input.Tags); err != nil {
+		err = fmt.Errorf("`input.Tags` is not valid: %+v", err)
+		return
+	}
+
+	if err = metadata.Validate(input.MetaData); err != nil {
+		err = fmt.Errorf("`input.MetaData` is not valid: %+v", err)
+		return
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
+	opts := client.RequestOptions{
+		ExpectedStatusCodes: []int{
+			http.StatusCreated,
+		},
+		HttpMethod: http.MethodPut,
+		OptionsObject: putBlobFromURLOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "PutBlobFromURL", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.ContentMD5 = resp.Header.Get("Content-MD5")
+				result.ContentCRC64 = resp.Header.Get("x-ms-content-crc64")
+				result.ETag = resp.Header.Get("ETag")
+				result.LastModified = resp.Header.Get("Last-Modified")
+				result.VersionID = resp.Header.Get("x-ms-version-id")
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type putBlobFromURLOptions struct {
+	input PutBlobFromURLInput
+}
+
+func (p putBlobFromURLOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	headers.Append("x-ms-blob-type", string(BlockBlob))
+	headers.Append("x-ms-copy-source", p.input.CopySource)
+	headers.Append("Content-Length", "0")
+
+	if p.input.CopySourceAuthorization != nil {
+		headers.Append("x-ms-copy-source-authorization", *p.input.CopySourceAuthorization)
+	}
+	if p.input.CopySourceBlobProperties != nil {
+		headers.Append("x-ms-copy-source-blob-properties", strconv.FormatBool(*p.input.CopySourceBlobProperties))
+	}
+	if p.input.SourceContentMD5 != nil {
+		headers.Append("x-ms-source-content-md5", *p.input.SourceContentMD5)
+	}
+	if p.input.SourceContentCRC64 != nil {
+		headers.Append("x-ms-source-content-crc64", *p.input.SourceContentCRC64)
+	}
+	if p.input.CacheControl != nil {
+		headers.Append("x-ms-blob-cache-control", *p.input.CacheControl)
+	}
+	if p.input.ContentDisposition != nil {
+		headers.Append("x-ms-blob-content-disposition", *p.input.ContentDisposition)
+	}
+	if p.input.ContentEncoding != nil {
+		headers.Append("x-ms-blob-content-encoding", *p.input.ContentEncoding)
+	}
+	if p.input.ContentLanguage != nil {
+		headers.Append("x-ms-blob-content-language", *p.input.ContentLanguage)
+	}
+	if p.input.ContentMD5 != nil {
+		headers.Append("x-ms-blob-content-md5", *p.input.ContentMD5)
+	}
+	if p.input.ContentType != nil {
+		headers.Append("x-ms-blob-content-type", *p.input.ContentType)
+	}
+	if p.input.LeaseID != nil {
+		headers.Append("x-ms-lease-id", *p.input.LeaseID)
+	}
+	if p.input.AccessTier != nil {
+		headers.Append("x-ms-access-tier", string(*p.input.AccessTier))
+	}
+	if p.input.EncryptionScope != nil {
+		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
+	}
+	if p.input.IfMatch != nil {
+		headers.Append("If-Match", *p.input.IfMatch)
+	}
+	if p.input.IfNoneMatch != nil {
+		headers.Append("If-None-Match", *p.input.IfNoneMatch)
+	}
+	if p.input.SourceIfMatch != nil {
+		headers.Append("x-ms-source-if-match", *p.input.SourceIfMatch)
+	}
+	if p.input.SourceIfNoneMatch != nil {
+		headers.Append("x-ms-source-if-none-match", *p.input.SourceIfNoneMatch)
+	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
+	appendTagsHeader(headers, p.input.Tags)
+
+	headers.Merge(metadata.SetMetaDataHeaders(p.input.MetaData))
+
+	return headers
+}
+
+func (p putBlobFromURLOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (p putBlobFromURLOptions) ToQuery() *client.QueryParams {
+	return nil
+}
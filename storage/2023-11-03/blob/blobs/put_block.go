@@ -11,14 +11,17 @@ import (
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
 )
 
 type PutBlockInput struct {
-	BlockID         string
-	Content         []byte
-	ContentMD5      *string
-	LeaseID         *string
-	EncryptionScope *string
+	BlockID             string
+	Content             []byte
+	ContentMD5          *string
+	LeaseID             *string
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+	ChecksumAlgorithm   *checksum.Algorithm
 }
 
 type PutBlockResponse struct {
@@ -53,6 +56,18 @@ func (c Client) PutBlock(ctx context.Context, containerName, blobName string, in
 		return
 	}
 
+	if input.ChecksumAlgorithm != nil {
+		if err = input.ChecksumAlgorithm.Validate(); err != nil {
+			err = fmt.Errorf("`input.ChecksumAlgorithm` is not valid: %+v", err)
+			return
+		}
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -74,7 +89,7 @@ func (c Client) PutBlock(ctx context.Context, containerName, blobName string, in
 	req.Body = io.NopCloser(bytes.NewReader(input.Content))
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutBlock", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -103,6 +118,10 @@ func (p putBlockOptions) ToHeaders() *client.Headers {
 	if p.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
 	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
+	if p.input.ChecksumAlgorithm != nil {
+		headers.Append(p.input.ChecksumAlgorithm.Header(), p.input.ChecksumAlgorithm.Compute(p.input.Content))
+	}
 
 	return headers
 }
@@ -11,24 +11,30 @@ import (
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
 	"github.com/tombuildsstuff/giovanni/storage/internal/metadata"
 )
 
 type PutBlockBlobInput struct {
-	CacheControl       *string
-	Content            *[]byte
-	ContentDisposition *string
-	ContentEncoding    *string
-	ContentLanguage    *string
-	ContentMD5         *string
-	ContentType        *string
-	LeaseID            *string
-	EncryptionScope    *string
-	MetaData           map[string]string
+	CacheControl        *string
+	Content             *[]byte
+	ContentDisposition  *string
+	ContentEncoding     *string
+	ContentLanguage     *string
+	ContentMD5          *string
+	ContentType         *string
+	LeaseID             *string
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+	ChecksumAlgorithm   *checksum.Algorithm
+	MetaData            map[string]string
 }
 
 type PutBlockBlobResponse struct {
 	HttpResponse *http.Response
+
+	// The Version ID of the blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
 }
 
 // PutBlockBlob is a wrapper around the Put API call (with a stricter input object)
@@ -59,6 +65,18 @@ func (c Client) PutBlockBlob(ctx context.Context, containerName, blobName string
 		return
 	}
 
+	if input.ChecksumAlgorithm != nil {
+		if err = input.ChecksumAlgorithm.Validate(); err != nil {
+			err = fmt.Errorf("`input.ChecksumAlgorithm` is not valid: %+v", err)
+			return
+		}
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -86,9 +104,15 @@ func (c Client) PutBlockBlob(ctx context.Context, containerName, blobName string
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutBlockBlob", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.VersionID = resp.Header.Get("x-ms-version-id")
+			}
+		}
 	}
 	if err != nil {
 		err = fmt.Errorf("executing request: %+v", err)
@@ -130,6 +154,10 @@ func (p putBlockBlobOptions) ToHeaders() *client.Headers {
 	if p.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
 	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
+	if p.input.ChecksumAlgorithm != nil && p.input.Content != nil {
+		headers.Append(p.input.ChecksumAlgorithm.Header(), p.input.ChecksumAlgorithm.Compute(*p.input.Content))
+	}
 	if p.input.Content != nil {
 		headers.Append("Content-Length", strconv.Itoa(len(*p.input.Content)))
 	}
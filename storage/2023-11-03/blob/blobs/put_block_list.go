@@ -22,16 +22,19 @@ type BlockID struct {
 }
 
 type PutBlockListInput struct {
-	BlockList          BlockList
-	CacheControl       *string
-	ContentDisposition *string
-	ContentEncoding    *string
-	ContentLanguage    *string
-	ContentMD5         *string
-	ContentType        *string
-	LeaseID            *string
-	EncryptionScope    *string
-	MetaData           map[string]string
+	BlockList           BlockList
+	CacheControl        *string
+	ContentDisposition  *string
+	ContentEncoding     *string
+	ContentLanguage     *string
+	ContentMD5          *string
+	ContentType         *string
+	LeaseID             *string
+	AccessTier          *AccessTier
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+	Tags                map[string]string
+	MetaData            map[string]string
 }
 
 type PutBlockListResponse struct {
@@ -40,6 +43,9 @@ type PutBlockListResponse struct {
 	ContentMD5   string
 	ETag         string
 	LastModified string
+
+	// The Version ID of the blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
 }
 
 // PutBlockList writes a blob by specifying the list of block IDs that make up the blob.
@@ -61,7 +67,18 @@ func (c Client) PutBlockList(ctx context.Context, containerName, blobName string
 		return
 	}
 
+	if err = validateTags(input.Tags); err != nil {
+		err = fmt.Errorf("`input.Tags` is not valid: %+v", err)
+		return
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
+		ContentType: "application/xml; charset=utf-8",
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
 		},
@@ -85,7 +102,7 @@ func (c Client) PutBlockList(ctx context.Context, containerName, blobName string
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutBlockList", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -94,6 +111,7 @@ func (c Client) PutBlockList(ctx context.Context, containerName, blobName string
 				result.ContentMD5 = resp.Header.Get("Content-MD5")
 				result.ETag = resp.Header.Get("ETag")
 				result.LastModified = resp.Header.Get("Last-Modified")
+				result.VersionID = resp.Header.Get("x-ms-version-id")
 			}
 		}
 	}
@@ -133,9 +151,14 @@ func (p putBlockListOptions) ToHeaders() *client.Headers {
 	if p.input.LeaseID != nil {
 		headers.Append("x-ms-lease-id", *p.input.LeaseID)
 	}
+	if p.input.AccessTier != nil {
+		headers.Append("x-ms-access-tier", string(*p.input.AccessTier))
+	}
 	if p.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
 	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
+	appendTagsHeader(headers, p.input.Tags)
 
 	headers.Merge(metadata.SetMetaDataHeaders(p.input.MetaData))
 
@@ -0,0 +1,52 @@
+package blobs
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPutBlockListSendsTheBlockListAsXml(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var contentType string
+	var body BlockList
+	var bodyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		bodyErr = xml.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	input := PutBlockListInput{
+		BlockList: BlockList{
+			LatestBlockIDs: []BlockID{
+				{Value: "YmxvY2sx"},
+				{Value: "YmxvY2sy"},
+			},
+		},
+	}
+	// the block list can only be marshalled when the request has an XML Content-Type
+	if _, err := client.PutBlockList(ctx, "container1", "blob1.txt", input); err != nil {
+		t.Fatalf("putting block list: %+v", err)
+	}
+
+	if contentType != "application/xml; charset=utf-8" {
+		t.Fatalf("expected the Content-Type to be %q but got %q", "application/xml; charset=utf-8", contentType)
+	}
+	if bodyErr != nil {
+		t.Fatalf("decoding the block list: %+v", bodyErr)
+	}
+	if len(body.LatestBlockIDs) != 2 || body.LatestBlockIDs[0].Value != "YmxvY2sx" || body.LatestBlockIDs[1].Value != "YmxvY2sy" {
+		t.Fatalf("expected the block list to contain the Latest Block IDs but got %+v", body)
+	}
+}
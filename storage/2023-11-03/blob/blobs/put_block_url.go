@@ -14,14 +14,25 @@ type PutBlockFromURLInput struct {
 	BlockID    string
 	CopySource string
 
-	ContentMD5      *string
-	LeaseID         *string
-	Range           *string
-	EncryptionScope *string
+	// The value of the Authorization header used to access the Copy Source, in the format `Bearer {token}`.
+	// Only OAuth access tokens are supported.
+	CopySourceAuthorization *string
+
+	// The base64-encoded MD5 hash of the content of the Copy Source (or Range)
+	ContentMD5 *string
+
+	// The base64-encoded CRC64 checksum of the content of the Copy Source (or Range)
+	SourceContentCRC64 *string
+
+	LeaseID             *string
+	Range               *string
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
 }
 
 type PutBlockFromURLResponse struct {
 	ContentMD5   string
+	ContentCRC64 string
 	HttpResponse *http.Response
 }
 
@@ -52,6 +63,16 @@ func (c Client) PutBlockFromURL(ctx context.Context, containerName, blobName str
 		return
 	}
 
+	if err = validateCopySourceAuthorization(input.CopySourceAuthorization); err != nil {
+		err = fmt.Errorf("`input.CopySourceAuthorization` is not valid: %+v", err)
+		return
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -70,13 +91,14 @@ func (c Client) PutBlockFromURL(ctx context.Context, containerName, blobName str
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutBlockFromURL", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
 		if err == nil {
 			if resp.Header != nil {
 				result.ContentMD5 = resp.Header.Get("Content-MD5")
+				result.ContentCRC64 = resp.Header.Get("x-ms-content-crc64")
 			}
 		}
 	}
@@ -97,9 +119,15 @@ func (p putBlockUrlOptions) ToHeaders() *client.Headers {
 
 	headers.Append("x-ms-copy-source", p.input.CopySource)
 
+	if p.input.CopySourceAuthorization != nil {
+		headers.Append("x-ms-copy-source-authorization", *p.input.CopySourceAuthorization)
+	}
 	if p.input.ContentMD5 != nil {
 		headers.Append("x-ms-source-content-md5", *p.input.ContentMD5)
 	}
+	if p.input.SourceContentCRC64 != nil {
+		headers.Append("x-ms-source-content-crc64", *p.input.SourceContentCRC64)
+	}
 	if p.input.LeaseID != nil {
 		headers.Append("x-ms-lease-id", *p.input.LeaseID)
 	}
@@ -109,6 +137,7 @@ func (p putBlockUrlOptions) ToHeaders() *client.Headers {
 	if p.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
 	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
 	return headers
 }
 
@@ -13,15 +13,16 @@ import (
 )
 
 type PutPageBlobInput struct {
-	CacheControl       *string
-	ContentDisposition *string
-	ContentEncoding    *string
-	ContentLanguage    *string
-	ContentMD5         *string
-	ContentType        *string
-	LeaseID            *string
-	EncryptionScope    *string
-	MetaData           map[string]string
+	CacheControl        *string
+	ContentDisposition  *string
+	ContentEncoding     *string
+	ContentLanguage     *string
+	ContentMD5          *string
+	ContentType         *string
+	LeaseID             *string
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+	MetaData            map[string]string
 
 	BlobContentLengthBytes int64
 	BlobSequenceNumber     *int64
@@ -30,6 +31,9 @@ type PutPageBlobInput struct {
 
 type PutPageBlobResponse struct {
 	HttpResponse *http.Response
+
+	// The Version ID of the blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
 }
 
 // PutPageBlob is a wrapper around the Put API call (with a stricter input object)
@@ -55,6 +59,11 @@ func (c Client) PutPageBlob(ctx context.Context, containerName, blobName string,
 		return
 	}
 
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -73,9 +82,15 @@ func (c Client) PutPageBlob(ctx context.Context, containerName, blobName string,
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutPageBlob", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.VersionID = resp.Header.Get("x-ms-version-id")
+			}
+		}
 	}
 	if err != nil {
 		err = fmt.Errorf("executing request: %+v", err)
@@ -141,6 +156,7 @@ func (p putPageBlobOptions) ToHeaders() *client.Headers {
 	if p.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
 	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
 
 	headers.Merge(metadata.SetMetaDataHeaders(p.input.MetaData))
 	return headers
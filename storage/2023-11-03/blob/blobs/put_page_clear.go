@@ -14,8 +14,9 @@ type PutPageClearInput struct {
 	StartByte int64
 	EndByte   int64
 
-	LeaseID         *string
-	EncryptionScope *string
+	LeaseID             *string
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
 }
 
 type PutPageClearResponse struct {
@@ -49,6 +50,11 @@ func (c Client) PutPageClear(ctx context.Context, containerName, blobName string
 		return
 	}
 
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -67,7 +73,7 @@ func (c Client) PutPageClear(ctx context.Context, containerName, blobName string
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutPageClear", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -95,6 +101,7 @@ func (p putPageClearOptions) ToHeaders() *client.Headers {
 	if p.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
 	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
 
 	return headers
 }
@@ -0,0 +1,197 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+type PutPageFromURLInput struct {
+	// The range of pages to write within the page blob, which must be aligned to a 512-byte boundary
+	// and be at most 4 MiB in size
+	StartByte int64
+	EndByte   int64
+
+	// The URL of the Source Blob (or File) to read the pages from
+	CopySource string
+
+	// The range of bytes to read from the CopySource, which must be the same size as the range being written
+	SourceStartByte int64
+	SourceEndByte   int64
+
+	// The value of the Authorization header used to access the CopySource, in the format `Bearer {token}`.
+	// Only OAuth access tokens are supported.
+	CopySourceAuthorization *string
+
+	// The base64-encoded MD5 hash and CRC64 checksum of the range of the CopySource
+	SourceContentMD5   *string
+	SourceContentCRC64 *string
+
+	IfSequenceNumberEQ  *string
+	IfSequenceNumberLE  *string
+	IfSequenceNumberLT  *string
+	IfModifiedSince     *string
+	IfUnmodifiedSince   *string
+	IfMatch             *string
+	IfNoneMatch         *string
+	LeaseID             *string
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+}
+
+type PutPageFromURLResponse struct {
+	HttpResponse *http.Response
+
+	BlobSequenceNumber string
+	ContentMD5         string
+	ContentCRC64       string
+	LastModified       string
+}
+
+// PutPageFromURL writes a range of pages to a page blob, where the contents are read from a URL.
+func (c Client) PutPageFromURL(ctx context.Context, containerName, blobName string, input PutPageFromURLInput) (result PutPageFromURLResponse, err error) {
+	if containerName == "" {
+		err = fmt.Errorf("`containerName` cannot be an empty string")
+		return
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		err = fmt.Errorf("`containerName` must be a lower-cased string")
+		return
+	}
+
+	if blobName == "" {
+		err = fmt.Errorf("`blobName` cannot be an empty string")
+		return
+	}
+
+	if input.CopySource == "" {
+		err = fmt.Errorf("`input.CopySource` cannot be an empty string")
+		return
+	}
+
+	if input.StartByte < 0 || input.StartByte%512 != 0 || (input.EndByte+1)%512 != 0 || input.EndByte <= input.StartByte {
+		err = fmt.Errorf("`input.StartByte` and `input.EndByte` must be aligned to a 512-byte boundary")
+		return
+	}
+
+	if input.SourceStartByte < 0 || (input.SourceEndByte-input.SourceStartByte) != (input.EndByte-input.StartByte) {
+		err = fmt.Errorf("the range specified by `input.SourceStartByte` and `input.SourceEndByte` must be the same size as the range being written")
+		return
+	}
+
+	if err = validateCopySourceAuthorization(input.CopySourceAuthorization); err != nil {
+		err = fmt.Errorf("`input.CopySourceAuthorization` is not valid: %+v", err)
+		return
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
+	opts := client.RequestOptions{
+		ExpectedStatusCodes: []int{
+			http.StatusCreated,
+		},
+		HttpMethod: http.MethodPut,
+		OptionsObject: putPageFromURLOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "PutPageFromURL", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.BlobSequenceNumber = resp.Header.Get("x-ms-blob-sequence-number")
+				result.ContentMD5 = resp.Header.Get("Content-MD5")
+				result.ContentCRC64 = resp.Header.Get("x-ms-content-crc64")
+				result.LastModified = resp.Header.Get("Last-Modified")
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type putPageFromURLOptions struct {
+	input PutPageFromURLInput
+}
+
+func (p putPageFromURLOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	headers.Append("x-ms-page-write", "update")
+	headers.Append("x-ms-range", fmt.Sprintf("bytes=%d-%d", p.input.StartByte, p.input.EndByte))
+	headers.Append("x-ms-copy-source", p.input.CopySource)
+	headers.Append("x-ms-source-range", fmt.Sprintf("bytes=%d-%d", p.input.SourceStartByte, p.input.SourceEndByte))
+	headers.Append("Content-Length", "0")
+
+	if p.input.CopySourceAuthorization != nil {
+		headers.Append("x-ms-copy-source-authorization", *p.input.CopySourceAuthorization)
+	}
+	if p.input.SourceContentMD5 != nil {
+		headers.Append("x-ms-source-content-md5", *p.input.SourceContentMD5)
+	}
+	if p.input.SourceContentCRC64 != nil {
+		headers.Append("x-ms-source-content-crc64", *p.input.SourceContentCRC64)
+	}
+	if p.input.LeaseID != nil {
+		headers.Append("x-ms-lease-id", *p.input.LeaseID)
+	}
+	if p.input.EncryptionScope != nil {
+		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
+	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
+	if p.input.IfSequenceNumberEQ != nil {
+		headers.Append("x-ms-if-sequence-number-eq", *p.input.IfSequenceNumberEQ)
+	}
+	if p.input.IfSequenceNumberLE != nil {
+		headers.Append("x-ms-if-sequence-number-le", *p.input.IfSequenceNumberLE)
+	}
+	if p.input.IfSequenceNumberLT != nil {
+		headers.Append("x-ms-if-sequence-number-lt", *p.input.IfSequenceNumberLT)
+	}
+	if p.input.IfModifiedSince != nil {
+		headers.Append("If-Modified-Since", *p.input.IfModifiedSince)
+	}
+	if p.input.IfUnmodifiedSince != nil {
+		headers.Append("If-Unmodified-Since", *p.input.IfUnmodifiedSince)
+	}
+	if p.input.IfMatch != nil {
+		headers.Append("If-Match", *p.input.IfMatch)
+	}
+	if p.input.IfNoneMatch != nil {
+		headers.Append("If-None-Match", *p.input.IfNoneMatch)
+	}
+
+	return headers
+}
+
+func (p putPageFromURLOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (p putPageFromURLOptions) ToQuery() *client.QueryParams {
+	out := &client.QueryParams{}
+	out.Append("comp", "page")
+	return out
+}
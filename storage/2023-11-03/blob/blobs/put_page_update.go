@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
 )
 
 type PutPageUpdateInput struct {
@@ -18,15 +19,17 @@ type PutPageUpdateInput struct {
 	EndByte   int64
 	Content   []byte
 
-	IfSequenceNumberEQ *string
-	IfSequenceNumberLE *string
-	IfSequenceNumberLT *string
-	IfModifiedSince    *string
-	IfUnmodifiedSince  *string
-	IfMatch            *string
-	IfNoneMatch        *string
-	LeaseID            *string
-	EncryptionScope    *string
+	IfSequenceNumberEQ  *string
+	IfSequenceNumberLE  *string
+	IfSequenceNumberLT  *string
+	IfModifiedSince     *string
+	IfUnmodifiedSince   *string
+	IfMatch             *string
+	IfNoneMatch         *string
+	LeaseID             *string
+	EncryptionScope     *string
+	CustomerProvidedKey *CustomerProvidedKey
+	ChecksumAlgorithm   *checksum.Algorithm
 }
 
 type PutPageUpdateResponse struct {
@@ -71,6 +74,18 @@ func (c Client) PutPageUpdate(ctx context.Context, containerName, blobName strin
 		return
 	}
 
+	if input.ChecksumAlgorithm != nil {
+		if err = input.ChecksumAlgorithm.Validate(); err != nil {
+			err = fmt.Errorf("`input.ChecksumAlgorithm` is not valid: %+v", err)
+			return
+		}
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -93,7 +108,7 @@ func (c Client) PutPageUpdate(ctx context.Context, containerName, blobName strin
 	req.ContentLength = int64(len(input.Content))
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutPageUpdate", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -130,6 +145,10 @@ func (p putPageUpdateOptions) ToHeaders() *client.Headers {
 	if p.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *p.input.EncryptionScope)
 	}
+	p.input.CustomerProvidedKey.appendHeaders(headers)
+	if p.input.ChecksumAlgorithm != nil {
+		headers.Append(p.input.ChecksumAlgorithm.Header(), p.input.ChecksumAlgorithm.Compute(p.input.Content))
+	}
 
 	if p.input.IfSequenceNumberEQ != nil {
 		headers.Append("x-ms-if-sequence-number-eq", *p.input.IfSequenceNumberEQ)
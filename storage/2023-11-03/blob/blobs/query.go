@@ -0,0 +1,291 @@
+package blobs
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+// QuerySerialization specifies the format of the Blob being queried, or of the results of the query -
+// where exactly one of the configurations must be specified.
+type QuerySerialization struct {
+	// DelimitedText specifies that the data is delimited text, such as CSV
+	DelimitedText *DelimitedTextConfiguration
+
+	// JSON specifies that the data is newline-delimited JSON records
+	JSON *JSONTextConfiguration
+
+	// Arrow specifies that the results are returned in the Apache Arrow format - which is only
+	// supported as an OutputSerialization
+	Arrow *ArrowConfiguration
+
+	// Parquet specifies that the Blob is in the Apache Parquet format - which is only supported
+	// as an InputSerialization
+	Parquet *ParquetConfiguration
+}
+
+type DelimitedTextConfiguration struct {
+	// The character used to separate columns, e.g. `,`
+	ColumnSeparator string `xml:"ColumnSeparator,omitempty"`
+
+	// The character used to quote a field, e.g. `"`
+	FieldQuote string `xml:"FieldQuote,omitempty"`
+
+	// The character used to separate records, e.g. `\n`
+	RecordSeparator string `xml:"RecordSeparator,omitempty"`
+
+	// The character used to escape a special character within a field
+	EscapeChar string `xml:"EscapeChar,omitempty"`
+
+	// Whether the first record contains the names of each column
+	HasHeaders bool `xml:"HasHeaders"`
+}
+
+type JSONTextConfiguration struct {
+	// The character used to separate records, e.g. `\n`
+	RecordSeparator string `xml:"RecordSeparator,omitempty"`
+}
+
+type ArrowConfiguration struct {
+	Schema []ArrowField `xml:"Schema>Field"`
+}
+
+type ArrowField struct {
+	// The type of the field, e.g. `int64`, `bool`, `string`, `double`, `decimal` or `timestamp[ms]`
+	Type      string `xml:"Type"`
+	Name      string `xml:"Name,omitempty"`
+	Precision *int   `xml:"Precision,omitempty"`
+	Scale     *int   `xml:"Scale,omitempty"`
+}
+
+type ParquetConfiguration struct{}
+
+type QueryInput struct {
+	// The SQL expression to run against the Blob, e.g. `SELECT * FROM BlobStorage WHERE _1 = 'value'`
+	Expression string
+
+	// The format of the Blob being queried
+	InputSerialization QuerySerialization
+
+	// The format of the results of the query. Defaults to the InputSerialization when nil.
+	OutputSerialization *QuerySerialization
+
+	// OnProgress is optionally called as the Blob is scanned, with the number of bytes scanned so far
+	// and the total size of the Blob.
+	OnProgress func(bytesScanned, totalBytes int64)
+
+	// OnError is optionally called for each non-fatal error reported whilst running the query (for example
+	// a record which couldn't be parsed, and has been skipped). Fatal errors are returned from the Reader.
+	OnError func(err QueryError)
+
+	Snapshot            *string
+	LeaseID             *string
+	IfModifiedSince     *string
+	IfUnmodifiedSince   *string
+	IfMatch             *string
+	IfNoneMatch         *string
+	CustomerProvidedKey *CustomerProvidedKey
+}
+
+type QueryResponse struct {
+	HttpResponse *http.Response
+
+	// Reader returns the results of the query in the OutputSerialization format, as they're decoded from the
+	// response. Reader must be closed once the results have been read.
+	Reader io.ReadCloser
+
+	BlobType     BlobType
+	ContentType  string
+	ETag         string
+	LastModified string
+}
+
+// Query runs a SQL expression against the contents of a Blob (which is either delimited text, JSON or Parquet),
+// returning only the matching data - rather than the entire contents of the Blob.
+func (c Client) Query(ctx context.Context, containerName, blobName string, input QueryInput) (result QueryResponse, err error) {
+	if containerName == "" {
+		err = fmt.Errorf("`containerName` cannot be an empty string")
+		return
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		err = fmt.Errorf("`containerName` must be a lower-cased string")
+		return
+	}
+
+	if blobName == "" {
+		err = fmt.Errorf("`blobName` cannot be an empty string")
+		return
+	}
+
+	if input.Expression == "" {
+		err = fmt.Errorf("`input.Expression` cannot be an empty string")
+		return
+	}
+
+	inputFormat, err := input.InputSerialization.format()
+	if err != nil {
+		err = fmt.Errorf("`input.InputSerialization` is not valid: %+v", err)
+		return
+	}
+	if inputFormat.Type == ArrowQueryFormat {
+		err = fmt.Errorf("`input.InputSerialization` cannot use the Arrow format, which is only supported for the output")
+		return
+	}
+
+	request := queryRequest{
+		QueryType:          "SQL",
+		Expression:         input.Expression,
+		InputSerialization: &querySerialization{Format: *inputFormat},
+	}
+	if input.OutputSerialization != nil {
+		outputFormat, err := input.OutputSerialization.format()
+		if err != nil {
+			return result, fmt.Errorf("`input.OutputSerialization` is not valid: %+v", err)
+		}
+		if outputFormat.Type == ParquetQueryFormat {
+			return result, fmt.Errorf("`input.OutputSerialization` cannot use the Parquet format, which is only supported for the input")
+		}
+		request.OutputSerialization = &querySerialization{Format: *outputFormat}
+	}
+
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
+	opts := client.RequestOptions{
+		ContentType: "application/xml; charset=utf-8",
+		ExpectedStatusCodes: []int{
+			http.StatusOK,
+			http.StatusPartialContent,
+		},
+		HttpMethod: http.MethodPost,
+		OptionsObject: queryOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	err = req.Marshal(&request)
+	if err != nil {
+		err = fmt.Errorf("marshalling request: %+v", err)
+		return
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "Query", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.BlobType = BlobType(resp.Header.Get("x-ms-blob-type"))
+				result.ContentType = resp.Header.Get("Content-Type")
+				result.ETag = resp.Header.Get("ETag")
+				result.LastModified = resp.Header.Get("Last-Modified")
+			}
+
+			if resp.Body != nil {
+				result.Reader = newQueryReader(resp.Body, input.OnProgress, input.OnError)
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+// format returns the Format element for the QuerySerialization, validating that exactly one configuration is specified
+func (s QuerySerialization) format() (*queryFormat, error) {
+	formats := make([]queryFormat, 0)
+	if s.DelimitedText != nil {
+		formats = append(formats, queryFormat{Type: DelimitedQueryFormat, DelimitedTextConfiguration: s.DelimitedText})
+	}
+	if s.JSON != nil {
+		formats = append(formats, queryFormat{Type: JSONQueryFormat, JSONTextConfiguration: s.JSON})
+	}
+	if s.Arrow != nil {
+		formats = append(formats, queryFormat{Type: ArrowQueryFormat, ArrowConfiguration: s.Arrow})
+	}
+	if s.Parquet != nil {
+		formats = append(formats, queryFormat{Type: ParquetQueryFormat, ParquetConfiguration: s.Parquet})
+	}
+	if len(formats) != 1 {
+		return nil, fmt.Errorf("exactly one of `DelimitedText`, `JSON`, `Arrow` or `Parquet` must be specified but got %d", len(formats))
+	}
+	return &formats[0], nil
+}
+
+type queryRequest struct {
+	XMLName             xml.Name            `xml:"QueryRequest"`
+	QueryType           string              `xml:"QueryType"`
+	Expression          string              `xml:"Expression"`
+	InputSerialization  *querySerialization `xml:"InputSerialization,omitempty"`
+	OutputSerialization *querySerialization `xml:"OutputSerialization,omitempty"`
+}
+
+type querySerialization struct {
+	Format queryFormat `xml:"Format"`
+}
+
+type queryFormat struct {
+	Type                       QueryFormatType             `xml:"Type"`
+	DelimitedTextConfiguration *DelimitedTextConfiguration `xml:"DelimitedTextConfiguration,omitempty"`
+	JSONTextConfiguration      *JSONTextConfiguration      `xml:"JsonTextConfiguration,omitempty"`
+	ArrowConfiguration         *ArrowConfiguration         `xml:"ArrowConfiguration,omitempty"`
+	ParquetConfiguration       *ParquetConfiguration       `xml:"ParquetTextConfiguration,omitempty"`
+}
+
+type queryOptions struct {
+	input QueryInput
+}
+
+func (q queryOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	if q.input.LeaseID != nil {
+		headers.Append("x-ms-lease-id", *q.input.LeaseID)
+	}
+	q.input.CustomerProvidedKey.appendHeaders(headers)
+	if q.input.IfModifiedSince != nil {
+		headers.Append("If-Modified-Since", *q.input.IfModifiedSince)
+	}
+	if q.input.IfUnmodifiedSince != nil {
+		headers.Append("If-Unmodified-Since", *q.input.IfUnmodifiedSince)
+	}
+	if q.input.IfMatch != nil {
+		headers.Append("If-Match", *q.input.IfMatch)
+	}
+	if q.input.IfNoneMatch != nil {
+		headers.Append("If-None-Match", *q.input.IfNoneMatch)
+	}
+	return headers
+}
+
+func (q queryOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (q queryOptions) ToQuery() *client.QueryParams {
+	out := &client.QueryParams{}
+	out.Append("comp", "query")
+	if q.input.Snapshot != nil {
+		out.Append("snapshot", *q.input.Snapshot)
+	}
+	return out
+}
@@ -0,0 +1,532 @@
+package blobs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// QueryError is an error reported by the Storage API whilst running a Query
+type QueryError struct {
+	// Fatal specifies whether the Query was stopped as a result of this error
+	Fatal bool
+
+	// The name and description of the error
+	Name        string
+	Description string
+
+	// The position within the Blob at which the error occurred
+	Position int64
+}
+
+func (e QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", e.Name, e.Position, e.Description)
+}
+
+// queryReader decodes the Avro-framed response of a Query, which contains a sequence of records - each being either
+// result data, progress, an error or the end of the results.
+type queryReader struct {
+	body       io.ReadCloser
+	avro       *avroReader
+	onProgress func(bytesScanned, totalBytes int64)
+	onError    func(err QueryError)
+
+	pending []byte
+	err     error
+}
+
+func newQueryReader(body io.ReadCloser, onProgress func(bytesScanned, totalBytes int64), onError func(err QueryError)) *queryReader {
+	return &queryReader{
+		body:       body,
+		avro:       newAvroReader(body),
+		onProgress: onProgress,
+		onError:    onError,
+	}
+}
+
+func (r *queryReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.next()
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *queryReader) Close() error {
+	return r.body.Close()
+}
+
+// next decodes the next record from the response - returning io.EOF once the end record has been read
+func (r *queryReader) next() error {
+	name, value, err := r.avro.next()
+	if err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("the query response ended before the end of the results: %w", io.ErrUnexpectedEOF)
+		}
+		return fmt.Errorf("decoding the query response: %+v", err)
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("decoding the query response: expected a record but got %T", value)
+	}
+
+	// the record names are namespaced, e.g. `com.microsoft.azure.storage.queryBlobContents.resultData`
+	switch name[strings.LastIndex(name, ".")+1:] {
+	case "resultData":
+		data, _ := record["data"].([]byte)
+		r.pending = data
+
+	case "progress":
+		if r.onProgress != nil {
+			bytesScanned, _ := record["bytesScanned"].(int64)
+			totalBytes, _ := record["totalBytes"].(int64)
+			r.onProgress(bytesScanned, totalBytes)
+		}
+
+	case "error":
+		queryErr := QueryError{}
+		queryErr.Fatal, _ = record["fatal"].(bool)
+		queryErr.Name, _ = record["name"].(string)
+		queryErr.Description, _ = record["description"].(string)
+		queryErr.Position, _ = record["position"].(int64)
+		if queryErr.Fatal {
+			return queryErr
+		}
+		if r.onError != nil {
+			r.onError(queryErr)
+		}
+
+	case "end":
+		if r.onProgress != nil {
+			totalBytes, _ := record["totalBytes"].(int64)
+			r.onProgress(totalBytes, totalBytes)
+		}
+		return io.EOF
+
+	default:
+		return fmt.Errorf("decoding the query response: unexpected record %q", name)
+	}
+
+	return nil
+}
+
+// avroMagic is the header of an Avro Object Container File
+var avroMagic = []byte{'O', 'b', 'j', 1}
+
+const avroSyncMarkerLength = 16
+
+// avroReader reads the objects within an Avro Object Container File, which is the format used for the response
+// of a Query. Only the `null` codec is supported, since that's the only codec used by the Storage API.
+type avroReader struct {
+	reader *bufio.Reader
+	schema *avroSchema
+	sync   []byte
+
+	// the remaining number of objects within the current block
+	remaining int64
+	err       error
+}
+
+func newAvroReader(reader io.Reader) *avroReader {
+	return &avroReader{
+		reader: bufio.NewReader(reader),
+	}
+}
+
+// next returns the name of the schema (which, for a union, is the name of the selected branch) and the value
+// of the next object - or io.EOF once all objects have been read
+func (r *avroReader) next() (string, interface{}, error) {
+	if r.err != nil {
+		return "", nil, r.err
+	}
+	if r.schema == nil {
+		if r.err = r.readHeader(); r.err != nil {
+			return "", nil, r.err
+		}
+	}
+
+	for r.remaining == 0 {
+		if r.err = r.readBlockHeader(); r.err != nil {
+			return "", nil, r.err
+		}
+	}
+
+	name, value, err := r.schema.decode(r.reader)
+	if err != nil {
+		r.err = err
+		return "", nil, err
+	}
+	r.remaining--
+
+	if r.remaining == 0 {
+		sync := make([]byte, avroSyncMarkerLength)
+		if _, err := io.ReadFull(r.reader, sync); err != nil {
+			r.err = fmt.Errorf("reading the sync marker: %+v", err)
+			return "", nil, r.err
+		}
+		if !bytes.Equal(sync, r.sync) {
+			r.err = fmt.Errorf("the sync marker following the block didn't match the sync marker in the header")
+			return "", nil, r.err
+		}
+	}
+
+	return name, value, nil
+}
+
+func (r *avroReader) readHeader() error {
+	magic := make([]byte, len(avroMagic))
+	if _, err := io.ReadFull(r.reader, magic); err != nil {
+		return fmt.Errorf("reading the header: %+v", err)
+	}
+	if !bytes.Equal(magic, avroMagic) {
+		return fmt.Errorf("the response isn't an avro object container file")
+	}
+
+	metaData := make(map[string][]byte)
+	for {
+		count, err := readAvroLong(r.reader)
+		if err != nil {
+			return fmt.Errorf("reading the header: %+v", err)
+		}
+		if count == 0 {
+			break
+		}
+		if count < 0 {
+			// a negative count is followed by the size of the block in bytes, which isn't needed
+			count = -count
+			if _, err := readAvroLong(r.reader); err != nil {
+				return fmt.Errorf("reading the header: %+v", err)
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			key, err := readAvroBytes(r.reader)
+			if err != nil {
+				return fmt.Errorf("reading the header: %+v", err)
+			}
+			value, err := readAvroBytes(r.reader)
+			if err != nil {
+				return fmt.Errorf("reading the header: %+v", err)
+			}
+			metaData[string(key)] = value
+		}
+	}
+
+	if codec, ok := metaData["avro.codec"]; ok && string(codec) != "null" {
+		return fmt.Errorf("the avro codec %q isn't supported", string(codec))
+	}
+	schema, err := parseAvroSchema(metaData["avro.schema"])
+	if err != nil {
+		return fmt.Errorf("parsing the avro schema: %+v", err)
+	}
+	r.schema = schema
+
+	r.sync = make([]byte, avroSyncMarkerLength)
+	if _, err := io.ReadFull(r.reader, r.sync); err != nil {
+		return fmt.Errorf("reading the sync marker: %+v", err)
+	}
+	return nil
+}
+
+func (r *avroReader) readBlockHeader() error {
+	count, err := readAvroLong(r.reader)
+	if err != nil {
+		// the end of the file is only expected between blocks
+		if err == io.ErrUnexpectedEOF {
+			return io.EOF
+		}
+		return fmt.Errorf("reading the block header: %+v", err)
+	}
+	if _, err := readAvroLong(r.reader); err != nil {
+		return fmt.Errorf("reading the block header: %+v", err)
+	}
+	if count < 0 {
+		return fmt.Errorf("the block contains a negative number of objects (%d)", count)
+	}
+	r.remaining = count
+	return nil
+}
+
+// avroSchema is the subset of an Avro Schema needed to decode the objects within a Query response
+type avroSchema struct {
+	Type   string
+	Name   string
+	Fields []avroField
+
+	// Branches are the possible schemas of a union
+	Branches []*avroSchema
+
+	// Items are the schema of the items within an array or the values within a map
+	Items *avroSchema
+
+	// Symbols are the possible values of an enum
+	Symbols []string
+
+	// Size is the number of bytes within a fixed
+	Size int
+}
+
+type avroField struct {
+	Name   string
+	Schema *avroSchema
+}
+
+func parseAvroSchema(input []byte) (*avroSchema, error) {
+	if len(input) == 0 {
+		return nil, fmt.Errorf("the schema is empty")
+	}
+	var raw interface{}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return nil, err
+	}
+	return buildAvroSchema(raw, make(map[string]*avroSchema))
+}
+
+func buildAvroSchema(raw interface{}, named map[string]*avroSchema) (*avroSchema, error) {
+	switch v := raw.(type) {
+	case string:
+		switch v {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &avroSchema{Type: v}, nil
+		}
+		if schema, ok := named[v]; ok {
+			return schema, nil
+		}
+		return nil, fmt.Errorf("the type %q isn't supported", v)
+
+	case []interface{}:
+		schema := &avroSchema{Type: "union"}
+		for _, item := range v {
+			branch, err := buildAvroSchema(item, named)
+			if err != nil {
+				return nil, err
+			}
+			schema.Branches = append(schema.Branches, branch)
+		}
+		return schema, nil
+
+	case map[string]interface{}:
+		schemaType, _ := v["type"].(string)
+		schema := &avroSchema{Type: schemaType}
+		schema.Name, _ = v["name"].(string)
+		if namespace, ok := v["namespace"].(string); ok && schema.Name != "" && !strings.Contains(schema.Name, ".") {
+			schema.Name = fmt.Sprintf("%s.%s", namespace, schema.Name)
+		}
+
+		switch schemaType {
+		case "record":
+			named[schema.Name] = schema
+			fields, _ := v["fields"].([]interface{})
+			for _, item := range fields {
+				field, ok := item.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("the fields of %q are invalid", schema.Name)
+				}
+				fieldName, _ := field["name"].(string)
+				fieldSchema, err := buildAvroSchema(field["type"], named)
+				if err != nil {
+					return nil, fmt.Errorf("field %q of %q: %+v", fieldName, schema.Name, err)
+				}
+				schema.Fields = append(schema.Fields, avroField{Name: fieldName, Schema: fieldSchema})
+			}
+
+		case "enum":
+			named[schema.Name] = schema
+			symbols, _ := v["symbols"].([]interface{})
+			for _, symbol := range symbols {
+				s, _ := symbol.(string)
+				schema.Symbols = append(schema.Symbols, s)
+			}
+
+		case "fixed":
+			named[schema.Name] = schema
+			size, _ := v["size"].(float64)
+			schema.Size = int(size)
+
+		case "array", "map":
+			key := "items"
+			if schemaType == "map" {
+				key = "values"
+			}
+			items, err := buildAvroSchema(v[key], named)
+			if err != nil {
+				return nil, err
+			}
+			schema.Items = items
+
+		default:
+			// primitive types can also be specified as `{"type": "long"}`
+			return buildAvroSchema(schemaType, named)
+		}
+		return schema, nil
+	}
+
+	return nil, fmt.Errorf("the schema %v is invalid", raw)
+}
+
+// decode reads a value of this schema, returning the name of the (selected) schema along with the value
+func (s *avroSchema) decode(reader *bufio.Reader) (string, interface{}, error) {
+	switch s.Type {
+	case "null":
+		return s.Type, nil, nil
+
+	case "boolean":
+		b, err := reader.ReadByte()
+		if err != nil {
+			return "", nil, unexpectedEOF(err)
+		}
+		return s.Type, b != 0, nil
+
+	case "int":
+		v, err := readAvroLong(reader)
+		return s.Type, int32(v), err
+
+	case "long":
+		v, err := readAvroLong(reader)
+		return s.Type, v, err
+
+	case "float":
+		b := make([]byte, 4)
+		if _, err := io.ReadFull(reader, b); err != nil {
+			return "", nil, unexpectedEOF(err)
+		}
+		return s.Type, math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+
+	case "double":
+		b := make([]byte, 8)
+		if _, err := io.ReadFull(reader, b); err != nil {
+			return "", nil, unexpectedEOF(err)
+		}
+		return s.Type, math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+
+	case "bytes":
+		v, err := readAvroBytes(reader)
+		return s.Type, v, err
+
+	case "string":
+		v, err := readAvroBytes(reader)
+		return s.Type, string(v), err
+
+	case "fixed":
+		b := make([]byte, s.Size)
+		if _, err := io.ReadFull(reader, b); err != nil {
+			return "", nil, unexpectedEOF(err)
+		}
+		return s.Name, b, nil
+
+	case "enum":
+		index, err := readAvroLong(reader)
+		if err != nil {
+			return "", nil, err
+		}
+		if index < 0 || index >= int64(len(s.Symbols)) {
+			return "", nil, fmt.Errorf("the enum index %d is out of range for %q", index, s.Name)
+		}
+		return s.Name, s.Symbols[index], nil
+
+	case "union":
+		index, err := readAvroLong(reader)
+		if err != nil {
+			return "", nil, err
+		}
+		if index < 0 || index >= int64(len(s.Branches)) {
+			return "", nil, fmt.Errorf("the union index %d is out of range", index)
+		}
+		return s.Branches[index].decode(reader)
+
+	case "record":
+		record := make(map[string]interface{}, len(s.Fields))
+		for _, field := range s.Fields {
+			_, value, err := field.Schema.decode(reader)
+			if err != nil {
+				return "", nil, fmt.Errorf("decoding field %q of %q: %+v", field.Name, s.Name, err)
+			}
+			record[field.Name] = value
+		}
+		return s.Name, record, nil
+
+	case "array", "map":
+		items := make([]interface{}, 0)
+		values := make(map[string]interface{})
+		for {
+			count, err := readAvroLong(reader)
+			if err != nil {
+				return "", nil, err
+			}
+			if count == 0 {
+				break
+			}
+			if count < 0 {
+				count = -count
+				if _, err := readAvroLong(reader); err != nil {
+					return "", nil, err
+				}
+			}
+			for i := int64(0); i < count; i++ {
+				var key []byte
+				if s.Type == "map" {
+					if key, err = readAvroBytes(reader); err != nil {
+						return "", nil, err
+					}
+				}
+				_, value, err := s.Items.decode(reader)
+				if err != nil {
+					return "", nil, err
+				}
+				if s.Type == "map" {
+					values[string(key)] = value
+				} else {
+					items = append(items, value)
+				}
+			}
+		}
+		if s.Type == "map" {
+			return s.Type, values, nil
+		}
+		return s.Type, items, nil
+	}
+
+	return "", nil, fmt.Errorf("the type %q isn't supported", s.Type)
+}
+
+// readAvroLong reads a zig-zag encoded variable-length integer
+func readAvroLong(reader *bufio.Reader) (int64, error) {
+	v, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+// readAvroBytes reads a sequence of bytes prefixed with its length
+func readAvroBytes(reader *bufio.Reader) ([]byte, error) {
+	length, err := readAvroLong(reader)
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("the length %d cannot be negative", length)
+	}
+	b := make([]byte, length)
+	if _, err := io.ReadFull(reader, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
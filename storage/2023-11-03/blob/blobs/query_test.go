@@ -0,0 +1,137 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestQuery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	blobClient := buildBlobClient(t, ctx, server)
+	containerName := "container1"
+
+	csv := &strings.Builder{}
+	csv.WriteString("level,service,message\n")
+	expected := &strings.Builder{}
+	for i := 0; i < 200; i++ {
+		level := "info"
+		if i%10 == 0 {
+			level = "error"
+			expected.WriteString(fmt.Sprintf("error|api|request %d failed\n", i))
+		}
+		csv.WriteString(fmt.Sprintf("%s,api,request %d failed\n", level, i))
+	}
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, "logs.csv", PutBlockBlobInput{Content: pointer.To([]byte(csv.String()))}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Querying CSV..")
+	progress := make([]int64, 0)
+	result, err := blobClient.Query(ctx, containerName, "logs.csv", QueryInput{
+		Expression: "SELECT * FROM BlobStorage WHERE level = 'error'",
+		InputSerialization: QuerySerialization{
+			DelimitedText: &DelimitedTextConfiguration{
+				ColumnSeparator: ",",
+				RecordSeparator: "\n",
+				HasHeaders:      true,
+			},
+		},
+		OutputSerialization: &QuerySerialization{
+			DelimitedText: &DelimitedTextConfiguration{
+				ColumnSeparator: "|",
+				RecordSeparator: "\n",
+			},
+		},
+		OnProgress: func(bytesScanned, totalBytes int64) {
+			if totalBytes != int64(csv.Len()) {
+				t.Errorf("expected the total bytes to be %d but got %d", csv.Len(), totalBytes)
+			}
+			progress = append(progress, bytesScanned)
+		},
+	})
+	if err != nil {
+		t.Fatalf("querying blob: %+v", err)
+	}
+	actual := readQueryResults(t, result)
+	if actual != expected.String() {
+		t.Fatalf("expected the results to be %q but got %q", expected.String(), actual)
+	}
+	if len(progress) < 2 || progress[len(progress)-1] != int64(csv.Len()) {
+		t.Fatalf("expected progress to be reported until all %d bytes were scanned but got %v", csv.Len(), progress)
+	}
+
+	t.Logf("[DEBUG] Querying JSON with an invalid record..")
+	json := "{\"level\":\"info\",\"id\":1}\n{\"level\":\"error\",\"id\":2}\nnot json\n{\"level\":\"error\",\"id\":3}\n"
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, "logs.json", PutBlockBlobInput{Content: pointer.To([]byte(json))}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	queryErrors := make([]QueryError, 0)
+	result, err = blobClient.Query(ctx, containerName, "logs.json", QueryInput{
+		Expression:         "SELECT * FROM BlobStorage WHERE level = 'error'",
+		InputSerialization: QuerySerialization{JSON: &JSONTextConfiguration{}},
+		OnError: func(err QueryError) {
+			queryErrors = append(queryErrors, err)
+		},
+	})
+	if err != nil {
+		t.Fatalf("querying blob: %+v", err)
+	}
+	actual = readQueryResults(t, result)
+	if expected := "{\"id\":2,\"level\":\"error\"}\n{\"id\":3,\"level\":\"error\"}\n"; actual != expected {
+		t.Fatalf("expected the results to be %q but got %q", expected, actual)
+	}
+	if position := int64(strings.Index(json, "not json")); len(queryErrors) != 1 || queryErrors[0].Fatal || queryErrors[0].Position != position {
+		t.Fatalf("expected a single non-fatal error at position %d but got %+v", position, queryErrors)
+	}
+
+	t.Logf("[DEBUG] Querying a column which doesn't exist..")
+	result, err = blobClient.Query(ctx, containerName, "logs.csv", QueryInput{
+		Expression:         "SELECT * FROM BlobStorage WHERE nope = 'error'",
+		InputSerialization: QuerySerialization{DelimitedText: &DelimitedTextConfiguration{HasHeaders: true}},
+	})
+	if err != nil {
+		t.Fatalf("querying blob: %+v", err)
+	}
+	defer result.Reader.Close()
+	var queryErr QueryError
+	if _, err := io.ReadAll(result.Reader); !errors.As(err, &queryErr) || !queryErr.Fatal {
+		t.Fatalf("expected a fatal QueryError but got: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Validating the Serialization..")
+	_, err = blobClient.Query(ctx, containerName, "logs.csv", QueryInput{
+		Expression:         "SELECT * FROM BlobStorage",
+		InputSerialization: QuerySerialization{DelimitedText: &DelimitedTextConfiguration{}, JSON: &JSONTextConfiguration{}},
+	})
+	if err == nil {
+		t.Fatalf("expected an error when multiple input formats are specified but didn't get one")
+	}
+	_, err = blobClient.Query(ctx, containerName, "logs.csv", QueryInput{
+		Expression:          "SELECT * FROM BlobStorage",
+		InputSerialization:  QuerySerialization{DelimitedText: &DelimitedTextConfiguration{}},
+		OutputSerialization: &QuerySerialization{Parquet: &ParquetConfiguration{}},
+	})
+	if err == nil {
+		t.Fatalf("expected an error when Parquet is used as the output format but didn't get one")
+	}
+}
+
+func readQueryResults(t *testing.T, result QueryResponse) string {
+	defer result.Reader.Close()
+	b, err := io.ReadAll(result.Reader)
+	if err != nil {
+		t.Fatalf("reading query results: %+v", err)
+	}
+	return string(b)
+}
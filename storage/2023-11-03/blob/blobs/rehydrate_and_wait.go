@@ -0,0 +1,49 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client/pollers"
+)
+
+type RehydrateAndWaitOptions struct {
+	// PollInterval is how often the status of the rehydration is checked. Defaults to 1 minute, since rehydrating
+	// a blob from the Archive tier can take up to 15 hours.
+	PollInterval time.Duration
+
+	// Progress is optionally called each time the status of the rehydration is checked, with the archive status
+	// of the blob - which is `rehydrate-pending-to-{tier}` until the blob has been rehydrated.
+	Progress func(archiveStatus ArchiveStatus)
+}
+
+// RehydrateAndWait moves an archived blob into the online tier specified in `input` and waits until the blob has
+// been rehydrated, at which point the blob can be read. `ctx` must have a deadline, which bounds how long to wait.
+func (c Client) RehydrateAndWait(ctx context.Context, containerName, blobName string, input SetTierInput) error {
+	return c.RehydrateAndWaitWithOptions(ctx, containerName, blobName, input, RehydrateAndWaitOptions{})
+}
+
+// RehydrateAndWaitWithOptions moves an archived blob into the online tier specified in `input` and waits until the
+// blob has been rehydrated, using the specified options. A blob which is already in an online tier is moved into
+// the specified tier without waiting. `ctx` must have a deadline, which bounds how long to wait - transient failures
+// to retrieve the status of the rehydration are retried until then.
+func (c Client) RehydrateAndWaitWithOptions(ctx context.Context, containerName, blobName string, input SetTierInput, options RehydrateAndWaitOptions) error {
+	if input.Tier != Hot && input.Tier != Cool && input.Tier != Cold {
+		return fmt.Errorf("`input.Tier` must be one of %q, %q or %q but got %q", string(Hot), string(Cool), string(Cold), string(input.Tier))
+	}
+
+	if _, err := c.SetTier(ctx, containerName, blobName, input); err != nil {
+		return fmt.Errorf("setting the tier: %+v", err)
+	}
+
+	pollerType := NewRehydrateAndWaitPoller(&c, containerName, blobName, input)
+	pollerType.pollInterval = options.PollInterval
+	pollerType.progress = options.Progress
+	poller := pollers.NewPoller(pollerType, pollerType.interval(), pollers.DefaultNumberOfDroppedConnectionsToAllow)
+	if err := poller.PollUntilDone(ctx); err != nil {
+		return fmt.Errorf("waiting for blob to be rehydrated: %+v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client/pollers"
+)
+
+var _ pollers.PollerType = &rehydrateAndWaitPoller{}
+
+func NewRehydrateAndWaitPoller(client *Client, containerName, blobName string, input SetTierInput) *rehydrateAndWaitPoller {
+	return &rehydrateAndWaitPoller{
+		client:        client,
+		containerName: containerName,
+		blobName:      blobName,
+		snapshot:      input.Snapshot,
+		versionID:     input.VersionID,
+	}
+}
+
+type rehydrateAndWaitPoller struct {
+	client        *Client
+	containerName string
+	blobName      string
+	snapshot      *string
+	versionID     *string
+
+	// pollInterval is how often the status of the rehydration is checked, defaulting to 1 minute
+	pollInterval time.Duration
+
+	// progress is optionally called with the archive status of the blob each time the status is checked
+	progress func(archiveStatus ArchiveStatus)
+}
+
+func (p *rehydrateAndWaitPoller) interval() time.Duration {
+	if p.pollInterval > 0 {
+		return p.pollInterval
+	}
+	return 1 * time.Minute
+}
+
+func (p *rehydrateAndWaitPoller) Poll(ctx context.Context) (*pollers.PollResult, error) {
+	var props GetPropertiesResponse
+	var err error
+	if p.snapshot != nil {
+		props, err = p.client.GetSnapshotProperties(ctx, p.containerName, p.blobName, GetSnapshotPropertiesInput{
+			SnapshotID: *p.snapshot,
+		})
+	} else {
+		props, err = p.client.GetProperties(ctx, p.containerName, p.blobName, GetPropertiesInput{
+			VersionID: p.versionID,
+		})
+	}
+	if err != nil {
+		if isTransientFailure(props.HttpResponse) {
+			// since rehydration can take hours, a transient failure (such as a dropped connection or throttling)
+			// shouldn't end the wait - the status is checked again at the next interval, until `ctx` is done
+			return &pollers.PollResult{
+				Status:       pollers.PollingStatusInProgress,
+				PollInterval: p.interval(),
+			}, nil
+		}
+		return nil, fmt.Errorf("retrieving properties (container: %s blob: %s) : %+v", p.containerName, p.blobName, err)
+	}
+
+	if p.progress != nil {
+		p.progress(props.ArchiveStatus)
+	}
+
+	// the Archive Status is `rehydrate-pending-to-{tier}` (e.g. `rehydrate-pending-to-cold`) until the blob is readable
+	if strings.HasPrefix(strings.ToLower(string(props.ArchiveStatus)), "rehydrate-pending-to-") {
+		return &pollers.PollResult{
+			Status:       pollers.PollingStatusInProgress,
+			PollInterval: p.interval(),
+		}, nil
+	}
+
+	if strings.EqualFold(string(props.AccessTier), string(Archive)) {
+		return nil, fmt.Errorf("the blob (container: %s blob: %s) is in the %q tier and is not being rehydrated", p.containerName, p.blobName, string(Archive))
+	}
+
+	return &pollers.PollResult{
+		Status:       pollers.PollingStatusSucceeded,
+		PollInterval: p.interval(),
+	}, nil
+}
+
+// isTransientFailure returns whether a request which returned `resp` (which is nil if no response was received)
+// failed for a reason which may resolve itself, such as a dropped connection, throttling or a server error
+func isTransientFailure(resp *http.Response) bool {
+	if resp == nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,160 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+	"github.com/tombuildsstuff/giovanni/storage/retry"
+)
+
+func TestRehydrateAndWait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	server.RehydrationDelay = 500 * time.Millisecond
+	blobClient := buildBlobClient(t, ctx, server)
+	containerName := "container1"
+	fileName := "archived.txt"
+	content := []byte("hello from the archive")
+
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, fileName, PutBlockBlobInput{Content: pointer.To(content)}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	if _, err := blobClient.SetTier(ctx, containerName, fileName, SetTierInput{Tier: Archive}); err != nil {
+		t.Fatalf("archiving blob: %+v", err)
+	}
+	if _, err := blobClient.Get(ctx, containerName, fileName, GetInput{}); err == nil {
+		t.Fatalf("expected an error reading an archived blob but didn't get one")
+	}
+
+	t.Logf("[DEBUG] Copying the Archived Blob into an Online Tier..")
+	copySource := fmt.Sprintf("%s/%s/%s", server.BaseUri(), containerName, fileName)
+	if _, err := blobClient.Copy(ctx, containerName, "no-tier.txt", CopyInput{CopySource: copySource}); err == nil {
+		t.Fatalf("expected an error copying an archived blob without an access tier but didn't get one")
+	}
+	copyInput := CopyInput{
+		CopySource:        copySource,
+		AccessTier:        pointer.To(Cool),
+		RehydratePriority: pointer.To(HighRehydratePriority),
+	}
+	if err := blobClient.CopyAndWaitWithOptions(ctx, containerName, "copied.txt", copyInput, CopyAndWaitOptions{PollInterval: 100 * time.Millisecond}); err != nil {
+		t.Fatalf("copying blob: %+v", err)
+	}
+	props, err := blobClient.GetProperties(ctx, containerName, "copied.txt", GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.AccessTier != Cool || props.ArchiveStatus != None {
+		t.Fatalf("expected the copy to be rehydrated into %q but got %q with the archive status %q", string(Cool), string(props.AccessTier), string(props.ArchiveStatus))
+	}
+	assertBlobContent(t, ctx, blobClient, containerName, "copied.txt", content)
+
+	t.Logf("[DEBUG] Rehydrating the Archived Blob..")
+	if err := blobClient.RehydrateAndWait(ctx, containerName, fileName, SetTierInput{Tier: Archive}); err == nil {
+		t.Fatalf("expected an error rehydrating into the Archive tier but didn't get one")
+	}
+	statuses := make([]ArchiveStatus, 0)
+	options := RehydrateAndWaitOptions{
+		PollInterval: 100 * time.Millisecond,
+		Progress: func(archiveStatus ArchiveStatus) {
+			statuses = append(statuses, archiveStatus)
+		},
+	}
+	if err := blobClient.RehydrateAndWaitWithOptions(ctx, containerName, fileName, SetTierInput{Tier: Cold}, options); err != nil {
+		t.Fatalf("rehydrating blob: %+v", err)
+	}
+	if len(statuses) < 2 || statuses[0] != RehydratePendingToCold || statuses[len(statuses)-1] != None {
+		t.Fatalf("expected the archive status to progress from %q until it was rehydrated but got %+v", string(RehydratePendingToCold), statuses)
+	}
+	assertBlobContent(t, ctx, blobClient, containerName, fileName, content)
+
+	t.Logf("[DEBUG] Raising the Rehydrate Priority..")
+	if _, err := blobClient.SetTier(ctx, containerName, fileName, SetTierInput{Tier: Archive}); err != nil {
+		t.Fatalf("archiving blob: %+v", err)
+	}
+	if _, err := blobClient.SetTier(ctx, containerName, fileName, SetTierInput{Tier: Hot, RehydratePriority: pointer.To(StandardRehydratePriority)}); err != nil {
+		t.Fatalf("rehydrating blob: %+v", err)
+	}
+	if _, err := blobClient.SetTier(ctx, containerName, fileName, SetTierInput{Tier: Hot, RehydratePriority: pointer.To(HighRehydratePriority)}); err != nil {
+		t.Fatalf("raising the rehydrate priority: %+v", err)
+	}
+	props, err = blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.ArchiveStatus != RehydratePendingToHot || props.RehydratePriority != HighRehydratePriority {
+		t.Fatalf("expected the blob to be rehydrating to %q with %q priority but got %q with %q priority", string(Hot), string(HighRehydratePriority), string(props.ArchiveStatus), string(props.RehydratePriority))
+	}
+	if _, err := blobClient.SetTier(ctx, containerName, fileName, SetTierInput{Tier: Cool}); err == nil {
+		t.Fatalf("expected an error changing the tier of a blob being rehydrated but didn't get one")
+	}
+}
+
+func TestRehydrateAndWaitTransientFailures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	server.RehydrationDelay = 500 * time.Millisecond
+	blobClient := buildBlobClient(t, ctx, server)
+	containerName := "container1"
+	fileName := "archived.txt"
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, fileName, PutBlockBlobInput{Content: pointer.To([]byte("hello"))}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	if _, err := blobClient.SetTier(ctx, containerName, fileName, SetTierInput{Tier: Archive}); err != nil {
+		t.Fatalf("archiving blob: %+v", err)
+	}
+
+	// the first couple of attempts to retrieve the status of the rehydration are throttled
+	var mu sync.Mutex
+	throttled := 0
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			mu.Lock()
+			throttle := throttled < 2
+			throttled++
+			mu.Unlock()
+			if throttle {
+				w.Header().Set("x-ms-error-code", "ServerBusy")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	proxyClient, err := NewWithBaseUri(proxy.URL + "/" + server.AccountName)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	proxyClient.Client.SetAuthorizer(authorizer)
+	proxyClient.RetryPolicy = &retry.Policy{MaxAttempts: 1}
+
+	options := RehydrateAndWaitOptions{
+		PollInterval: 100 * time.Millisecond,
+	}
+	if err := proxyClient.RehydrateAndWaitWithOptions(ctx, containerName, fileName, SetTierInput{Tier: Hot}, options); err != nil {
+		t.Fatalf("rehydrating blob: %+v", err)
+	}
+	assertBlobContent(t, ctx, blobClient, containerName, fileName, []byte("hello"))
+
+	t.Logf("[DEBUG] Polling a Blob which doesn't exist..")
+	pollerType := NewRehydrateAndWaitPoller(proxyClient, containerName, "deleted.txt", SetTierInput{})
+	if _, err := pollerType.Poll(ctx); err == nil {
+		t.Fatalf("expected an error polling a blob which doesn't exist but didn't get one")
+	}
+}
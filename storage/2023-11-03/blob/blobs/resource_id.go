@@ -23,6 +23,12 @@ type BlobId struct {
 
 	// BlobName specifies the name of this Blob.
 	BlobName string
+
+	// Snapshot optionally specifies a Snapshot of this Blob.
+	Snapshot *string
+
+	// VersionID optionally specifies a Version of this Blob.
+	VersionID *string
 }
 
 func NewBlobID(accountId accounts.AccountId, containerName, blobName string) BlobId {
@@ -34,7 +40,18 @@ func NewBlobID(accountId accounts.AccountId, containerName, blobName string) Blo
 }
 
 func (b BlobId) ID() string {
-	return fmt.Sprintf("%s/%s/%s", b.AccountId.ID(), b.ContainerName, b.BlobName)
+	id := fmt.Sprintf("%s/%s/%s", b.AccountId.ID(), b.ContainerName, b.BlobName)
+	query := url.Values{}
+	if b.Snapshot != nil {
+		query.Set("snapshot", *b.Snapshot)
+	}
+	if b.VersionID != nil {
+		query.Set("versionid", *b.VersionID)
+	}
+	if len(query) > 0 {
+		id = fmt.Sprintf("%s?%s", id, query.Encode())
+	}
+	return id
 }
 
 func (b BlobId) String() string {
@@ -42,17 +59,25 @@ func (b BlobId) String() string {
 		fmt.Sprintf("Account %q", b.AccountId.String()),
 		fmt.Sprintf("Container Name %q", b.ContainerName),
 	}
+	if b.Snapshot != nil {
+		components = append(components, fmt.Sprintf("Snapshot %q", *b.Snapshot))
+	}
+	if b.VersionID != nil {
+		components = append(components, fmt.Sprintf("Version ID %q", *b.VersionID))
+	}
 	return fmt.Sprintf("Blob %q (%s)", b.BlobName, strings.Join(components, " / "))
 }
 
-// ParseBlobID parses `input` into a Blob ID using a known `domainSuffix`
+// ParseBlobID parses `input` into a Blob ID using a known `domainSuffix` - including the
+// Snapshot or Version ID specified in the `snapshot` or `versionid` query-string values
 func ParseBlobID(input, domainSuffix string) (*BlobId, error) {
 	// example: https://foo.blob.core.windows.net/Bar/example.vhd
+	// example: https://foo.blob.core.windows.net/Bar/example.vhd?snapshot=2024-01-02T03:04:05.0000000Z
 	if input == "" {
 		return nil, fmt.Errorf("`input` was empty")
 	}
 
-	account, err := accounts.ParseAccountID(input, domainSuffix)
+	account, err := accounts.ParseAccountIDWithSubDomainType(input, domainSuffix, accounts.BlobSubDomainType)
 	if err != nil {
 		return nil, fmt.Errorf("parsing account %q: %+v", input, err)
 	}
@@ -66,7 +91,10 @@ func ParseBlobID(input, domainSuffix string) (*BlobId, error) {
 		return nil, fmt.Errorf("parsing %q as a uri: %+v", input, err)
 	}
 
-	path := strings.TrimPrefix(uri.Path, "/")
+	path, err := account.ResourcePath(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %+v", input, err)
+	}
 	segments := strings.Split(path, "/")
 	if len(segments) < 2 {
 		return nil, fmt.Errorf("expected the path to contain at least 2 segments but got %d", len(segments))
@@ -75,9 +103,22 @@ func ParseBlobID(input, domainSuffix string) (*BlobId, error) {
 	containerName := segments[0]
 	blobName := strings.TrimPrefix(path, containerName)
 	blobName = strings.TrimPrefix(blobName, "/")
-	return &BlobId{
+	id := BlobId{
 		AccountId:     *account,
 		ContainerName: containerName,
 		BlobName:      blobName,
-	}, nil
+	}
+
+	query := uri.Query()
+	if v := query.Get("snapshot"); v != "" {
+		id.Snapshot = &v
+	}
+	if v := query.Get("versionid"); v != "" {
+		id.VersionID = &v
+	}
+	if id.Snapshot != nil && id.VersionID != nil {
+		return nil, fmt.Errorf("expected only one of `snapshot` and `versionid` to be specified in %q", input)
+	}
+
+	return &id, nil
 }
@@ -231,3 +231,81 @@ func TestFormatBlobIDInEdgeZone(t *testing.T) {
 		t.Fatalf("expected %q but got %q", expected, actual)
 	}
 }
+
+func TestParseBlobIDPathStyle(t *testing.T) {
+	input := "http://127.0.0.1:10000/devstoreaccount1/container1/more/nested/blob1.vhd"
+	actual, err := ParseBlobID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountId.AccountName != "devstoreaccount1" {
+		t.Fatalf("expected AccountName to be %q but got %q", "devstoreaccount1", actual.AccountId.AccountName)
+	}
+	if !actual.AccountId.IsPathStyle {
+		t.Fatalf("expected IsPathStyle to be true but got %t", actual.AccountId.IsPathStyle)
+	}
+	if actual.ContainerName != "container1" {
+		t.Fatalf("expected ContainerName to be %q but got %q", "container1", actual.ContainerName)
+	}
+	if actual.BlobName != "more/nested/blob1.vhd" {
+		t.Fatalf("expected BlobName to be %q but got %q", "more/nested/blob1.vhd", actual.BlobName)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
+
+func TestParseBlobIDWithSnapshot(t *testing.T) {
+	input := "https://example1.blob.core.windows.net/container1/blob1.vhd?snapshot=2024-01-02T03%3A04%3A05.0000000Z"
+	actual, err := ParseBlobID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.BlobName != "blob1.vhd" {
+		t.Fatalf("expected BlobName to be %q but got %q", "blob1.vhd", actual.BlobName)
+	}
+	if actual.Snapshot == nil || *actual.Snapshot != "2024-01-02T03:04:05.0000000Z" {
+		t.Fatalf("expected Snapshot to be %q but got %v", "2024-01-02T03:04:05.0000000Z", actual.Snapshot)
+	}
+	if actual.VersionID != nil {
+		t.Fatalf("expected VersionID to be nil but got %q", *actual.VersionID)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
+
+func TestParseBlobIDWithVersion(t *testing.T) {
+	input := "http://127.0.0.1:10000/devstoreaccount1/container1/blob1.vhd?versionid=2024-01-02T03%3A04%3A05.0000000Z"
+	actual, err := ParseBlobID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.VersionID == nil || *actual.VersionID != "2024-01-02T03:04:05.0000000Z" {
+		t.Fatalf("expected VersionID to be %q but got %v", "2024-01-02T03:04:05.0000000Z", actual.VersionID)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+
+	if _, err := ParseBlobID(input+"&snapshot=2024-01-02T03%3A04%3A05.0000000Z", "core.windows.net"); err == nil {
+		t.Fatalf("expected an error when both a Snapshot and a Version ID are specified")
+	}
+}
+
+func TestFormatBlobIDWithSnapshot(t *testing.T) {
+	actual := BlobId{
+		AccountId: accounts.AccountId{
+			AccountName:   "example1",
+			SubDomainType: accounts.BlobSubDomainType,
+			DomainSuffix:  "core.windows.net",
+		},
+		ContainerName: "container1",
+		BlobName:      "somefile.vhd",
+		Snapshot:      pointer.To("2024-01-02T03:04:05.0000000Z"),
+	}.ID()
+	expected := "https://example1.blob.core.windows.net/container1/somefile.vhd?snapshot=2024-01-02T03%3A04%3A05.0000000Z"
+	if actual != expected {
+		t.Fatalf("expected %q but got %q", expected, actual)
+	}
+}
@@ -12,6 +12,19 @@ import (
 
 type SetTierInput struct {
 	Tier AccessTier
+
+	// The priority with which an archived blob should be rehydrated into an online tier - either High or Standard.
+	// If not specified the blob is rehydrated using Standard priority. A pending rehydration can only be changed
+	// from Standard to High priority.
+	RehydratePriority *RehydratePriority
+
+	// The Snapshot of the blob on which the tier should be set
+	// If not specified the tier is set on the base blob.
+	Snapshot *string
+
+	// The Version ID of the blob on which the tier should be set
+	// If not specified the tier is set on the current version of the blob.
+	VersionID *string
 }
 
 type SetTierResponse struct {
@@ -35,6 +48,21 @@ func (c Client) SetTier(ctx context.Context, containerName, blobName string, inp
 		return
 	}
 
+	if input.VersionID != nil && *input.VersionID == "" {
+		err = fmt.Errorf("`input.VersionID` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if input.Snapshot != nil && *input.Snapshot == "" {
+		err = fmt.Errorf("`input.Snapshot` should either be specified or nil, not an empty string")
+		return
+	}
+
+	if input.Snapshot != nil && input.VersionID != nil {
+		err = fmt.Errorf("only one of `input.Snapshot` and `input.VersionID` can be specified")
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusOK,
@@ -42,7 +70,10 @@ func (c Client) SetTier(ctx context.Context, containerName, blobName string, inp
 		},
 		HttpMethod: http.MethodPut,
 		OptionsObject: setTierOptions{
-			tier: input.Tier,
+			tier:              input.Tier,
+			rehydratePriority: input.RehydratePriority,
+			snapshot:          input.Snapshot,
+			versionID:         input.VersionID,
 		},
 		Path: fmt.Sprintf("/%s/%s", containerName, blobName),
 	}
@@ -54,7 +85,7 @@ func (c Client) SetTier(ctx context.Context, containerName, blobName string, inp
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetTier", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -67,12 +98,18 @@ func (c Client) SetTier(ctx context.Context, containerName, blobName string, inp
 }
 
 type setTierOptions struct {
-	tier AccessTier
+	tier              AccessTier
+	rehydratePriority *RehydratePriority
+	snapshot          *string
+	versionID         *string
 }
 
 func (s setTierOptions) ToHeaders() *client.Headers {
 	headers := &client.Headers{}
 	headers.Append("x-ms-access-tier", string(s.tier))
+	if s.rehydratePriority != nil {
+		headers.Append("x-ms-rehydrate-priority", string(*s.rehydratePriority))
+	}
 	return headers
 }
 
@@ -83,5 +120,11 @@ func (s setTierOptions) ToOData() *odata.Query {
 func (s setTierOptions) ToQuery() *client.QueryParams {
 	out := &client.QueryParams{}
 	out.Append("comp", "tier")
+	if s.snapshot != nil {
+		out.Append("snapshot", *s.snapshot)
+	}
+	if s.versionID != nil {
+		out.Append("versionid", *s.versionID)
+	}
 	return out
 }
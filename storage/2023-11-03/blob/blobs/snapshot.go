@@ -19,6 +19,9 @@ type SnapshotInput struct {
 	// The encryption scope to set for the request content.
 	EncryptionScope *string
 
+	// The encryption key provided by the client, which is used to encrypt the blob (and is not stored by Azure)
+	CustomerProvidedKey *CustomerProvidedKey
+
 	// MetaData is a user-defined name-value pair associated with the blob.
 	// If no name-value pairs are specified, the operation will copy the base blob metadata to the snapshot.
 	// If one or more name-value pairs are specified, the snapshot is created with the specified metadata,
@@ -53,6 +56,9 @@ type SnapshotResponse struct {
 	// The value of this header indicates the snapshot version,
 	// and may be used in subsequent requests to access the snapshot.
 	SnapshotDateTime string
+
+	// The Version ID of the base blob, which is returned when Versioning is enabled for the Storage Account
+	VersionID string
 }
 
 // Snapshot captures a Snapshot of a given Blob
@@ -77,6 +83,11 @@ func (c Client) Snapshot(ctx context.Context, containerName, blobName string, in
 		return
 	}
 
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusCreated,
@@ -95,7 +106,7 @@ func (c Client) Snapshot(ctx context.Context, containerName, blobName string, in
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Snapshot", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -103,6 +114,7 @@ func (c Client) Snapshot(ctx context.Context, containerName, blobName string, in
 			if resp.Header != nil {
 				result.ETag = resp.Header.Get("ETag")
 				result.SnapshotDateTime = resp.Header.Get("x-ms-snapshot")
+				result.VersionID = resp.Header.Get("x-ms-version-id")
 			}
 		}
 	}
@@ -128,6 +140,7 @@ func (s snapshotOptions) ToHeaders() *client.Headers {
 	if s.input.EncryptionScope != nil {
 		headers.Append("x-ms-encryption-scope", *s.input.EncryptionScope)
 	}
+	s.input.CustomerProvidedKey.appendHeaders(headers)
 
 	if s.input.IfModifiedSince != nil {
 		headers.Append("If-Modified-Since", *s.input.IfModifiedSince)
@@ -19,6 +19,10 @@ type GetSnapshotPropertiesInput struct {
 
 	// The ID of the Snapshot which should be retrieved
 	SnapshotID string
+
+	// The encryption key provided by the client when the blob was written
+	// This must be specified if the blob is encrypted using a Customer-Provided Key
+	CustomerProvidedKey *CustomerProvidedKey
 }
 
 // GetSnapshotProperties returns all user-defined metadata, standard HTTP properties, and system properties for
@@ -44,6 +48,11 @@ func (c Client) GetSnapshotProperties(ctx context.Context, containerName, blobNa
 		return
 	}
 
+	if err = input.CustomerProvidedKey.validate(); err != nil {
+		err = fmt.Errorf("`input.CustomerProvidedKey` is not valid: %+v", err)
+		return
+	}
+
 	opts := client.RequestOptions{
 		ExpectedStatusCodes: []int{
 			http.StatusOK,
@@ -62,7 +71,7 @@ func (c Client) GetSnapshotProperties(ctx context.Context, containerName, blobNa
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetSnapshotProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -93,6 +102,9 @@ func (c Client) GetSnapshotProperties(ctx context.Context, containerName, blobNa
 				result.LeaseDuration = LeaseDuration(resp.Header.Get("x-ms-lease-duration"))
 				result.LeaseState = LeaseState(resp.Header.Get("x-ms-lease-state"))
 				result.LeaseStatus = LeaseStatus(resp.Header.Get("x-ms-lease-status"))
+				result.RehydratePriority = RehydratePriority(resp.Header.Get("x-ms-rehydrate-priority"))
+				result.EncryptionScope = resp.Header.Get("x-ms-encryption-scope")
+				result.EncryptionKeySHA256 = resp.Header.Get("x-ms-encryption-key-sha256")
 				result.MetaData = metadata.ParseFromHeaders(resp.Header)
 
 				if v := resp.Header.Get("Content-Length"); v != "" {
@@ -156,6 +168,7 @@ func (s snapshotGetPropertiesOptions) ToHeaders() *client.Headers {
 	if s.input.LeaseID != nil {
 		headers.Append("x-ms-lease-id", *s.input.LeaseID)
 	}
+	s.input.CustomerProvidedKey.appendHeaders(headers)
 	return headers
 }
 
@@ -0,0 +1,127 @@
+package blobs
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestReadingSnapshots(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	blobClient, err := NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobClient.Client.SetAuthorizer(authorizer)
+
+	containerName := "container1"
+	fileName := "blocks.txt"
+	if _, err := containersClient.Create(ctx, containerName, containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Putting and Snapshotting a Block Blob..")
+	blockId := base64.StdEncoding.EncodeToString([]byte("block1"))
+	if _, err := blobClient.PutBlock(ctx, containerName, fileName, PutBlockInput{BlockID: blockId, Content: []byte("before")}); err != nil {
+		t.Fatalf("putting block: %+v", err)
+	}
+	if _, err := blobClient.PutBlockList(ctx, containerName, fileName, PutBlockListInput{BlockList: BlockList{LatestBlockIDs: []BlockID{{Value: blockId}}}}); err != nil {
+		t.Fatalf("putting block list: %+v", err)
+	}
+	snapshot, err := blobClient.Snapshot(ctx, containerName, fileName, SnapshotInput{})
+	if err != nil {
+		t.Fatalf("snapshotting blob: %+v", err)
+	}
+	if _, err := blobClient.PutBlockBlob(ctx, containerName, fileName, PutBlockBlobInput{Content: pointer.To([]byte("after"))}); err != nil {
+		t.Fatalf("overwriting blob: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Parsing the Snapshot ID..")
+	// the Emulator's path-style URI is used, since the port of the Server is random
+	id, err := ParseBlobID(fmt.Sprintf("http://127.0.0.1:10000/%s/%s/%s?snapshot=%s", server.AccountName, containerName, fileName, snapshot.SnapshotDateTime), "core.windows.net")
+	if err != nil {
+		t.Fatalf("parsing snapshot id: %+v", err)
+	}
+	if id.Snapshot == nil || *id.Snapshot != snapshot.SnapshotDateTime {
+		t.Fatalf("expected the Snapshot to be %q but got %v", snapshot.SnapshotDateTime, id.Snapshot)
+	}
+
+	t.Logf("[DEBUG] Reading the Snapshot..")
+	blob, err := blobClient.Get(ctx, id.ContainerName, id.BlobName, GetInput{Snapshot: id.Snapshot})
+	if err != nil {
+		t.Fatalf("retrieving snapshot: %+v", err)
+	}
+	if string(*blob.Contents) != "before" {
+		t.Fatalf("expected the snapshot to contain %q but got %q", "before", string(*blob.Contents))
+	}
+	blockList, err := blobClient.GetBlockList(ctx, containerName, fileName, GetBlockListInput{BlockListType: All, Snapshot: id.Snapshot})
+	if err != nil {
+		t.Fatalf("retrieving snapshot block list: %+v", err)
+	}
+	if len(blockList.CommittedBlocks.Blocks) != 1 || blockList.CommittedBlocks.Blocks[0].Name != blockId {
+		t.Fatalf("expected the snapshot to contain the block %q but got %+v", blockId, blockList.CommittedBlocks.Blocks)
+	}
+
+	t.Logf("[DEBUG] Setting the Tier of the Snapshot..")
+	if _, err := blobClient.SetTier(ctx, containerName, fileName, SetTierInput{Tier: Cool, Snapshot: id.Snapshot}); err != nil {
+		t.Fatalf("setting tier on snapshot: %+v", err)
+	}
+	props, err := blobClient.GetSnapshotProperties(ctx, containerName, fileName, GetSnapshotPropertiesInput{SnapshotID: snapshot.SnapshotDateTime})
+	if err != nil {
+		t.Fatalf("retrieving snapshot properties: %+v", err)
+	}
+	if props.AccessTier != Cool {
+		t.Fatalf("expected the snapshot to be in the %q tier but got %q", string(Cool), string(props.AccessTier))
+	}
+	props, err = blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.AccessTier != Hot {
+		t.Fatalf("expected the base blob to remain in the %q tier but got %q", string(Hot), string(props.AccessTier))
+	}
+
+	t.Logf("[DEBUG] Reading the Page Ranges of a Page Blob Snapshot..")
+	if _, err := blobClient.PutPageBlob(ctx, containerName, "page.vhd", PutPageBlobInput{BlobContentLengthBytes: 1024}); err != nil {
+		t.Fatalf("putting page blob: %+v", err)
+	}
+	if _, err := blobClient.PutPageUpdate(ctx, containerName, "page.vhd", PutPageUpdateInput{StartByte: 0, EndByte: 511, Content: make([]byte, 512)}); err != nil {
+		t.Fatalf("putting page: %+v", err)
+	}
+	pageSnapshot, err := blobClient.Snapshot(ctx, containerName, "page.vhd", SnapshotInput{})
+	if err != nil {
+		t.Fatalf("snapshotting page blob: %+v", err)
+	}
+	if _, err := blobClient.PutPageUpdate(ctx, containerName, "page.vhd", PutPageUpdateInput{StartByte: 512, EndByte: 1023, Content: make([]byte, 512)}); err != nil {
+		t.Fatalf("putting page: %+v", err)
+	}
+	ranges, err := blobClient.GetPageRanges(ctx, containerName, "page.vhd", GetPageRangesInput{Snapshot: pointer.To(pageSnapshot.SnapshotDateTime)})
+	if err != nil {
+		t.Fatalf("retrieving snapshot page ranges: %+v", err)
+	}
+	if len(ranges.PageRanges) != 1 || ranges.PageRanges[0].End != 511 {
+		t.Fatalf("expected the snapshot to contain a single page but got %+v", ranges.PageRanges)
+	}
+
+	if _, err := blobClient.Get(ctx, containerName, fileName, GetInput{Snapshot: id.Snapshot, VersionID: pointer.To("abc123")}); err == nil {
+		t.Fatalf("expected an error specifying both a Snapshot and a Version ID but didn't get one")
+	}
+}
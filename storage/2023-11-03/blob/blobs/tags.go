@@ -0,0 +1,67 @@
+package blobs
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+)
+
+const (
+	maxTags           = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// validateTags confirms that the Blob Index Tags are valid - there can be at most 10 tags, where each key is
+// between 1 and 128 characters and each value is at most 256 characters, using alphanumeric characters,
+// spaces and `+ - . / : = _`
+func validateTags(tags map[string]string) error {
+	if len(tags) > maxTags {
+		return fmt.Errorf("at most %d tags can be specified but got %d", maxTags, len(tags))
+	}
+	for k, v := range tags {
+		if len(k) == 0 || len(k) > maxTagKeyLength {
+			return fmt.Errorf("the tag key %q must be between 1 and %d characters", k, maxTagKeyLength)
+		}
+		if len(v) > maxTagValueLength {
+			return fmt.Errorf("the value for the tag %q must be at most %d characters", k, maxTagValueLength)
+		}
+		if !isValidTagString(k) {
+			return fmt.Errorf("the tag key %q contains invalid characters", k)
+		}
+		if !isValidTagString(v) {
+			return fmt.Errorf("the value for the tag %q contains invalid characters", k)
+		}
+	}
+	return nil
+}
+
+func isValidTagString(input string) bool {
+	for _, r := range input {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || strings.ContainsRune(" +-./:=_", r) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// appendTagsHeader appends the `x-ms-tags` header containing the URL-encoded Blob Index Tags, when specified
+func appendTagsHeader(headers *client.Headers, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, fmt.Sprintf("%s=%s", url.QueryEscape(k), url.QueryEscape(tags[k])))
+	}
+	headers.Append("x-ms-tags", strings.Join(values, "&"))
+}
@@ -47,7 +47,7 @@ func (c Client) Undelete(ctx context.Context, containerName, blobName string) (r
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Undelete", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
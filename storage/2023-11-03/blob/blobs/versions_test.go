@@ -0,0 +1,127 @@
+package blobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func TestVersionsLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	server.VersioningEnabled = true
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	blobClient, err := NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobClient.Client.SetAuthorizer(authorizer)
+
+	containerName := "container1"
+	fileName := "versioned.txt"
+	if _, err := containersClient.Create(ctx, containerName, containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Putting two versions of the Blob..")
+	first, err := blobClient.PutBlockBlob(ctx, containerName, fileName, PutBlockBlobInput{Content: pointer.To([]byte("first"))})
+	if err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	if first.VersionID == "" {
+		t.Fatalf("expected a Version ID to be returned but didn't get one")
+	}
+	second, err := blobClient.PutBlockBlob(ctx, containerName, fileName, PutBlockBlobInput{Content: pointer.To([]byte("second"))})
+	if err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	if second.VersionID == first.VersionID {
+		t.Fatalf("expected each write to return a new Version ID but got %q twice", first.VersionID)
+	}
+
+	t.Logf("[DEBUG] Retrieving the previous Version..")
+	blob, err := blobClient.Get(ctx, containerName, fileName, GetInput{VersionID: pointer.To(first.VersionID)})
+	if err != nil {
+		t.Fatalf("retrieving version: %+v", err)
+	}
+	if string(*blob.Contents) != "first" {
+		t.Fatalf("expected the previous version to contain %q but got %q", "first", string(*blob.Contents))
+	}
+	props, err := blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{VersionID: pointer.To(first.VersionID)})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.VersionID != first.VersionID || props.IsCurrentVersion {
+		t.Fatalf("expected the properties of the previous version %q but got %q (current: %t)", first.VersionID, props.VersionID, props.IsCurrentVersion)
+	}
+	if _, err := blobClient.SetTier(ctx, containerName, fileName, SetTierInput{Tier: Cool, VersionID: pointer.To(first.VersionID)}); err != nil {
+		t.Fatalf("setting tier on version: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Listing Versions..")
+	include := []containers.Dataset{containers.Versions}
+	list, err := containersClient.ListBlobs(ctx, containerName, containers.ListBlobsInput{Include: &include})
+	if err != nil {
+		t.Fatalf("listing blobs: %+v", err)
+	}
+	if len(list.Blobs.Blobs) != 2 {
+		t.Fatalf("expected 2 versions but got %d", len(list.Blobs.Blobs))
+	}
+	for _, v := range list.Blobs.Blobs {
+		if v.VersionId == nil || v.Name != fileName {
+			t.Fatalf("expected the Version ID of %q to be returned", v.Name)
+		}
+		current := v.IsCurrentVersion != nil && *v.IsCurrentVersion
+		if current != (*v.VersionId == second.VersionID) {
+			t.Fatalf("expected only %q to be the current version", second.VersionID)
+		}
+	}
+
+	t.Logf("[DEBUG] Deleting the Blob retains its Versions..")
+	if _, err := blobClient.Delete(ctx, containerName, fileName, DeleteInput{}); err != nil {
+		t.Fatalf("deleting blob: %+v", err)
+	}
+	if _, err := blobClient.GetProperties(ctx, containerName, fileName, GetPropertiesInput{}); err == nil {
+		t.Fatalf("expected an error retrieving the deleted blob but didn't get one")
+	}
+
+	t.Logf("[DEBUG] Promoting the previous Version..")
+	if err := blobClient.PromoteVersion(ctx, containerName, fileName, PromoteVersionInput{VersionID: first.VersionID}); err != nil {
+		t.Fatalf("promoting version: %+v", err)
+	}
+	blob, err = blobClient.Get(ctx, containerName, fileName, GetInput{})
+	if err != nil {
+		t.Fatalf("retrieving blob: %+v", err)
+	}
+	if string(*blob.Contents) != "first" {
+		t.Fatalf("expected the promoted version to contain %q but got %q", "first", string(*blob.Contents))
+	}
+
+	t.Logf("[DEBUG] Deleting the previous Versions..")
+	for _, versionId := range []string{first.VersionID, second.VersionID} {
+		if _, err := blobClient.Delete(ctx, containerName, fileName, DeleteInput{VersionID: pointer.To(versionId)}); err != nil {
+			t.Fatalf("deleting version %q: %+v", versionId, err)
+		}
+	}
+	list, err = containersClient.ListBlobs(ctx, containerName, containers.ListBlobsInput{Include: &include})
+	if err != nil {
+		t.Fatalf("listing blobs: %+v", err)
+	}
+	if len(list.Blobs.Blobs) != 1 {
+		t.Fatalf("expected only the current version to remain but got %d versions", len(list.Blobs.Blobs))
+	}
+}
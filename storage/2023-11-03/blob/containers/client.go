@@ -1,14 +1,29 @@
 package containers
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/client/dataplane/storage"
+	"github.com/tombuildsstuff/giovanni/storage/logging"
+	"github.com/tombuildsstuff/giovanni/storage/retry"
+	"github.com/tombuildsstuff/giovanni/storage/telemetry"
 )
 
 // Client is the base client for Blob Storage Containers.
 type Client struct {
 	Client *storage.Client
+
+	// RetryPolicy optionally configures how failed requests are retried (see `retry.DefaultPolicy`) - when
+	// unset the default retry behaviour of the base layer is used.
+	RetryPolicy *retry.Policy
+
+	// Instrumentation optionally records OpenTelemetry Spans and Metrics for each operation.
+	Instrumentation *telemetry.Instrumentation
+
+	// Logger optionally logs each operation, along with any progress messages from helper methods.
+	Logger *logging.RequestLogger
 }
 
 func NewWithBaseUri(baseUri string) (*Client, error) {
@@ -20,3 +35,17 @@ func NewWithBaseUri(baseUri string) (*Client, error) {
 		Client: baseClient,
 	}, nil
 }
+
+// execute sends the request using the configured RetryPolicy, recording and logging the operation using the
+// configured Instrumentation and Logger
+func (c Client) execute(ctx context.Context, operation string, req *client.Request) (*client.Response, error) {
+	op := telemetry.Operation{
+		Name:         fmt.Sprintf("containers.%s", operation),
+		ResourceType: telemetry.ContainerResourceType,
+	}
+	return c.Instrumentation.Execute(ctx, req, op, func(ctx context.Context) (*client.Response, error) {
+		return c.Logger.Execute(ctx, req, op.Name, func(ctx context.Context) (*client.Response, error) {
+			return retry.Execute(ctx, req, c.RetryPolicy)
+		})
+	})
+}
@@ -0,0 +1,35 @@
+package containers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientUsingPathStyleBaseUri(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	requestedPath := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	// e.g. `http://127.0.0.1:10000/devstoreaccount1`
+	client, err := NewWithBaseUri(server.URL + "/devstoreaccount1")
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	if _, err := client.Create(ctx, "container1", CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	if expected := "/devstoreaccount1/container1"; requestedPath != expected {
+		t.Fatalf("expected the request path to be %q but got %q", expected, requestedPath)
+	}
+}
@@ -32,7 +32,7 @@ func (c Client) AcquireLease(ctx context.Context, containerName string, input Ac
 		return result, fmt.Errorf("`containerName` cannot be an empty string")
 	}
 	// An infinite lease duration is -1 seconds. A non-infinite lease can be between 15 and 60 seconds
-	if input.LeaseDuration != -1 && (input.LeaseDuration <= 15 || input.LeaseDuration >= 60) {
+	if input.LeaseDuration != -1 && (input.LeaseDuration < 15 || input.LeaseDuration > 60) {
 		return result, fmt.Errorf("`input.LeaseDuration` must be -1 (infinite), or between 15 and 60 seconds")
 	}
 
@@ -56,7 +56,7 @@ func (c Client) AcquireLease(ctx context.Context, containerName string, input Ac
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "AcquireLease", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -63,7 +63,7 @@ func (c Client) BreakLease(ctx context.Context, containerName string, input Brea
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "BreakLease", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -58,7 +58,7 @@ func (c Client) ChangeLease(ctx context.Context, containerName string, input Cha
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "ChangeLease", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -0,0 +1,72 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tombuildsstuff/giovanni/storage/lease"
+)
+
+// NewLeaseKeeper returns a Keeper which acquires a Lease on the specified Container and renews it in the background,
+// allowing the Container to be used as a distributed lock.
+func (c Client) NewLeaseKeeper(containerName string, options lease.Options) (*lease.Keeper, error) {
+	if containerName == "" {
+		return nil, fmt.Errorf("`containerName` cannot be an empty string")
+	}
+
+	if strings.ToLower(containerName) != containerName {
+		return nil, fmt.Errorf("`containerName` must be a lower-cased string")
+	}
+
+	lessor := containerLessor{
+		client:        c,
+		containerName: containerName,
+	}
+	return lease.NewKeeper(lessor, options)
+}
+
+var _ lease.Lessor = containerLessor{}
+
+type containerLessor struct {
+	client        Client
+	containerName string
+}
+
+func (l containerLessor) AcquireLease(ctx context.Context, duration int, proposedLeaseId string) error {
+	input := AcquireLeaseInput{
+		LeaseDuration:   duration,
+		ProposedLeaseID: proposedLeaseId,
+	}
+	resp, err := l.client.AcquireLease(ctx, l.containerName, input)
+	if err != nil {
+		if resp.HttpResponse != nil && resp.HttpResponse.StatusCode == http.StatusConflict {
+			return fmt.Errorf("%w: %+v", lease.ErrLeaseAlreadyPresent, err)
+		}
+		return fmt.Errorf("acquiring lease: %+v", err)
+	}
+	return nil
+}
+
+func (l containerLessor) RenewLease(ctx context.Context, leaseId string) error {
+	resp, err := l.client.RenewLease(ctx, l.containerName, RenewLeaseInput{LeaseId: leaseId})
+	if err != nil {
+		if resp.HttpResponse != nil && resp.HttpResponse.StatusCode == http.StatusConflict {
+			return fmt.Errorf("%w: %+v", lease.ErrLeaseLost, err)
+		}
+		return fmt.Errorf("renewing lease: %+v", err)
+	}
+	return nil
+}
+
+func (l containerLessor) ReleaseLease(ctx context.Context, leaseId string) error {
+	resp, err := l.client.ReleaseLease(ctx, l.containerName, ReleaseLeaseInput{LeaseId: leaseId})
+	if err != nil {
+		if resp.HttpResponse != nil && resp.HttpResponse.StatusCode == http.StatusConflict {
+			return fmt.Errorf("%w: %+v", lease.ErrLeaseLost, err)
+		}
+		return fmt.Errorf("releasing lease: %+v", err)
+	}
+	return nil
+}
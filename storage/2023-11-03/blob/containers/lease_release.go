@@ -47,7 +47,7 @@ func (c Client) ReleaseLease(ctx context.Context, containerName string, input Re
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "ReleaseLease", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -44,6 +44,10 @@ type BlobDetails struct {
 	MetaData   map[string]interface{} `map:"Metadata,omitempty"`
 	Properties *BlobProperties        `xml:"Properties,omitempty"`
 	Snapshot   *string                `xml:"Snapshot,omitempty"`
+
+	// VersionId and IsCurrentVersion are only returned when the `Versions` Dataset is included
+	VersionId        *string `xml:"VersionId,omitempty"`
+	IsCurrentVersion *bool   `xml:"IsCurrentVersion,omitempty"`
 }
 
 type BlobProperties struct {
@@ -74,6 +78,16 @@ type BlobProperties struct {
 	LeaseStatus            *string `xml:"LeaseStatus,omitempty"`
 	RemainingRetentionDays *string `xml:"RemainingRetentionDays,omitempty"`
 	ServerEncrypted        *bool   `xml:"ServerEncrypted,omitempty"`
+
+	// ImmutabilityPolicyUntilDate and ImmutabilityPolicyMode are only returned when the `ImmutabilityPolicy`
+	// Dataset is included, and LegalHold when the `LegalHold` Dataset is included
+	ImmutabilityPolicyUntilDate *string `xml:"ImmutabilityPolicyUntilDate,omitempty"`
+	ImmutabilityPolicyMode      *string `xml:"ImmutabilityPolicyMode,omitempty"`
+	LegalHold                   *bool   `xml:"LegalHold,omitempty"`
+
+	// ArchiveStatus and RehydratePriority are only returned when the blob is being rehydrated from the Archive tier
+	ArchiveStatus     *string `xml:"ArchiveStatus,omitempty"`
+	RehydratePriority *string `xml:"RehydratePriority,omitempty"`
 }
 
 type BlobPrefix struct {
@@ -113,7 +127,7 @@ func (c Client) ListBlobs(ctx context.Context, containerName string, input ListB
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "ListBlobs", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
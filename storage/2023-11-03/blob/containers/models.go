@@ -35,11 +35,14 @@ type ContainerProperties struct {
 type Dataset string
 
 var (
-	Copy             Dataset = "copy"
-	Deleted          Dataset = "deleted"
-	MetaData         Dataset = "metadata"
-	Snapshots        Dataset = "snapshots"
-	UncommittedBlobs Dataset = "uncommittedblobs"
+	Copy               Dataset = "copy"
+	Deleted            Dataset = "deleted"
+	ImmutabilityPolicy Dataset = "immutabilitypolicy"
+	LegalHold          Dataset = "legalhold"
+	MetaData           Dataset = "metadata"
+	Snapshots          Dataset = "snapshots"
+	UncommittedBlobs   Dataset = "uncommittedblobs"
+	Versions           Dataset = "versions"
 )
 
 type ErrorResponse struct {
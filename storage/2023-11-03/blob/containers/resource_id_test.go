@@ -160,3 +160,20 @@ func TestFormatContainerIDInEdgeZone(t *testing.T) {
 		t.Fatalf("expected %q but got %q", expected, actual)
 	}
 }
+
+func TestParseContainerIDPathStyle(t *testing.T) {
+	input := "http://127.0.0.1:10000/devstoreaccount1/container1"
+	actual, err := ParseContainerID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountId.AccountName != "devstoreaccount1" {
+		t.Fatalf("expected AccountName to be %q but got %q", "devstoreaccount1", actual.AccountId.AccountName)
+	}
+	if actual.ContainerName != "container1" {
+		t.Fatalf("expected ContainerName to be %q but got %q", "container1", actual.ContainerName)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
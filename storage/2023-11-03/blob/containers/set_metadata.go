@@ -50,7 +50,7 @@ func (c Client) SetMetaData(ctx context.Context, containerName string, input Set
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetMetaData", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -0,0 +1,238 @@
+package clients
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/auth"
+	"github.com/hashicorp/go-azure-sdk/sdk/client/dataplane/storage"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/accounts"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/blobs"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/datalakestore/filesystems"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/datalakestore/paths"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/file/directories"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/file/files"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/file/shares"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/queue/messages"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/queue/queues"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/table/entities"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/table/tables"
+	"github.com/tombuildsstuff/giovanni/storage/logging"
+	"github.com/tombuildsstuff/giovanni/storage/retry"
+	"github.com/tombuildsstuff/giovanni/storage/telemetry"
+)
+
+// Options configures the Clients built from a Connection String.
+type Options struct {
+	// RetryPolicy optionally configures how failed requests are retried by each Client.
+	RetryPolicy *retry.Policy
+
+	// Instrumentation optionally records OpenTelemetry Spans and Metrics for each operation.
+	Instrumentation *telemetry.Instrumentation
+
+	// Logger optionally logs each operation.
+	Logger *logging.RequestLogger
+
+	// HTTPClient optionally specifies the HTTP Client (and so the transport) used to send requests from each Client.
+	// Since the base layer builds a new transport for each request, this is only used when requests are sent using a
+	// RetryPolicy - as such `retry.DefaultPolicy` is used when RetryPolicy isn't set.
+	HTTPClient *http.Client
+}
+
+// Client is a bundle of Clients for each Storage API, which share the same Authorizer, RetryPolicy,
+// Instrumentation and Logger. When a RetryPolicy is set the Clients also share the same HTTP transport
+// (either that of the HTTPClient, or one shared by all Retry Policies) - otherwise the base layer builds a new
+// transport for each request.
+//
+// Clients for services whose endpoint can't be determined from the Connection String are nil - for
+// example the DataLakeStore and File Services aren't available when using the Storage Emulator.
+type Client struct {
+	// ConnectionString is the parsed Connection String used to build these Clients.
+	ConnectionString ConnectionString
+
+	// Blob Storage
+	Accounts   *accounts.Client
+	Blobs      *blobs.Client
+	Containers *containers.Client
+
+	// DataLakeStore Gen2
+	FileSystems *filesystems.Client
+	Paths       *paths.Client
+
+	// File Storage
+	Directories *directories.Client
+	Files       *files.Client
+	Shares      *shares.Client
+
+	// Queue Storage
+	Messages *messages.Client
+	Queues   *queues.Client
+
+	// Table Storage
+	Entities *entities.Client
+	Tables   *tables.Client
+}
+
+// NewFromConnectionString parses the Azure Storage Connection String specified in `connectionString` and
+// returns a Client for each Storage API whose endpoint is available.
+func NewFromConnectionString(connectionString string, options Options) (*Client, error) {
+	parsed, err := ParseConnectionString(connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Connection String: %+v", err)
+	}
+
+	return New(*parsed, options)
+}
+
+// New returns a Client for each Storage API whose endpoint is available in the Connection String
+// specified in `input`.
+func New(input ConnectionString, options Options) (*Client, error) {
+	// the same Authorizer is used for all Clients, except Table Storage which signs requests differently
+	var authorizer, tableAuthorizer auth.Authorizer
+	if input.AccountKey != "" {
+		sharedKey, err := auth.NewSharedKeyAuthorizer(input.AccountName, input.AccountKey, auth.SharedKey)
+		if err != nil {
+			return nil, fmt.Errorf("building SharedKey authorizer: %+v", err)
+		}
+		authorizer = sharedKey
+
+		sharedKeyTable, err := auth.NewSharedKeyAuthorizer(input.AccountName, input.AccountKey, auth.SharedKeyTable)
+		if err != nil {
+			return nil, fmt.Errorf("building SharedKeyTable authorizer: %+v", err)
+		}
+		tableAuthorizer = sharedKeyTable
+	}
+
+	retryPolicy := options.RetryPolicy
+	if options.HTTPClient != nil {
+		// the HTTP Client is only used when sending requests using a Retry Policy
+		policy := retry.DefaultPolicy()
+		if retryPolicy != nil {
+			copied := *retryPolicy
+			policy = &copied
+		}
+		policy.HTTPClient = options.HTTPClient
+		retryPolicy = policy
+	}
+
+	configure := func(base *storage.Client, authorizer auth.Authorizer) {
+		if input.SharedAccessSignature != "" {
+			base.AuthorizeRequest = authorizeUsingSharedAccessSignature(input.SharedAccessSignature)
+		} else if authorizer != nil {
+			base.SetAuthorizer(authorizer)
+		}
+	}
+
+	out := Client{
+		ConnectionString: input,
+	}
+
+	if endpoint := input.Endpoint(accounts.BlobSubDomainType); endpoint != nil {
+		accountsClient, err := accounts.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Accounts client: %+v", err)
+		}
+		configure(accountsClient.Client, authorizer)
+		accountsClient.RetryPolicy, accountsClient.Instrumentation, accountsClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Accounts = accountsClient
+
+		blobsClient, err := blobs.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Blobs client: %+v", err)
+		}
+		configure(blobsClient.Client, authorizer)
+		blobsClient.RetryPolicy, blobsClient.Instrumentation, blobsClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Blobs = blobsClient
+
+		containersClient, err := containers.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Containers client: %+v", err)
+		}
+		configure(containersClient.Client, authorizer)
+		containersClient.RetryPolicy, containersClient.Instrumentation, containersClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Containers = containersClient
+	}
+
+	if endpoint := input.Endpoint(accounts.DataLakeStoreSubDomainType); endpoint != nil {
+		fileSystemsClient, err := filesystems.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building FileSystems client: %+v", err)
+		}
+		configure(fileSystemsClient.Client, authorizer)
+		fileSystemsClient.RetryPolicy, fileSystemsClient.Instrumentation, fileSystemsClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.FileSystems = fileSystemsClient
+
+		pathsClient, err := paths.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Paths client: %+v", err)
+		}
+		configure(pathsClient.Client, authorizer)
+		pathsClient.RetryPolicy, pathsClient.Instrumentation, pathsClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Paths = pathsClient
+	}
+
+	if endpoint := input.Endpoint(accounts.FileSubDomainType); endpoint != nil {
+		directoriesClient, err := directories.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Directories client: %+v", err)
+		}
+		configure(directoriesClient.Client, authorizer)
+		directoriesClient.RetryPolicy, directoriesClient.Instrumentation, directoriesClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Directories = directoriesClient
+
+		filesClient, err := files.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Files client: %+v", err)
+		}
+		configure(filesClient.Client, authorizer)
+		filesClient.RetryPolicy, filesClient.Instrumentation, filesClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Files = filesClient
+
+		sharesClient, err := shares.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Shares client: %+v", err)
+		}
+		configure(sharesClient.Client, authorizer)
+		sharesClient.RetryPolicy, sharesClient.Instrumentation, sharesClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Shares = sharesClient
+	}
+
+	if endpoint := input.Endpoint(accounts.QueueSubDomainType); endpoint != nil {
+		messagesClient, err := messages.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Messages client: %+v", err)
+		}
+		configure(messagesClient.Client, authorizer)
+		messagesClient.RetryPolicy, messagesClient.Instrumentation, messagesClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Messages = messagesClient
+
+		queuesClient, err := queues.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Queues client: %+v", err)
+		}
+		configure(queuesClient.Client, authorizer)
+		queuesClient.RetryPolicy, queuesClient.Instrumentation, queuesClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Queues = queuesClient
+	}
+
+	if endpoint := input.Endpoint(accounts.TableSubDomainType); endpoint != nil {
+		entitiesClient, err := entities.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Entities client: %+v", err)
+		}
+		configure(entitiesClient.Client, tableAuthorizer)
+		entitiesClient.RetryPolicy, entitiesClient.Instrumentation, entitiesClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Entities = entitiesClient
+
+		tablesClient, err := tables.NewWithBaseUri(*endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("building Tables client: %+v", err)
+		}
+		configure(tablesClient.Client, tableAuthorizer)
+		tablesClient.RetryPolicy, tablesClient.Instrumentation, tablesClient.Logger = retryPolicy, options.Instrumentation, options.Logger
+		out.Tables = tablesClient
+	}
+
+	return &out, nil
+}
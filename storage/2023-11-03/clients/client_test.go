@@ -0,0 +1,114 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/blobs"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+	"github.com/tombuildsstuff/giovanni/storage/retry"
+)
+
+func TestNewFromConnectionStringUsingAccountKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	connectionString := fmt.Sprintf("AccountName=%s;AccountKey=%s;BlobEndpoint=%s", server.AccountName, server.AccountKey, server.BaseUri())
+	policy := retry.DefaultPolicy()
+	client, err := NewFromConnectionString(connectionString, Options{RetryPolicy: policy})
+	if err != nil {
+		t.Fatalf("building clients: %+v", err)
+	}
+	if expected := fmt.Sprintf("https://%s.queue.core.windows.net", server.AccountName); client.Queues.Client.BaseUri != expected {
+		t.Fatalf("expected the endpoint for the Queues client to be %q but got %q", expected, client.Queues.Client.BaseUri)
+	}
+	if client.Blobs.RetryPolicy != policy || client.Containers.RetryPolicy != policy {
+		t.Fatalf("expected the Retry Policy to be configured on each client")
+	}
+
+	if _, err := client.Containers.Create(ctx, "container1", containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	if _, err := client.Blobs.PutBlockBlob(ctx, "container1", "blob.txt", blobs.PutBlockBlobInput{Content: pointer.To([]byte("hello"))}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	props, err := client.Blobs.GetProperties(ctx, "container1", "blob.txt", blobs.GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.ContentLength != 5 {
+		t.Fatalf("expected the Content Length to be 5 but got %d", props.ContentLength)
+	}
+}
+
+func TestNewFromConnectionStringUsingSharedAccessSignature(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var query, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	connectionString := fmt.Sprintf("BlobEndpoint=%s/devstoreaccount1;SharedAccessSignature=?sv=2023-11-03&sig=abc%%3D", server.URL)
+	client, err := NewFromConnectionString(connectionString, Options{})
+	if err != nil {
+		t.Fatalf("building clients: %+v", err)
+	}
+
+	if _, err := client.Containers.Create(ctx, "container1", containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	if expected := "restype=container&sv=2023-11-03&sig=abc%3D"; query != expected {
+		t.Fatalf("expected the query string to be %q but got %q", expected, query)
+	}
+	if authorization != "" {
+		t.Fatalf("expected no Authorization header but got %q", authorization)
+	}
+}
+
+type countingTransport struct {
+	count int
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.count++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestNewFromConnectionStringUsingHTTPClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	connectionString := fmt.Sprintf("AccountName=%s;AccountKey=%s;BlobEndpoint=%s", server.AccountName, server.AccountKey, server.BaseUri())
+	transport := &countingTransport{}
+	httpClient := &http.Client{Transport: transport}
+	client, err := NewFromConnectionString(connectionString, Options{HTTPClient: httpClient})
+	if err != nil {
+		t.Fatalf("building clients: %+v", err)
+	}
+	if client.Blobs.RetryPolicy == nil || client.Blobs.RetryPolicy != client.Queues.RetryPolicy || client.Blobs.RetryPolicy.HTTPClient != httpClient {
+		t.Fatalf("expected each client to use a Retry Policy configured with the HTTP Client")
+	}
+
+	if _, err := client.Containers.Create(ctx, "container1", containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	if _, err := client.Blobs.PutBlockBlob(ctx, "container1", "blob.txt", blobs.PutBlockBlobInput{Content: pointer.To([]byte("hello"))}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	if transport.count != 2 {
+		t.Fatalf("expected both requests to be sent using the HTTP Client but got %d", transport.count)
+	}
+}
@@ -0,0 +1,191 @@
+package clients
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/accounts"
+)
+
+const (
+	// defaultEndpointsProtocol is the protocol used to build the endpoints for each service, when
+	// `DefaultEndpointsProtocol` isn't specified in the Connection String
+	defaultEndpointsProtocol = "https"
+
+	// defaultEndpointSuffix is the suffix used to build the endpoints for each service, when `EndpointSuffix`
+	// isn't specified in the Connection String
+	defaultEndpointSuffix = "core.windows.net"
+)
+
+// ConnectionString is a parsed Azure Storage Connection String, for example:
+// `DefaultEndpointsProtocol=https;AccountName=example;AccountKey=abc123==;EndpointSuffix=core.windows.net`
+type ConnectionString struct {
+	// AccountName is the name of the Storage Account.
+	AccountName string
+
+	// AccountKey is the (base64 encoded) Access Key for the Storage Account.
+	AccountKey string
+
+	// SharedAccessSignature is a SAS Token (without the leading `?`) used to authorize requests.
+	SharedAccessSignature string
+
+	// DefaultEndpointsProtocol is the protocol (`http` or `https`) used for each service.
+	DefaultEndpointsProtocol string
+
+	// EndpointSuffix is the domain suffix used for each service, for example `core.windows.net`.
+	EndpointSuffix string
+
+	// BlobEndpoint optionally overrides the endpoint used for the Blob Service.
+	BlobEndpoint string
+
+	// FileEndpoint optionally overrides the endpoint used for the File Service.
+	FileEndpoint string
+
+	// QueueEndpoint optionally overrides the endpoint used for the Queue Service.
+	QueueEndpoint string
+
+	// TableEndpoint optionally overrides the endpoint used for the Table Service.
+	TableEndpoint string
+
+	// UseDevelopmentStorage specifies whether the Storage Emulator is being used (via `UseDevelopmentStorage=true`).
+	UseDevelopmentStorage bool
+
+	// Other contains any keys which aren't used by this SDK (for example `BlobSecondaryEndpoint` or
+	// `DevelopmentStorageProxyUri`), keyed by the name of the key as specified in the Connection String.
+	Other map[string]string
+}
+
+// ParseConnectionString parses the Azure Storage Connection String specified in `input`.
+func ParseConnectionString(input string) (*ConnectionString, error) {
+	out := ConnectionString{}
+
+	for _, segment := range strings.Split(input, ";") {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+
+		// values (such as the Account Key) can contain `=`, so only the first one is a separator
+		key, value, ok := strings.Cut(segment, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected the segment %q to be in the format `Key=Value`", segment)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(key) {
+		case "accountname":
+			out.AccountName = value
+		case "accountkey":
+			out.AccountKey = value
+		case "sharedaccesssignature":
+			out.SharedAccessSignature = strings.TrimPrefix(value, "?")
+		case "defaultendpointsprotocol":
+			out.DefaultEndpointsProtocol = strings.ToLower(value)
+		case "endpointsuffix":
+			out.EndpointSuffix = value
+		case "blobendpoint":
+			out.BlobEndpoint = strings.TrimSuffix(value, "/")
+		case "fileendpoint":
+			out.FileEndpoint = strings.TrimSuffix(value, "/")
+		case "queueendpoint":
+			out.QueueEndpoint = strings.TrimSuffix(value, "/")
+		case "tableendpoint":
+			out.TableEndpoint = strings.TrimSuffix(value, "/")
+		case "usedevelopmentstorage":
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("parsing `UseDevelopmentStorage`: expected `true` or `false` but got %q", value)
+			}
+			out.UseDevelopmentStorage = v
+		default:
+			// Connection Strings from the Portal/CLI can contain other keys (such as the secondary endpoints), which are retained
+			if out.Other == nil {
+				out.Other = map[string]string{}
+			}
+			out.Other[key] = value
+		}
+	}
+
+	if out.UseDevelopmentStorage {
+		// the Storage Emulator uses well-known credentials, unless these are explicitly overridden
+		if out.AccountName == "" {
+			out.AccountName = accounts.EmulatorAccountName
+		}
+		if out.AccountKey == "" && out.SharedAccessSignature == "" {
+			out.AccountKey = accounts.EmulatorAccountKey
+		}
+	}
+
+	if out.AccountKey != "" && out.SharedAccessSignature != "" {
+		return nil, fmt.Errorf("only one of `AccountKey` and `SharedAccessSignature` can be specified")
+	}
+	if out.AccountKey != "" && out.AccountName == "" {
+		return nil, fmt.Errorf("`AccountName` must be specified when `AccountKey` is specified")
+	}
+	if out.DefaultEndpointsProtocol != "" && out.DefaultEndpointsProtocol != "http" && out.DefaultEndpointsProtocol != "https" {
+		return nil, fmt.Errorf("expected `DefaultEndpointsProtocol` to be `http` or `https` but got %q", out.DefaultEndpointsProtocol)
+	}
+	if !out.UseDevelopmentStorage && out.AccountName == "" && out.BlobEndpoint == "" && out.FileEndpoint == "" && out.QueueEndpoint == "" && out.TableEndpoint == "" {
+		return nil, fmt.Errorf("either `AccountName` or an endpoint for at least one service must be specified")
+	}
+
+	return &out, nil
+}
+
+// Endpoint returns the endpoint for the service specified in `subDomainType`, or nil when the endpoint
+// for this service can't be determined from the Connection String.
+//
+// Explicitly specified endpoints (such as `BlobEndpoint`) are used as-is - otherwise the endpoint is built
+// from the Account Name, `DefaultEndpointsProtocol` and `EndpointSuffix`. The DataLakeStore endpoint is
+// derived from the Blob endpoint, since Connection Strings don't include it. When `UseDevelopmentStorage`
+// is set the default ports of the Storage Emulator are used, which doesn't support the DataLakeStore or
+// File Services.
+func (c ConnectionString) Endpoint(subDomainType accounts.SubDomainType) *string {
+	override := ""
+	switch subDomainType {
+	case accounts.BlobSubDomainType:
+		override = c.BlobEndpoint
+	case accounts.DataLakeStoreSubDomainType:
+		if c.BlobEndpoint != "" {
+			if !strings.Contains(c.BlobEndpoint, ".blob.") {
+				return nil
+			}
+			override = strings.Replace(c.BlobEndpoint, ".blob.", ".dfs.", 1)
+		}
+	case accounts.FileSubDomainType:
+		override = c.FileEndpoint
+	case accounts.QueueSubDomainType:
+		override = c.QueueEndpoint
+	case accounts.TableSubDomainType:
+		override = c.TableEndpoint
+	}
+	if override != "" {
+		return &override
+	}
+
+	if c.UseDevelopmentStorage {
+		accountId, err := accounts.NewEmulatorAccountID(subDomainType)
+		if err != nil {
+			return nil
+		}
+		accountId.AccountName = c.AccountName
+		endpoint := accountId.ID()
+		return &endpoint
+	}
+
+	if c.AccountName == "" {
+		return nil
+	}
+	protocol := c.DefaultEndpointsProtocol
+	if protocol == "" {
+		protocol = defaultEndpointsProtocol
+	}
+	suffix := c.EndpointSuffix
+	if suffix == "" {
+		suffix = defaultEndpointSuffix
+	}
+	endpoint := fmt.Sprintf("%s://%s.%s.%s", protocol, c.AccountName, string(subDomainType), suffix)
+	return &endpoint
+}
@@ -0,0 +1,156 @@
+package clients
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/accounts"
+)
+
+func TestParseConnectionString(t *testing.T) {
+	testData := []struct {
+		input    string
+		expected *ConnectionString
+	}{
+		{
+			input:    "",
+			expected: nil,
+		},
+		{
+			input:    "AccountName",
+			expected: nil,
+		},
+		{
+			input: "AccountName=example;BlobSecondaryEndpoint=https://example-secondary.blob.core.windows.net/;DevelopmentStorageProxyUri=http://proxy",
+			expected: &ConnectionString{
+				AccountName: "example",
+				Other: map[string]string{
+					"BlobSecondaryEndpoint":      "https://example-secondary.blob.core.windows.net/",
+					"DevelopmentStorageProxyUri": "http://proxy",
+				},
+			},
+		},
+		{
+			input:    "AccountKey=abc123==",
+			expected: nil,
+		},
+		{
+			input:    "AccountName=example;AccountKey=abc123==;SharedAccessSignature=sv=2023-11-03&sig=abc",
+			expected: nil,
+		},
+		{
+			input:    "DefaultEndpointsProtocol=ftp;AccountName=example",
+			expected: nil,
+		},
+		{
+			input: "DefaultEndpointsProtocol=https;AccountName=example;AccountKey=abc123==;EndpointSuffix=core.windows.net",
+			expected: &ConnectionString{
+				AccountName:              "example",
+				AccountKey:               "abc123==",
+				DefaultEndpointsProtocol: "https",
+				EndpointSuffix:           "core.windows.net",
+			},
+		},
+		{
+			input: "BlobEndpoint=https://example.blob.core.windows.net/;SharedAccessSignature=?sv=2023-11-03&sig=abc%3D;",
+			expected: &ConnectionString{
+				BlobEndpoint:          "https://example.blob.core.windows.net",
+				SharedAccessSignature: "sv=2023-11-03&sig=abc%3D",
+			},
+		},
+		{
+			input: "UseDevelopmentStorage=true",
+			expected: &ConnectionString{
+				AccountName:           accounts.EmulatorAccountName,
+				AccountKey:            accounts.EmulatorAccountKey,
+				UseDevelopmentStorage: true,
+			},
+		},
+		{
+			input:    "UseDevelopmentStorage=yes",
+			expected: nil,
+		},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.input)
+		actual, err := ParseConnectionString(v.input)
+		if err != nil {
+			if v.expected == nil {
+				continue
+			}
+			t.Fatalf("parsing %q: %+v", v.input, err)
+		}
+		if v.expected == nil {
+			t.Fatalf("expected an error parsing %q but got %+v", v.input, *actual)
+		}
+		if !reflect.DeepEqual(*actual, *v.expected) {
+			t.Fatalf("expected %+v but got %+v", *v.expected, *actual)
+		}
+	}
+}
+
+func TestConnectionStringEndpoint(t *testing.T) {
+	testData := []struct {
+		input    string
+		expected map[accounts.SubDomainType]string
+	}{
+		{
+			input: "AccountName=example;AccountKey=abc123==",
+			expected: map[accounts.SubDomainType]string{
+				accounts.BlobSubDomainType:          "https://example.blob.core.windows.net",
+				accounts.DataLakeStoreSubDomainType: "https://example.dfs.core.windows.net",
+				accounts.FileSubDomainType:          "https://example.file.core.windows.net",
+				accounts.QueueSubDomainType:         "https://example.queue.core.windows.net",
+				accounts.TableSubDomainType:         "https://example.table.core.windows.net",
+			},
+		},
+		{
+			input: "DefaultEndpointsProtocol=http;AccountName=example;AccountKey=abc123==;EndpointSuffix=core.chinacloudapi.cn;TableEndpoint=https://tables.example.com",
+			expected: map[accounts.SubDomainType]string{
+				accounts.BlobSubDomainType:          "http://example.blob.core.chinacloudapi.cn",
+				accounts.DataLakeStoreSubDomainType: "http://example.dfs.core.chinacloudapi.cn",
+				accounts.FileSubDomainType:          "http://example.file.core.chinacloudapi.cn",
+				accounts.QueueSubDomainType:         "http://example.queue.core.chinacloudapi.cn",
+				accounts.TableSubDomainType:         "https://tables.example.com",
+			},
+		},
+		{
+			input: "BlobEndpoint=https://example.blob.core.windows.net;SharedAccessSignature=sv=2023-11-03&sig=abc",
+			expected: map[accounts.SubDomainType]string{
+				accounts.BlobSubDomainType:          "https://example.blob.core.windows.net",
+				accounts.DataLakeStoreSubDomainType: "https://example.dfs.core.windows.net",
+			},
+		},
+		{
+			input: "UseDevelopmentStorage=true",
+			expected: map[accounts.SubDomainType]string{
+				accounts.BlobSubDomainType:  "http://127.0.0.1:10000/devstoreaccount1",
+				accounts.QueueSubDomainType: "http://127.0.0.1:10001/devstoreaccount1",
+				accounts.TableSubDomainType: "http://127.0.0.1:10002/devstoreaccount1",
+			},
+		},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.input)
+		connectionString, err := ParseConnectionString(v.input)
+		if err != nil {
+			t.Fatalf("parsing %q: %+v", v.input, err)
+		}
+		for _, subDomainType := range accounts.PossibleValuesForSubDomainType() {
+			actual := connectionString.Endpoint(subDomainType)
+			expected, ok := v.expected[subDomainType]
+			if !ok {
+				if actual != nil {
+					t.Fatalf("expected no endpoint for %q but got %q", string(subDomainType), *actual)
+				}
+				continue
+			}
+			if actual == nil {
+				t.Fatalf("expected the endpoint for %q to be %q but got nil", string(subDomainType), expected)
+			}
+			if *actual != expected {
+				t.Fatalf("expected the endpoint for %q to be %q but got %q", string(subDomainType), expected, *actual)
+			}
+		}
+	}
+}
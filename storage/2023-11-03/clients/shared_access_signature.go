@@ -0,0 +1,21 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/auth"
+)
+
+// authorizeUsingSharedAccessSignature returns a function which authorizes each request by appending the
+// SAS Token to the query string, rather than setting an Authorization header.
+func authorizeUsingSharedAccessSignature(sasToken string) func(context.Context, *http.Request, auth.Authorizer) error {
+	return func(_ context.Context, req *http.Request, _ auth.Authorizer) error {
+		if req.URL.RawQuery == "" {
+			req.URL.RawQuery = sasToken
+		} else {
+			req.URL.RawQuery = req.URL.RawQuery + "&" + sasToken
+		}
+		return nil
+	}
+}
@@ -36,7 +36,7 @@ func (c Client) Delete(ctx context.Context, fileSystemName string) (result Delet
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Delete", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -53,7 +53,7 @@ func ParseFileSystemID(input, domainSuffix string) (*FileSystemId, error) {
 		return nil, fmt.Errorf("`input` was empty")
 	}
 
-	account, err := accounts.ParseAccountID(input, domainSuffix)
+	account, err := accounts.ParseAccountIDWithSubDomainType(input, domainSuffix, accounts.DataLakeStoreSubDomainType)
 	if err != nil {
 		return nil, fmt.Errorf("parsing account %q: %+v", input, err)
 	}
@@ -67,7 +67,10 @@ func ParseFileSystemID(input, domainSuffix string) (*FileSystemId, error) {
 		return nil, fmt.Errorf("parsing %q as a uri: %+v", input, err)
 	}
 
-	path := strings.TrimPrefix(uri.Path, "/")
+	path, err := account.ResourcePath(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %+v", input, err)
+	}
 	segments := strings.Split(path, "/")
 	if len(segments) != 1 {
 		return nil, fmt.Errorf("expected the path to contain 1 segment but got %d", len(segments))
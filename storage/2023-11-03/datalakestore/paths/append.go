@@ -0,0 +1,118 @@
+package paths
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
+)
+
+type AppendInput struct {
+	// The position within the file where the Content should be appended, which must be equal
+	// to the length of the file (including any data which has been appended but not yet flushed)
+	Position int64
+
+	// The Bytes which should be appended to the file, which are uploaded but not committed until
+	// the file is flushed (see `Flush`)
+	Content []byte
+
+	// Required if the path has an active lease.
+	LeaseID *string
+
+	// Optionally computes a checksum of the Content using this Algorithm, which the Storage API validates on receipt
+	ChecksumAlgorithm *checksum.Algorithm
+}
+
+type AppendResponse struct {
+	HttpResponse *http.Response
+}
+
+// Append uploads data to be appended to a Data Lake Store Gen2 File within a Storage Account File System
+func (c Client) Append(ctx context.Context, fileSystemName string, path string, input AppendInput) (result AppendResponse, err error) {
+	if fileSystemName == "" {
+		err = fmt.Errorf("`fileSystemName` cannot be an empty string")
+		return
+	}
+
+	if input.Position < 0 {
+		err = fmt.Errorf("`input.Position` cannot be negative")
+		return
+	}
+
+	if len(input.Content) == 0 {
+		err = fmt.Errorf("`input.Content` cannot be empty")
+		return
+	}
+
+	if input.ChecksumAlgorithm != nil {
+		if err = input.ChecksumAlgorithm.Validate(); err != nil {
+			err = fmt.Errorf("`input.ChecksumAlgorithm` is not valid: %+v", err)
+			return
+		}
+	}
+
+	opts := client.RequestOptions{
+		ExpectedStatusCodes: []int{
+			http.StatusAccepted,
+		},
+		HttpMethod: http.MethodPatch,
+		OptionsObject: appendOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", fileSystemName, path),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(input.Content))
+	req.ContentLength = int64(len(input.Content))
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "Append", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type appendOptions struct {
+	input AppendInput
+}
+
+func (a appendOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	headers.Append("Content-Length", strconv.Itoa(len(a.input.Content)))
+	if a.input.LeaseID != nil {
+		headers.Append("x-ms-lease-id", *a.input.LeaseID)
+	}
+	if a.input.ChecksumAlgorithm != nil {
+		headers.Append(a.input.ChecksumAlgorithm.Header(), a.input.ChecksumAlgorithm.Compute(a.input.Content))
+	}
+	return headers
+}
+
+func (a appendOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (a appendOptions) ToQuery() *client.QueryParams {
+	out := &client.QueryParams{}
+	out.Append("action", "append")
+	out.Append("position", strconv.FormatInt(a.input.Position, 10))
+	return out
+}
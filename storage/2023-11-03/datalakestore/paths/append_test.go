@@ -0,0 +1,94 @@
+package paths
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
+)
+
+type recordedPathRequest struct {
+	method string
+	query  url.Values
+	header http.Header
+	body   string
+}
+
+func TestAppendAndFlush(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	requests := make([]recordedPathRequest, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedPathRequest{
+			method: r.Method,
+			query:  r.URL.Query(),
+			header: r.Header,
+			body:   string(body),
+		})
+		if r.URL.Query().Get("action") == "flush" {
+			w.Header().Set("ETag", "0x8D0")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	for _, algorithm := range []checksum.Algorithm{checksum.MD5, checksum.CRC64} {
+		input := AppendInput{
+			Position:          5,
+			Content:           []byte("world"),
+			ChecksumAlgorithm: pointer.To(algorithm),
+		}
+		if _, err := client.Append(ctx, "filesystem1", "folder/file.txt", input); err != nil {
+			t.Fatalf("appending with %s: %+v", string(algorithm), err)
+		}
+	}
+	flushed, err := client.Flush(ctx, "filesystem1", "folder/file.txt", FlushInput{Position: 10, Close: true})
+	if err != nil {
+		t.Fatalf("flushing: %+v", err)
+	}
+	if flushed.ETag != "0x8D0" {
+		t.Fatalf("expected the ETag to be %q but got %q", "0x8D0", flushed.ETag)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests but got %d", len(requests))
+	}
+	for i, algorithm := range []checksum.Algorithm{checksum.MD5, checksum.CRC64} {
+		request := requests[i]
+		if request.method != http.MethodPatch || request.query.Get("action") != "append" || request.query.Get("position") != "5" {
+			t.Fatalf("expected a PATCH with `action=append` and `position=5` but got %s %s", request.method, request.query.Encode())
+		}
+		if request.body != "world" {
+			t.Fatalf("expected the body to be %q but got %q", "world", request.body)
+		}
+		if expected, actual := algorithm.Compute([]byte("world")), request.header.Get(algorithm.Header()); actual != expected {
+			t.Fatalf("expected the %q header to be %q but got %q", algorithm.Header(), expected, actual)
+		}
+	}
+	flush := requests[2]
+	if flush.method != http.MethodPatch || flush.query.Get("action") != "flush" || flush.query.Get("position") != "10" || flush.query.Get("close") != "true" {
+		t.Fatalf("expected a PATCH with `action=flush`, `position=10` and `close=true` but got %s %s", flush.method, flush.query.Encode())
+	}
+	if flush.query.Has("retainUncommittedData") {
+		t.Fatalf("expected `retainUncommittedData` not to be sent but got %s", flush.query.Encode())
+	}
+
+	if _, err := client.Append(ctx, "filesystem1", "folder/file.txt", AppendInput{Position: 10}); err == nil {
+		t.Fatalf("expected an error appending empty content but didn't get one")
+	}
+}
@@ -0,0 +1,111 @@
+package paths
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+type FlushInput struct {
+	// The length of the file once the appended data has been committed, which must be equal to
+	// the length of the file including all of the data which has been appended
+	Position int64
+
+	// Whether any uncommitted data appended beyond the Position should be retained, rather than deleted
+	RetainUncommittedData bool
+
+	// Whether the file should be closed, raising a `FileClosed` event for any Event Grid subscriptions
+	Close bool
+
+	// Required if the path has an active lease.
+	LeaseID *string
+}
+
+type FlushResponse struct {
+	HttpResponse *http.Response
+
+	ETag         string
+	LastModified string
+}
+
+// Flush commits the data previously appended to a Data Lake Store Gen2 File within a Storage Account File System
+func (c Client) Flush(ctx context.Context, fileSystemName string, path string, input FlushInput) (result FlushResponse, err error) {
+	if fileSystemName == "" {
+		err = fmt.Errorf("`fileSystemName` cannot be an empty string")
+		return
+	}
+
+	if input.Position < 0 {
+		err = fmt.Errorf("`input.Position` cannot be negative")
+		return
+	}
+
+	opts := client.RequestOptions{
+		ExpectedStatusCodes: []int{
+			http.StatusOK,
+		},
+		HttpMethod: http.MethodPatch,
+		OptionsObject: flushOptions{
+			input: input,
+		},
+		Path: fmt.Sprintf("/%s/%s", fileSystemName, path),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "Flush", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+
+		if err == nil {
+			if resp.Header != nil {
+				result.ETag = resp.Header.Get("ETag")
+				result.LastModified = resp.Header.Get("Last-Modified")
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type flushOptions struct {
+	input FlushInput
+}
+
+func (f flushOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	if f.input.LeaseID != nil {
+		headers.Append("x-ms-lease-id", *f.input.LeaseID)
+	}
+	return headers
+}
+
+func (f flushOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (f flushOptions) ToQuery() *client.QueryParams {
+	out := &client.QueryParams{}
+	out.Append("action", "flush")
+	out.Append("position", strconv.FormatInt(f.input.Position, 10))
+	if f.input.RetainUncommittedData {
+		out.Append("retainUncommittedData", "true")
+	}
+	if f.input.Close {
+		out.Append("close", "true")
+	}
+	return out
+}
@@ -61,7 +61,7 @@ func (c Client) GetProperties(ctx context.Context, fileSystemName string, path s
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -54,7 +54,7 @@ func (c Client) SetAccessControl(ctx context.Context, fileSystemName string, pat
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetAccessControl", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -56,7 +56,7 @@ func (c Client) Get(ctx context.Context, shareName, path string) (result GetResp
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Get", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
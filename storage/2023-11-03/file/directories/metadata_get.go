@@ -51,7 +51,7 @@ func (c Client) GetMetaData(ctx context.Context, shareName, path string) (result
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetMetaData", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -51,7 +51,7 @@ func ParseDirectoryID(input, domainSuffix string) (*DirectoryId, error) {
 		return nil, fmt.Errorf("`input` was empty")
 	}
 
-	account, err := accounts.ParseAccountID(input, domainSuffix)
+	account, err := accounts.ParseAccountIDWithSubDomainType(input, domainSuffix, accounts.FileSubDomainType)
 	if err != nil {
 		return nil, fmt.Errorf("parsing account %q: %+v", input, err)
 	}
@@ -65,7 +65,10 @@ func ParseDirectoryID(input, domainSuffix string) (*DirectoryId, error) {
 		return nil, fmt.Errorf("parsing %q as a uri: %+v", input, err)
 	}
 
-	path := strings.TrimPrefix(uri.Path, "/")
+	path, err := account.ResourcePath(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %+v", input, err)
+	}
 	segments := strings.Split(path, "/")
 	if len(segments) < 2 {
 		return nil, fmt.Errorf("expected the path to contain at least 2 segments but got %d", len(segments))
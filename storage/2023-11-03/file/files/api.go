@@ -21,4 +21,5 @@ type StorageFile interface {
 	Delete(ctx context.Context, shareName string, path string, fileName string) (DeleteResponse, error)
 	Create(ctx context.Context, shareName string, path string, fileName string, input CreateInput) (CreateResponse, error)
 	CopyAndWait(ctx context.Context, shareName, path, fileName string, input CopyInput) (CopyResponse, error)
+	CopyAndWaitWithOptions(ctx context.Context, shareName, path, fileName string, input CopyInput, options CopyAndWaitOptions) (CopyResponse, error)
 }
@@ -85,7 +85,7 @@ func (c Client) Copy(ctx context.Context, shareName, path, fileName string, inpu
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Copy", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -11,7 +11,8 @@ import (
 )
 
 type CopyAbortInput struct {
-	copyID string
+	// The Copy ID which should be aborted
+	CopyID string
 }
 
 type CopyAbortResponse struct {
@@ -36,8 +37,8 @@ func (c Client) AbortCopy(ctx context.Context, shareName, path, fileName string,
 		return
 	}
 
-	if input.copyID == "" {
-		err = fmt.Errorf("`copyID` cannot be an empty string")
+	if input.CopyID == "" {
+		err = fmt.Errorf("`input.CopyID` cannot be an empty string")
 		return
 	}
 
@@ -52,7 +53,7 @@ func (c Client) AbortCopy(ctx context.Context, shareName, path, fileName string,
 		},
 		HttpMethod: http.MethodPut,
 		OptionsObject: CopyAbortOptions{
-			copyId: input.copyID,
+			copyId: input.CopyID,
 		},
 		Path: fmt.Sprintf("/%s/%s%s", shareName, path, fileName),
 	}
@@ -64,7 +65,7 @@ func (c Client) AbortCopy(ctx context.Context, shareName, path, fileName string,
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "AbortCopy", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -2,16 +2,52 @@ package files
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client/pollers"
 )
 
+type CopyAndWaitOptions struct {
+	// PollInterval is how often the status of the copy is checked. Defaults to 10 seconds.
+	PollInterval time.Duration
+
+	// Progress is optionally called each time the status of the copy is checked, with the number of bytes
+	// which have been copied and the total number of bytes to copy.
+	Progress func(bytesCopied, totalBytes int64)
+
+	// AbortOnCancel specifies whether the copy should be aborted when `ctx` is cancelled (or its deadline
+	// is exceeded) before the copy has completed.
+	AbortOnCancel bool
+}
+
+// CopyFailedError is returned when a copy finishes with a status of either `failed` or `aborted`.
+type CopyFailedError struct {
+	// CopyID is the ID of the copy operation.
+	CopyID string
+
+	// CopyStatus is the final status of the copy operation, either `failed` or `aborted`.
+	CopyStatus string
+
+	// CopyStatusDescription describes why the copy operation failed.
+	CopyStatusDescription string
+}
+
+func (e *CopyFailedError) Error() string {
+	return fmt.Sprintf("copy %q finished with the status %q: %s", e.CopyID, e.CopyStatus, e.CopyStatusDescription)
+}
+
 // CopyAndWait is a convenience method which doesn't exist in the API, which copies the file and then waits for the copy to complete
 func (c Client) CopyAndWait(ctx context.Context, shareName, path, fileName string, input CopyInput) (result CopyResponse, err error) {
+	return c.CopyAndWaitWithOptions(ctx, shareName, path, fileName, input, CopyAndWaitOptions{})
+}
+
+// CopyAndWaitWithOptions is a convenience method which doesn't exist in the API, which copies the file and then waits
+// for the copy to complete using the specified options - returning a *CopyFailedError if the copy fails or is aborted.
+func (c Client) CopyAndWaitWithOptions(ctx context.Context, shareName, path, fileName string, input CopyInput, options CopyAndWaitOptions) (result CopyResponse, err error) {
 	fileCopy, e := c.Copy(ctx, shareName, path, fileName, input)
-	if err != nil {
+	if e != nil {
 		result.HttpResponse = fileCopy.HttpResponse
 		err = fmt.Errorf("copying: %s", e)
 		return
@@ -20,8 +56,23 @@ func (c Client) CopyAndWait(ctx context.Context, shareName, path, fileName strin
 	result.CopyID = fileCopy.CopyID
 
 	pollerType := NewCopyAndWaitPoller(&c, shareName, path, fileName)
-	poller := pollers.NewPoller(pollerType, 10*time.Second, pollers.DefaultNumberOfDroppedConnectionsToAllow)
+	pollerType.pollInterval = options.PollInterval
+	pollerType.progress = options.Progress
+	poller := pollers.NewPoller(pollerType, pollerType.interval(), pollers.DefaultNumberOfDroppedConnectionsToAllow)
 	if err = poller.PollUntilDone(ctx); err != nil {
+		var copyFailed *CopyFailedError
+		if errors.As(err, &copyFailed) {
+			return result, copyFailed
+		}
+
+		if options.AbortOnCancel && ctx.Err() != nil {
+			abortCtx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+			defer cancel()
+			if _, abortErr := c.AbortCopy(abortCtx, shareName, path, fileName, CopyAbortInput{CopyID: result.CopyID}); abortErr != nil {
+				return result, fmt.Errorf("waiting for file to copy: %+v (aborting the copy: %+v)", err, abortErr)
+			}
+		}
+
 		return result, fmt.Errorf("waiting for file to copy: %+v", err)
 	}
 
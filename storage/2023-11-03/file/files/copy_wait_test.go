@@ -2,7 +2,11 @@ package files
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -22,9 +26,9 @@ func TestFilesCopyAndWaitFromURL(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	resourceGroup := fmt.Sprintf("acctestrg-%d", testhelpers.RandomInt())
-	accountName := fmt.Sprintf("acctestsa%s", testhelpers.RandomString())
-	shareName := fmt.Sprintf("share-%d", testhelpers.RandomInt())
+	resourceGroup := fmt.Sprintf("acctestrg-%d", client.RandomInt())
+	accountName := fmt.Sprintf("acctestsa%s", client.RandomString())
+	shareName := fmt.Sprintf("share-%d", client.RandomInt())
 
 	testData, err := client.BuildTestResources(ctx, resourceGroup, accountName, storageaccounts.KindStorage)
 	if err != nil {
@@ -86,9 +90,9 @@ func TestFilesCopyAndWaitFromBlob(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	resourceGroup := fmt.Sprintf("acctestrg-%d", testhelpers.RandomInt())
-	accountName := fmt.Sprintf("acctestsa%s", testhelpers.RandomString())
-	shareName := fmt.Sprintf("share-%d", testhelpers.RandomInt())
+	resourceGroup := fmt.Sprintf("acctestrg-%d", client.RandomInt())
+	accountName := fmt.Sprintf("acctestsa%s", client.RandomString())
+	shareName := fmt.Sprintf("share-%d", client.RandomInt())
 
 	testData, err := client.BuildTestResources(ctx, resourceGroup, accountName, storageaccounts.KindStorage)
 	if err != nil {
@@ -148,3 +152,62 @@ func TestFilesCopyAndWaitFromBlob(t *testing.T) {
 		t.Fatalf("Expected the Copy Status to be `Success` but got %q", props.CopyStatus)
 	}
 }
+
+func TestFilesCopyAndWaitFailed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			w.Header().Set("x-ms-copy-id", "copy1")
+			w.Header().Set("x-ms-copy-status", "pending")
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodHead:
+			polls++
+			status := "pending"
+			if polls > 1 {
+				status = "aborted"
+			}
+			w.Header().Set("x-ms-copy-id", "copy1")
+			w.Header().Set("x-ms-copy-status", status)
+			w.Header().Set("x-ms-copy-progress", "0/1024")
+			w.Header().Set("x-ms-copy-status-description", "the copy was aborted")
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	filesClient, err := NewWithBaseUri(server.URL + "/devstoreaccount1")
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	authorizer, err := auth.NewSharedKeyAuthorizer("devstoreaccount1", base64.StdEncoding.EncodeToString([]byte("key")), auth.SharedKey)
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	filesClient.Client.SetAuthorizer(authorizer)
+
+	reported := 0
+	options := CopyAndWaitOptions{
+		PollInterval: 10 * time.Millisecond,
+		Progress: func(bytesCopied, totalBytes int64) {
+			reported++
+		},
+	}
+	result, err := filesClient.CopyAndWaitWithOptions(ctx, "share1", "", "file.txt", CopyInput{CopySource: "https://example.com/file.txt"}, options)
+	var copyFailed *CopyFailedError
+	if !errors.As(err, &copyFailed) {
+		t.Fatalf("expected a CopyFailedError but got: %+v", err)
+	}
+	if copyFailed.CopyStatus != "aborted" || copyFailed.CopyStatusDescription != "the copy was aborted" {
+		t.Fatalf("expected the CopyFailedError to contain the copy status and description but got %+v", *copyFailed)
+	}
+	if result.CopyID != "copy1" {
+		t.Fatalf("expected the Copy ID to be returned but got %q", result.CopyID)
+	}
+	if reported != 2 {
+		t.Fatalf("expected progress to be reported for each of the 2 polls but got %d", reported)
+	}
+}
@@ -99,7 +99,7 @@ func (c Client) Create(ctx context.Context, shareName, path, fileName string, in
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Create", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
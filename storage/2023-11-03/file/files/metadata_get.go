@@ -55,7 +55,7 @@ func (c Client) GetMetaData(ctx context.Context, shareName, path, fileName strin
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetMetaData", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
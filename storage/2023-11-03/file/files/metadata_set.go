@@ -64,7 +64,7 @@ func (c Client) SetMetaData(ctx context.Context, shareName, path, fileName strin
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetMetaData", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -70,7 +70,7 @@ func (c Client) ClearByteRange(ctx context.Context, shareName, path, fileName st
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "ClearByteRange", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
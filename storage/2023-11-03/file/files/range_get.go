@@ -10,11 +10,17 @@ import (
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
 )
 
 type GetByteRangeInput struct {
 	StartBytes int64
 	EndBytes   int64
+
+	// ChecksumAlgorithm optionally requests a checksum for the range, which is verified against the contents
+	// that are returned - a *checksum.MismatchError is returned if these don't match. Only MD5 is supported
+	// by the File Service.
+	ChecksumAlgorithm *checksum.Algorithm
 }
 
 type GetByteRangeResponse struct {
@@ -60,6 +66,11 @@ func (c Client) GetByteRange(ctx context.Context, shareName, path, fileName stri
 		return
 	}
 
+	if input.ChecksumAlgorithm != nil && *input.ChecksumAlgorithm != checksum.MD5 {
+		err = fmt.Errorf("`input.ChecksumAlgorithm` must be %q since only MD5 is supported by the File Service", string(checksum.MD5))
+		return
+	}
+
 	if path != "" {
 		path = fmt.Sprintf("%s/", path)
 	}
@@ -83,7 +94,7 @@ func (c Client) GetByteRange(ctx context.Context, shareName, path, fileName stri
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetByteRange", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -96,6 +107,12 @@ func (c Client) GetByteRange(ctx context.Context, shareName, path, fileName stri
 					return result, fmt.Errorf("could not parse response body")
 				}
 
+				if input.ChecksumAlgorithm != nil {
+					if err := input.ChecksumAlgorithm.Verify(resp.Header, respBody); err != nil {
+						return result, err
+					}
+				}
+
 				if respBody != nil {
 					result.Contents = pointer.To(respBody)
 				}
@@ -117,6 +134,9 @@ type GetByteRangeOptions struct {
 func (g GetByteRangeOptions) ToHeaders() *client.Headers {
 	headers := &client.Headers{}
 	headers.Append("x-ms-range", fmt.Sprintf("bytes=%d-%d", g.input.StartBytes, g.input.EndBytes-1))
+	if g.input.ChecksumAlgorithm != nil {
+		headers.Append(g.input.ChecksumAlgorithm.RangeHeader(), "true")
+	}
 	return headers
 }
 
@@ -3,7 +3,7 @@ package files
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"net/http"
 	"runtime"
@@ -58,7 +58,7 @@ func (c Client) GetFile(ctx context.Context, shareName, path, fileName string, i
 
 	for i := 0; i < chunks; i++ {
 		go func(i int) {
-			log.Printf("[DEBUG] Downloading Chunk %d of %d", i+1, chunks)
+			c.Logger.Log(ctx, slog.LevelDebug, "downloading chunk", "share", shareName, "file", fileName, "chunk", i+1, "chunks", chunks)
 
 			dfci := downloadFileChunkInput{
 				thisChunk: i,
@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
 )
 
 type PutByteRangeInput struct {
@@ -20,6 +21,10 @@ type PutByteRangeInput struct {
 	// Content is the File Contents for the specified range
 	// which can be at most 4MB
 	Content []byte
+
+	// ChecksumAlgorithm optionally computes a checksum of the Content using this Algorithm, which the
+	// Storage API validates on receipt - only MD5 is supported by the File Service.
+	ChecksumAlgorithm *checksum.Algorithm
 }
 
 type PutRangeResponse struct {
@@ -64,6 +69,11 @@ func (c Client) PutByteRange(ctx context.Context, shareName, path, fileName stri
 		return
 	}
 
+	if input.ChecksumAlgorithm != nil && *input.ChecksumAlgorithm != checksum.MD5 {
+		err = fmt.Errorf("`input.ChecksumAlgorithm` must be %q since only MD5 is supported by the File Service", string(checksum.MD5))
+		return
+	}
+
 	if path != "" {
 		path = fmt.Sprintf("%s/", path)
 	}
@@ -89,7 +99,7 @@ func (c Client) PutByteRange(ctx context.Context, shareName, path, fileName stri
 	req.ContentLength = int64(len(input.Content))
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "PutByteRange", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -110,6 +120,9 @@ func (p PutRangeOptions) ToHeaders() *client.Headers {
 	headers.Append("x-ms-write", "update")
 	headers.Append("x-ms-range", fmt.Sprintf("bytes=%d-%d", p.input.StartBytes, p.input.EndBytes-1))
 	headers.Append("Content-Length", strconv.Itoa(len(p.input.Content)))
+	if p.input.ChecksumAlgorithm != nil {
+		headers.Append(p.input.ChecksumAlgorithm.Header(), p.input.ChecksumAlgorithm.Compute(p.input.Content))
+	}
 	return headers
 }
 
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"math"
 	"os"
 	"sync"
@@ -41,7 +41,7 @@ func (c Client) PutFile(ctx context.Context, shareName, path, fileName string, f
 	for i := 0; i < workerCount; i++ {
 		go func() {
 			for i := range jobs {
-				log.Printf("[DEBUG] Chunk %d of %d", i+1, chunks)
+				c.Logger.Log(ctx, slog.LevelDebug, "uploading chunk", "share", shareName, "file", fileName, "chunk", i+1, "chunks", chunks)
 
 				uci := uploadChunkInput{
 					thisChunk: i,
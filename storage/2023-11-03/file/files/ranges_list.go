@@ -64,7 +64,7 @@ func (c Client) ListRanges(ctx context.Context, shareName, path, fileName string
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "ListRanges", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -44,7 +44,7 @@ func (c Client) GetACL(ctx context.Context, shareName string) (result GetACLResu
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetACL", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
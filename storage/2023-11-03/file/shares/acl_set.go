@@ -63,7 +63,7 @@ func (c Client) SetACL(ctx context.Context, shareName string, input SetAclInput)
 	req.Body = io.NopCloser(bytes.NewReader(bytesWithHeader))
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetACL", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
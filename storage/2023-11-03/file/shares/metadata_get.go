@@ -43,7 +43,7 @@ func (c Client) GetMetaData(ctx context.Context, shareName string) (result GetMe
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetMetaData", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -56,7 +56,7 @@ func (c Client) SetMetaData(ctx context.Context, shareName string, input SetMeta
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetMetaData", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
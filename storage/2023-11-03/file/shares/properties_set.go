@@ -54,7 +54,7 @@ func (c Client) SetProperties(ctx context.Context, shareName string, properties
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
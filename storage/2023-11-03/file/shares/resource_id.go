@@ -53,7 +53,7 @@ func ParseShareID(input, domainSuffix string) (*ShareId, error) {
 		return nil, fmt.Errorf("`input` was empty")
 	}
 
-	account, err := accounts.ParseAccountID(input, domainSuffix)
+	account, err := accounts.ParseAccountIDWithSubDomainType(input, domainSuffix, accounts.FileSubDomainType)
 	if err != nil {
 		return nil, fmt.Errorf("parsing account %q: %+v", input, err)
 	}
@@ -67,13 +67,16 @@ func ParseShareID(input, domainSuffix string) (*ShareId, error) {
 		return nil, fmt.Errorf("parsing %q as a uri: %+v", input, err)
 	}
 
-	path := strings.TrimPrefix(uri.Path, "/")
+	path, err := account.ResourcePath(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %+v", input, err)
+	}
 	segments := strings.Split(path, "/")
 	if len(segments) == 0 {
 		return nil, fmt.Errorf("expected the path to contain segments but got none")
 	}
 
-	shareName := strings.TrimPrefix(uri.Path, "/")
+	shareName := path
 	return &ShareId{
 		AccountId: *account,
 		ShareName: shareName,
@@ -63,7 +63,7 @@ func (c Client) CreateSnapshot(ctx context.Context, shareName string, input Crea
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "CreateSnapshot", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
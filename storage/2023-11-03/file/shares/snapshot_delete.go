@@ -48,7 +48,7 @@ func (c Client) DeleteSnapshot(ctx context.Context, accountName, shareName strin
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "DeleteSnapshot", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
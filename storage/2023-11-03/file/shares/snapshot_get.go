@@ -57,7 +57,7 @@ func (c Client) GetSnapshot(ctx context.Context, shareName string, input GetSnap
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetSnapshot", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -47,7 +47,7 @@ func (c Client) GetStats(ctx context.Context, shareName string) (result GetStats
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetStats", req)
 	if resp != nil {
 		result.HttpResponse = resp.Response
 
@@ -61,7 +61,7 @@ func (c Client) Get(ctx context.Context, queueName string, input GetInput) (resu
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Get", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -51,7 +51,7 @@ func (c Client) Peek(ctx context.Context, queueName string, input PeekInput) (re
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Peek", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -73,7 +73,7 @@ func (c Client) Put(ctx context.Context, queueName string, input PutInput) (resu
 	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Put", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -77,7 +77,7 @@ func (c Client) Update(ctx context.Context, queueName string, messageID string,
 	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Update", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
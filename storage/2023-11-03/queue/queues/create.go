@@ -53,7 +53,7 @@ func (c Client) Create(ctx context.Context, queueName string, input CreateInput)
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Create", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -41,7 +41,7 @@ func (c Client) Delete(ctx context.Context, queueName string) (result DeleteResp
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Delete", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -53,7 +53,7 @@ func (c Client) SetMetaData(ctx context.Context, queueName string, input SetMeta
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetMetaData", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
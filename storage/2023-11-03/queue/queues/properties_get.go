@@ -34,7 +34,7 @@ func (c Client) GetServiceProperties(ctx context.Context) (result GetStorageServ
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetServiceProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
@@ -50,7 +50,7 @@ func (c Client) SetServiceProperties(ctx context.Context, input SetStorageServic
 	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetServiceProperties", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -160,3 +160,38 @@ func TestFormatQueueIDInEdgeZone(t *testing.T) {
 		t.Fatalf("expected %q but got %q", expected, actual)
 	}
 }
+
+func TestParseQueueIDPathStyle(t *testing.T) {
+	input := "http://127.0.0.1:10001/devstoreaccount1/queue1"
+	actual, err := ParseQueueID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountId.AccountName != "devstoreaccount1" {
+		t.Fatalf("expected AccountName to be %q but got %q", "devstoreaccount1", actual.AccountId.AccountName)
+	}
+	if actual.QueueName != "queue1" {
+		t.Fatalf("expected QueueName to be %q but got %q", "queue1", actual.QueueName)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
+
+func TestParseQueueIDPathStyleMappedPort(t *testing.T) {
+	// e.g. a Storage Emulator running in a container, with the Queue Service mapped to a random port
+	input := "http://127.0.0.1:49154/devstoreaccount1/queue1"
+	actual, err := ParseQueueID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountId.SubDomainType != accounts.QueueSubDomainType {
+		t.Fatalf("expected SubDomainType to be %q but got %q", accounts.QueueSubDomainType, actual.AccountId.SubDomainType)
+	}
+	if actual.QueueName != "queue1" {
+		t.Fatalf("expected QueueName to be %q but got %q", "queue1", actual.QueueName)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
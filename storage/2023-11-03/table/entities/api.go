@@ -5,10 +5,13 @@ import (
 )
 
 type StorageTableEntity interface {
+	Batch(ctx context.Context, tableName string, input BatchInput) (resp BatchResponse, err error)
 	Delete(ctx context.Context, tableName string, input DeleteEntityInput) (resp DeleteEntityResponse, err error)
 	Insert(ctx context.Context, tableName string, input InsertEntityInput) (resp InsertResponse, err error)
 	InsertOrReplace(ctx context.Context, tableName string, input InsertOrReplaceEntityInput) (resp InsertOrReplaceResponse, err error)
 	InsertOrMerge(ctx context.Context, tableName string, input InsertOrMergeEntityInput) (resp InsertOrMergeResponse, err error)
+	Merge(ctx context.Context, tableName string, input MergeEntityInput) (resp MergeEntityResponse, err error)
 	Query(ctx context.Context, tableName string, input QueryEntitiesInput) (resp QueryEntitiesResponse, err error)
 	Get(ctx context.Context, tableName string, input GetEntityInput) (resp GetEntityResponse, err error)
+	Update(ctx context.Context, tableName string, input UpdateEntityInput) (resp UpdateEntityResponse, err error)
 }
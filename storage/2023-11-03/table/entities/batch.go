@@ -0,0 +1,340 @@
+package entities
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+// MaxBatchOperations is the maximum number of operations which can be performed in a single Batch.
+const MaxBatchOperations = 100
+
+type BatchOperationType string
+
+var (
+	BatchOperationDelete          BatchOperationType = "Delete"
+	BatchOperationInsert          BatchOperationType = "Insert"
+	BatchOperationInsertOrMerge   BatchOperationType = "InsertOrMerge"
+	BatchOperationInsertOrReplace BatchOperationType = "InsertOrReplace"
+	BatchOperationMerge           BatchOperationType = "Merge"
+	BatchOperationUpdate          BatchOperationType = "Update"
+)
+
+type BatchOperation struct {
+	// The type of operation which should be performed for this Entity
+	Type BatchOperationType
+
+	// The Entity which should be inserted/merged/replaced, this isn't used for Delete operations.
+	Entity map[string]interface{}
+
+	// The ETag of the Entity for Delete, Merge and Update operations, if not specified
+	// the operation is performed unconditionally.
+	IfMatch *string
+
+	// All operations within a Batch must use the same PartitionKey.
+	PartitionKey string
+	RowKey       string
+}
+
+type BatchInput struct {
+	// The operations which should be performed as a single transaction, up to a maximum of 100.
+	Operations []BatchOperation
+}
+
+type BatchResponse struct {
+	HttpResponse *http.Response
+
+	// ETags contains the new ETag for each of the Operations, at the same index as the Operation within `Operations`.
+	// This is empty for Delete operations.
+	ETags []string
+}
+
+// Batch performs the specified operations against Entities within a single Partition of a table as a
+// single atomic transaction (known as an Entity Group Transaction) - either all operations succeed or none do.
+func (c Client) Batch(ctx context.Context, tableName string, input BatchInput) (result BatchResponse, err error) {
+	if tableName == "" {
+		return result, fmt.Errorf("`tableName` cannot be an empty string")
+	}
+
+	if len(input.Operations) == 0 {
+		return result, fmt.Errorf("`input.Operations` must contain at least one operation")
+	}
+
+	if len(input.Operations) > MaxBatchOperations {
+		return result, fmt.Errorf("`input.Operations` can contain at most %d operations but got %d", MaxBatchOperations, len(input.Operations))
+	}
+
+	partitionKey := input.Operations[0].PartitionKey
+	for i, operation := range input.Operations {
+		if operation.PartitionKey == "" {
+			return result, fmt.Errorf("`input.Operations[%d].PartitionKey` cannot be an empty string", i)
+		}
+		if operation.PartitionKey != partitionKey {
+			return result, fmt.Errorf("`input.Operations[%d].PartitionKey` must match the PartitionKey of the other operations (%q) but got %q", i, partitionKey, operation.PartitionKey)
+		}
+		if operation.RowKey == "" {
+			return result, fmt.Errorf("`input.Operations[%d].RowKey` cannot be an empty string", i)
+		}
+	}
+
+	body, boundary, err := buildBatchBody(c.Client.BaseUri, tableName, input.Operations)
+	if err != nil {
+		return result, fmt.Errorf("building batch body: %+v", err)
+	}
+
+	opts := client.RequestOptions{
+		ContentType: fmt.Sprintf("multipart/mixed; boundary=%s", boundary),
+		ExpectedStatusCodes: []int{
+			http.StatusAccepted,
+		},
+		HttpMethod:    http.MethodPost,
+		OptionsObject: batchOptions{},
+		Path:          "/$batch",
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	err = req.Marshal(body)
+	if err != nil {
+		return result, fmt.Errorf("marshalling request: %+v", err)
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "Batch", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+	}
+	if err != nil {
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	result.ETags, err = parseBatchResponse(resp.Response, len(input.Operations))
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+type batchOptions struct{}
+
+func (b batchOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	headers.Append("Accept", "application/json;odata=minimalmetadata")
+	headers.Append("DataServiceVersion", "3.0;NetFx")
+	headers.Append("MaxDataServiceVersion", "3.0;NetFx")
+	return headers
+}
+
+func (b batchOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (b batchOptions) ToQuery() *client.QueryParams {
+	return nil
+}
+
+func buildBatchBody(baseUri, tableName string, operations []BatchOperation) ([]byte, string, error) {
+	changeSet := &bytes.Buffer{}
+	changeSetWriter := multipart.NewWriter(changeSet)
+	if err := changeSetWriter.SetBoundary(fmt.Sprintf("changeset_%s", uuid.New().String())); err != nil {
+		return nil, "", err
+	}
+
+	for i, operation := range operations {
+		method := http.MethodPut
+		ifMatch := ""
+		switch operation.Type {
+		case BatchOperationDelete:
+			method = http.MethodDelete
+			ifMatch = "*"
+		case BatchOperationInsert:
+			method = http.MethodPost
+		case BatchOperationInsertOrMerge:
+			method = "MERGE"
+		case BatchOperationInsertOrReplace:
+			method = http.MethodPut
+		case BatchOperationMerge:
+			method = "MERGE"
+			ifMatch = "*"
+		case BatchOperationUpdate:
+			method = http.MethodPut
+			ifMatch = "*"
+		default:
+			return nil, "", fmt.Errorf("operation %d: unsupported operation type %q", i, string(operation.Type))
+		}
+		if ifMatch != "" && operation.IfMatch != nil {
+			ifMatch = *operation.IfMatch
+		}
+
+		uri := fmt.Sprintf("%s/%s(PartitionKey='%s',RowKey='%s')", strings.TrimSuffix(baseUri, "/"), tableName, operation.PartitionKey, operation.RowKey)
+		if operation.Type == BatchOperationInsert {
+			uri = fmt.Sprintf("%s/%s", strings.TrimSuffix(baseUri, "/"), tableName)
+		}
+
+		request := &bytes.Buffer{}
+		fmt.Fprintf(request, "%s %s HTTP/1.1\r\n", method, uri)
+		fmt.Fprintf(request, "Accept: application/json;odata=minimalmetadata\r\n")
+		fmt.Fprintf(request, "DataServiceVersion: 3.0;\r\n")
+		fmt.Fprintf(request, "Content-ID: %d\r\n", i+1)
+		if ifMatch != "" {
+			fmt.Fprintf(request, "If-Match: %s\r\n", ifMatch)
+		}
+		if operation.Type == BatchOperationDelete {
+			fmt.Fprintf(request, "\r\n")
+		} else {
+			entity := make(map[string]interface{}, len(operation.Entity)+2)
+			for k, v := range operation.Entity {
+				entity[k] = v
+			}
+			entity["PartitionKey"] = operation.PartitionKey
+			entity["RowKey"] = operation.RowKey
+			payload, err := json.Marshal(entity)
+			if err != nil {
+				return nil, "", fmt.Errorf("operation %d: marshalling entity: %+v", i, err)
+			}
+			fmt.Fprintf(request, "Content-Type: application/json\r\n")
+			fmt.Fprintf(request, "Content-Length: %d\r\n", len(payload))
+			fmt.Fprintf(request, "Prefer: return-no-content\r\n")
+			fmt.Fprintf(request, "\r\n")
+			request.Write(payload)
+		}
+
+		partHeaders := textproto.MIMEHeader{}
+		partHeaders.Set("Content-Type", "application/http")
+		partHeaders.Set("Content-Transfer-Encoding", "binary")
+		part, err := changeSetWriter.CreatePart(partHeaders)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(request.Bytes()); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := changeSetWriter.Close(); err != nil {
+		return nil, "", err
+	}
+
+	batch := &bytes.Buffer{}
+	batchWriter := multipart.NewWriter(batch)
+	if err := batchWriter.SetBoundary(fmt.Sprintf("batch_%s", uuid.New().String())); err != nil {
+		return nil, "", err
+	}
+	partHeaders := textproto.MIMEHeader{}
+	partHeaders.Set("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%s", changeSetWriter.Boundary()))
+	part, err := batchWriter.CreatePart(partHeaders)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(changeSet.Bytes()); err != nil {
+		return nil, "", err
+	}
+	if err := batchWriter.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return batch.Bytes(), batchWriter.Boundary(), nil
+}
+
+// parseBatchResponse parses the multipart response to a Batch, returning the ETags for each operation or an error
+// should any of the operations have failed - in which case the service returns a single response for the failed operation.
+func parseBatchResponse(resp *http.Response, numberOfOperations int) ([]string, error) {
+	responses, err := parseMultipartResponses(resp.Header.Get("Content-Type"), resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing batch response: %+v", err)
+	}
+
+	etags := make([]string, 0, numberOfOperations)
+	for _, r := range responses {
+		if r.StatusCode >= 200 && r.StatusCode <= 299 {
+			etags = append(etags, r.Header.Get("ETag"))
+			continue
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		message := strings.TrimSpace(string(body))
+		var odataError struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message struct {
+					Value string `json:"value"`
+				} `json:"message"`
+			} `json:"odata.error"`
+		}
+		if err := json.Unmarshal(body, &odataError); err == nil && odataError.Error.Code != "" {
+			message = fmt.Sprintf("%s: %s", odataError.Error.Code, odataError.Error.Message.Value)
+		}
+
+		if r.StatusCode == http.StatusPreconditionFailed {
+			return nil, fmt.Errorf("batch operation failed: %w: %s", ErrPreconditionFailed, message)
+		}
+		return nil, fmt.Errorf("batch operation failed with status %d: %s", r.StatusCode, message)
+	}
+
+	if len(etags) != numberOfOperations {
+		return nil, fmt.Errorf("expected %d responses to the batch but got %d", numberOfOperations, len(etags))
+	}
+
+	return etags, nil
+}
+
+func parseMultipartResponses(contentType string, body io.Reader) ([]*http.Response, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("parsing content type %q: %+v", contentType, err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("expected a multipart content type but got %q", mediaType)
+	}
+
+	out := make([]*http.Response, 0)
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		partContentType := part.Header.Get("Content-Type")
+		if strings.HasPrefix(partContentType, "multipart/") {
+			nested, err := parseMultipartResponses(partContentType, part)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, nested...)
+			continue
+		}
+
+		contents, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		r, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(contents)), nil)
+		if err != nil {
+			return nil, fmt.Errorf("parsing response: %+v", err)
+		}
+		out = append(out, r)
+	}
+
+	return out, nil
+}
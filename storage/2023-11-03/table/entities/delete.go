@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/hashicorp/go-azure-helpers/lang/response"
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
 )
@@ -17,6 +18,11 @@ type DeleteEntityInput struct {
 	// because they are canonically sorted. For example, you should convert the value 1 to 0000001 to ensure proper sorting.
 	RowKey       string
 	PartitionKey string
+
+	// The ETag of the Entity which should be deleted, as returned from Get or Query.
+	// When specified the delete fails with ErrPreconditionFailed if the Entity has since been modified,
+	// otherwise the Entity is deleted unconditionally.
+	IfMatch *string
 }
 
 type DeleteEntityResponse struct {
@@ -43,9 +49,11 @@ func (c Client) Delete(ctx context.Context, tableName string, input DeleteEntity
 		ExpectedStatusCodes: []int{
 			http.StatusNoContent,
 		},
-		HttpMethod:    http.MethodDelete,
-		OptionsObject: deleteEntitiesOptions{},
-		Path:          fmt.Sprintf("/%s(PartitionKey='%s', RowKey='%s')", tableName, input.PartitionKey, input.RowKey),
+		HttpMethod: http.MethodDelete,
+		OptionsObject: deleteEntitiesOptions{
+			ifMatch: input.IfMatch,
+		},
+		Path: fmt.Sprintf("/%s(PartitionKey='%s', RowKey='%s')", tableName, input.PartitionKey, input.RowKey),
 	}
 
 	req, err := c.Client.NewRequest(ctx, opts)
@@ -55,23 +63,34 @@ func (c Client) Delete(ctx context.Context, tableName string, input DeleteEntity
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Delete", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
 	if err != nil {
+		if response.WasStatusCode(result.HttpResponse, http.StatusPreconditionFailed) {
+			err = fmt.Errorf("executing request: %w: %+v", ErrPreconditionFailed, err)
+			return
+		}
 		err = fmt.Errorf("executing request: %+v", err)
 		return
 	}
 	return
 }
 
-type deleteEntitiesOptions struct{}
+type deleteEntitiesOptions struct {
+	ifMatch *string
+}
 
 func (d deleteEntitiesOptions) ToHeaders() *client.Headers {
 	headers := &client.Headers{}
 	headers.Append("Accept", "application/json")
-	headers.Append("If-Match", "*")
+
+	ifMatch := "*"
+	if d.ifMatch != nil {
+		ifMatch = *d.ifMatch
+	}
+	headers.Append("If-Match", ifMatch)
 	return headers
 }
 
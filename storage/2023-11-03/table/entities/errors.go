@@ -0,0 +1,9 @@
+package entities
+
+import "errors"
+
+// ErrPreconditionFailed is returned when the ETag specified in `IfMatch` doesn't match
+// the current ETag of the Entity - meaning the Entity has been modified since it was retrieved.
+//
+// This can be checked using `errors.Is(err, entities.ErrPreconditionFailed)`.
+var ErrPreconditionFailed = errors.New("the ETag specified in `IfMatch` did not match the current ETag of the Entity")
@@ -20,6 +20,9 @@ type GetEntityInput struct {
 type GetEntityResponse struct {
 	HttpResponse *http.Response
 
+	// The ETag of the Entity, which can be used as `IfMatch` when updating, merging or deleting this Entity
+	ETag string
+
 	Entity map[string]interface{}
 }
 
@@ -55,11 +58,13 @@ func (c Client) Get(ctx context.Context, tableName string, input GetEntityInput)
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Get", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
 		if err == nil {
+			result.ETag = resp.Header.Get("ETag")
+
 			err = resp.Unmarshal(&result.Entity)
 			if err != nil {
 				err = fmt.Errorf("unmarshalling response: %+v", err)
@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type GetEntityAsResponse[T any] struct {
+	HttpResponse *http.Response
+
+	// The ETag of the Entity, which can be used as `IfMatch` when updating, merging or deleting this Entity
+	ETag string
+
+	Entity T
+}
+
+// GetAs retrieves an entity from a table and unmarshals it into `T` - see Unmarshal for more information.
+func GetAs[T any](ctx context.Context, c Client, tableName string, input GetEntityInput) (result GetEntityAsResponse[T], err error) {
+	resp, err := c.Get(ctx, tableName, input)
+	result.HttpResponse = resp.HttpResponse
+	result.ETag = resp.ETag
+	if err != nil {
+		return
+	}
+
+	if err = Unmarshal(resp.Entity, &result.Entity); err != nil {
+		err = fmt.Errorf("unmarshalling entity: %+v", err)
+		return
+	}
+
+	return
+}
@@ -72,7 +72,7 @@ func (c Client) Insert(ctx context.Context, tableName string, input InsertEntity
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Insert", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
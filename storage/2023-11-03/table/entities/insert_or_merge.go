@@ -68,7 +68,7 @@ func (c Client) InsertOrMerge(ctx context.Context, tableName string, input Inser
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "InsertOrMerge", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -30,6 +30,8 @@ type InsertOrReplaceResponse struct {
 
 // InsertOrReplace replaces an existing entity or inserts a new entity if it does not exist in the table.
 // Because this operation can insert or update an entity, it is also known as an upsert operation.
+// Any properties of an existing entity which aren't specified in `input.Entity` are removed - use
+// InsertOrMerge to retain these instead.
 func (c Client) InsertOrReplace(ctx context.Context, tableName string, input InsertOrReplaceEntityInput) (result InsertOrReplaceResponse, err error) {
 	if tableName == "" {
 		return result, fmt.Errorf("`tableName` cannot be an empty string")
@@ -48,7 +50,7 @@ func (c Client) InsertOrReplace(ctx context.Context, tableName string, input Ins
 		ExpectedStatusCodes: []int{
 			http.StatusNoContent,
 		},
-		HttpMethod:    "MERGE",
+		HttpMethod:    http.MethodPut,
 		OptionsObject: insertOrReplaceOptions{},
 		Path:          fmt.Sprintf("/%s(PartitionKey='%s', RowKey='%s')", tableName, input.PartitionKey, input.RowKey),
 	}
@@ -68,7 +70,7 @@ func (c Client) InsertOrReplace(ctx context.Context, tableName string, input Ins
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "InsertOrReplace", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
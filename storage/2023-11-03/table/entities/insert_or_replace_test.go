@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInsertOrReplaceReplacesTheEntity(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var method string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	input := InsertOrReplaceEntityInput{
+		PartitionKey: "partition",
+		RowKey:       "row",
+		Entity: map[string]interface{}{
+			"hello": "world",
+		},
+	}
+	if _, err := client.InsertOrReplace(ctx, "table1", input); err != nil {
+		t.Fatalf("inserting or replacing entity: %+v", err)
+	}
+
+	// a MERGE would retain any existing properties, rather than replacing the entity
+	if method != http.MethodPut {
+		t.Fatalf("expected the entity to be replaced using a %q but got %q", http.MethodPut, method)
+	}
+	if body["hello"] != "world" || body["PartitionKey"] != "partition" || body["RowKey"] != "row" {
+		t.Fatalf("expected the entity to be sent but got %+v", body)
+	}
+}
@@ -2,10 +2,12 @@ package entities
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/storage/2023-01-01/storageaccounts"
 	"github.com/hashicorp/go-azure-sdk/sdk/auth"
 	"github.com/tombuildsstuff/giovanni/storage/2020-08-04/table/tables"
@@ -23,9 +25,9 @@ func TestEntitiesLifecycle(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	resourceGroup := fmt.Sprintf("acctestrg-%d", testhelpers.RandomInt())
-	accountName := fmt.Sprintf("acctestsa%s", testhelpers.RandomString())
-	tableName := fmt.Sprintf("table%d", testhelpers.RandomInt())
+	resourceGroup := fmt.Sprintf("acctestrg-%d", client.RandomInt())
+	accountName := fmt.Sprintf("acctestsa%s", client.RandomString())
+	tableName := fmt.Sprintf("table%d", client.RandomInt())
 
 	testData, err := client.BuildTestResources(ctx, resourceGroup, accountName, storageaccounts.KindStorage)
 	if err != nil {
@@ -145,10 +147,58 @@ func TestEntitiesLifecycle(t *testing.T) {
 		t.Fatalf("Expected Row Key to be %q but got %q", rowKey, rowKey2)
 	}
 
+	if getResults.ETag == "" {
+		t.Fatalf("Expected an ETag to be returned but didn't get one")
+	}
+
+	t.Logf("[DEBUG] Updating..")
+	updateInput := UpdateEntityInput{
+		PartitionKey: partitionKey,
+		RowKey:       rowKey,
+		IfMatch:      getResults.ETag,
+		Entity: map[string]interface{}{
+			"hello": "updated",
+		},
+	}
+	updateResults, err := entitiesClient.Update(ctx, tableName, updateInput)
+	if err != nil {
+		t.Fatalf("Error updating: %s", err)
+	}
+
+	t.Logf("[DEBUG] Updating with a stale ETag..")
+	if _, err := entitiesClient.Update(ctx, tableName, updateInput); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("Expected a precondition failed error but got: %+v", err)
+	}
+
+	t.Logf("[DEBUG] Merging..")
+	mergeInput := MergeEntityInput{
+		PartitionKey: partitionKey,
+		RowKey:       rowKey,
+		IfMatch:      updateResults.ETag,
+		Entity: map[string]interface{}{
+			"another": "value",
+		},
+	}
+	mergeResults, err := entitiesClient.Merge(ctx, tableName, mergeInput)
+	if err != nil {
+		t.Fatalf("Error merging: %s", err)
+	}
+
+	t.Logf("[DEBUG] Deleting with a stale ETag..")
+	staleDeleteInput := DeleteEntityInput{
+		PartitionKey: partitionKey,
+		RowKey:       rowKey,
+		IfMatch:      pointer.To(updateResults.ETag),
+	}
+	if _, err := entitiesClient.Delete(ctx, tableName, staleDeleteInput); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("Expected a precondition failed error but got: %+v", err)
+	}
+
 	t.Logf("[DEBUG] Deleting..")
 	deleteInput := DeleteEntityInput{
 		PartitionKey: partitionKey,
 		RowKey:       rowKey,
+		IfMatch:      pointer.To(mergeResults.ETag),
 	}
 	if _, err := entitiesClient.Delete(ctx, tableName, deleteInput); err != nil {
 		t.Logf("Error deleting: %s", err)
@@ -0,0 +1,539 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EdmType is the OData Entity Data Model type of a property on an Entity
+type EdmType string
+
+var (
+	EdmBinary   EdmType = "Edm.Binary"
+	EdmBoolean  EdmType = "Edm.Boolean"
+	EdmDateTime EdmType = "Edm.DateTime"
+	EdmDouble   EdmType = "Edm.Double"
+	EdmGuid     EdmType = "Edm.Guid"
+	EdmInt32    EdmType = "Edm.Int32"
+	EdmInt64    EdmType = "Edm.Int64"
+	EdmString   EdmType = "Edm.String"
+)
+
+// requiresAnnotation returns whether the Table Service is unable to infer this type from
+// the JSON value alone, meaning an `@odata.type` annotation must be sent/is returned.
+func (t EdmType) requiresAnnotation() bool {
+	switch t {
+	case EdmBinary, EdmDateTime, EdmDouble, EdmGuid, EdmInt64:
+		return true
+	}
+	return false
+}
+
+const (
+	odataTypeSuffix = "@odata.type"
+	tagName         = "table"
+
+	// edmDateTimeFormat is the format used for an Edm.DateTime, which has a precision of 100 nanoseconds
+	edmDateTimeFormat = "2006-01-02T15:04:05.0000000Z"
+)
+
+// Marshal converts the struct `v` into an Entity which can be used for the `Entity` field on
+// Insert, InsertOrMerge, InsertOrReplace, Merge and Update.
+//
+// Properties are named using the `table` struct tag, which takes the form `table:"name,edm=Int64,omitempty"`,
+// where the `edm` option overrides the EDM Type inferred from the Go type and `omitempty` omits zero values.
+// Fields tagged `table:"-"`, unexported fields and fields prefixed with `odata.` (such as `odata.etag`) are skipped.
+//
+// Go types are mapped to EDM Types as follows, unless overridden:
+//
+//   - string: Edm.String (or Edm.Guid)
+//   - bool: Edm.Boolean
+//   - int8, int16, int32, uint8, uint16: Edm.Int32
+//   - int, int64, uint, uint32, uint64: Edm.Int64
+//   - float32, float64: Edm.Double
+//   - time.Time: Edm.DateTime
+//   - []byte: Edm.Binary
+//   - [16]byte (e.g. uuid.UUID): Edm.Guid
+//
+// The `@odata.type` annotations required for the Table Service to store these types are always included, when
+// `metaDataLevel` is FullMetaData annotations are also included for types which the Table Service can infer.
+func Marshal(v interface{}, metaDataLevel MetaDataLevel) (map[string]interface{}, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, fmt.Errorf("`v` cannot be nil")
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("`v` must be a struct or a pointer to a struct but got %s", val.Kind())
+	}
+
+	fields, err := parseFields(val.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{})
+	for _, field := range fields {
+		if strings.HasPrefix(field.name, "odata.") {
+			continue
+		}
+
+		fieldVal := val.FieldByIndex(field.index)
+		if field.omitEmpty && fieldVal.IsZero() {
+			continue
+		}
+		if fieldVal.Kind() == reflect.Ptr {
+			if fieldVal.IsNil() {
+				continue
+			}
+			fieldVal = fieldVal.Elem()
+		}
+
+		value, err := marshalValue(fieldVal, field.edmType)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling property %q: %+v", field.name, err)
+		}
+		out[field.name] = value
+
+		if field.edmType.requiresAnnotation() || metaDataLevel == FullMetaData {
+			out[field.name+odataTypeSuffix] = string(field.edmType)
+		}
+	}
+
+	return out, nil
+}
+
+// Unmarshal populates the struct pointed to by `v` from the Entity returned from Get or Query.
+//
+// The `@odata.type` annotations returned at MinimalMetaData and FullMetaData are used to determine the
+// type of each property where present, otherwise (for example at NoMetaData) the EDM Type is determined
+// from the `table` struct tag or the Go type of the field, as documented on Marshal.
+//
+// Properties in the Entity which have no matching field are ignored. Fields tagged with the name `odata.etag`
+// are populated with the ETag of the Entity, when returned.
+func Unmarshal(entity map[string]interface{}, v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return fmt.Errorf("`v` must be a non-nil pointer to a struct")
+	}
+	val = val.Elem()
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("`v` must be a pointer to a struct but got a pointer to %s", val.Kind())
+	}
+
+	fields, err := parseFields(val.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		raw, ok := entity[field.name]
+		if !ok || raw == nil {
+			continue
+		}
+
+		edmType := field.edmType
+		if annotation, ok := entity[field.name+odataTypeSuffix].(string); ok && annotation != "" {
+			edmType = EdmType(annotation)
+		}
+
+		fieldVal := val.FieldByIndex(field.index)
+		if fieldVal.Kind() == reflect.Ptr {
+			if fieldVal.IsNil() {
+				fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+			}
+			fieldVal = fieldVal.Elem()
+		}
+
+		if err := unmarshalValue(raw, edmType, fieldVal); err != nil {
+			return fmt.Errorf("unmarshalling property %q: %+v", field.name, err)
+		}
+	}
+
+	return nil
+}
+
+type fieldInfo struct {
+	index     []int
+	name      string
+	edmType   EdmType
+	omitEmpty bool
+}
+
+var (
+	byteSliceType = reflect.TypeOf([]byte(nil))
+	guidType      = reflect.TypeOf([16]byte{})
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+func parseFields(t reflect.Type) ([]fieldInfo, error) {
+	out := make([]fieldInfo, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, hasTag := field.Tag.Lookup(tagName)
+		if tag == "-" {
+			continue
+		}
+
+		// flatten any embedded structs which haven't been explicitly named
+		if field.Anonymous && !hasTag {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && embedded != timeType {
+				nested, err := parseFields(embedded)
+				if err != nil {
+					return nil, err
+				}
+				for _, n := range nested {
+					n.index = append([]int{i}, n.index...)
+					out = append(out, n)
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		info := fieldInfo{
+			index: []int{i},
+			name:  field.Name,
+		}
+		segments := strings.Split(tag, ",")
+		if segments[0] != "" {
+			info.name = segments[0]
+		}
+		for _, option := range segments[1:] {
+			switch {
+			case option == "omitempty":
+				info.omitEmpty = true
+			case strings.HasPrefix(option, "edm="):
+				edmType := strings.TrimPrefix(option, "edm=")
+				if !strings.HasPrefix(edmType, "Edm.") {
+					edmType = "Edm." + edmType
+				}
+				info.edmType = EdmType(edmType)
+			default:
+				return nil, fmt.Errorf("field %q: unsupported `table` tag option %q", field.Name, option)
+			}
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if info.edmType == "" {
+			edmType, err := edmTypeForGoType(fieldType)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %+v", field.Name, err)
+			}
+			info.edmType = edmType
+		}
+
+		// system properties/annotations such as `odata.etag` are read-only
+		if strings.HasPrefix(info.name, "odata.") {
+			info.edmType = EdmString
+		}
+
+		out = append(out, info)
+	}
+
+	return out, nil
+}
+
+func edmTypeForGoType(t reflect.Type) (EdmType, error) {
+	switch {
+	case t == timeType:
+		return EdmDateTime, nil
+	case t == byteSliceType:
+		return EdmBinary, nil
+	case t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8:
+		return EdmGuid, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return EdmString, nil
+	case reflect.Bool:
+		return EdmBoolean, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return EdmInt32, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return EdmInt64, nil
+	case reflect.Float32, reflect.Float64:
+		return EdmDouble, nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+func marshalValue(v reflect.Value, edmType EdmType) (interface{}, error) {
+	switch edmType {
+	case EdmBinary:
+		if v.Type() != byteSliceType && !(v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8) {
+			return nil, fmt.Errorf("%s must be a []byte but got %s", edmType, v.Type())
+		}
+		return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+
+	case EdmBoolean:
+		if v.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("%s must be a bool but got %s", edmType, v.Type())
+		}
+		return v.Bool(), nil
+
+	case EdmDateTime:
+		if v.Type() != timeType {
+			return nil, fmt.Errorf("%s must be a time.Time but got %s", edmType, v.Type())
+		}
+		return v.Interface().(time.Time).UTC().Format(edmDateTimeFormat), nil
+
+	case EdmDouble:
+		var f float64
+		switch {
+		case v.CanFloat():
+			f = v.Float()
+		case v.CanInt():
+			f = float64(v.Int())
+		case v.CanUint():
+			f = float64(v.Uint())
+		default:
+			return nil, fmt.Errorf("%s must be a numeric type but got %s", edmType, v.Type())
+		}
+		switch {
+		case math.IsNaN(f):
+			return "NaN", nil
+		case math.IsInf(f, 1):
+			return "Infinity", nil
+		case math.IsInf(f, -1):
+			return "-Infinity", nil
+		}
+		return f, nil
+
+	case EdmGuid:
+		if v.Kind() == reflect.String {
+			return v.String(), nil
+		}
+		if v.Type().ConvertibleTo(guidType) {
+			return uuid.UUID(v.Convert(guidType).Interface().([16]byte)).String(), nil
+		}
+		return nil, fmt.Errorf("%s must be a string or a [16]byte but got %s", edmType, v.Type())
+
+	case EdmInt32:
+		switch {
+		case v.CanInt():
+			i := v.Int()
+			if i < math.MinInt32 || i > math.MaxInt32 {
+				return nil, fmt.Errorf("%d overflows %s", i, edmType)
+			}
+			return i, nil
+		case v.CanUint():
+			i := v.Uint()
+			if i > math.MaxInt32 {
+				return nil, fmt.Errorf("%d overflows %s", i, edmType)
+			}
+			return i, nil
+		}
+		return nil, fmt.Errorf("%s must be an integer type but got %s", edmType, v.Type())
+
+	case EdmInt64:
+		// Int64's are sent as strings since they can exceed the precision of a JSON number
+		switch {
+		case v.CanInt():
+			return strconv.FormatInt(v.Int(), 10), nil
+		case v.CanUint():
+			i := v.Uint()
+			if i > math.MaxInt64 {
+				return nil, fmt.Errorf("%d overflows %s", i, edmType)
+			}
+			return strconv.FormatUint(i, 10), nil
+		}
+		return nil, fmt.Errorf("%s must be an integer type but got %s", edmType, v.Type())
+
+	case EdmString:
+		if v.Kind() != reflect.String {
+			return nil, fmt.Errorf("%s must be a string but got %s", edmType, v.Type())
+		}
+		return v.String(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported EDM Type %q", string(edmType))
+}
+
+func unmarshalValue(raw interface{}, edmType EdmType, v reflect.Value) error {
+	switch edmType {
+	case EdmBinary:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("expected a base64-encoded string for %s but got %T", edmType, raw)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("decoding %s: %+v", edmType, err)
+		}
+		if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("cannot assign %s to %s", edmType, v.Type())
+		}
+		v.SetBytes(b)
+		return nil
+
+	case EdmBoolean:
+		b, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("expected a bool for %s but got %T", edmType, raw)
+		}
+		if v.Kind() != reflect.Bool {
+			return fmt.Errorf("cannot assign %s to %s", edmType, v.Type())
+		}
+		v.SetBool(b)
+		return nil
+
+	case EdmDateTime:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("expected a string for %s but got %T", edmType, raw)
+		}
+		// the fractional seconds are optional, and are parsed regardless of the number of digits
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %+v", edmType, err)
+		}
+		if v.Type() == timeType {
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+		if v.Kind() == reflect.String {
+			v.SetString(s)
+			return nil
+		}
+		return fmt.Errorf("cannot assign %s to %s", edmType, v.Type())
+
+	case EdmDouble:
+		var f float64
+		switch value := raw.(type) {
+		case float64:
+			f = value
+		case json.Number:
+			parsed, err := value.Float64()
+			if err != nil {
+				return fmt.Errorf("parsing %s: %+v", edmType, err)
+			}
+			f = parsed
+		case string:
+			switch value {
+			case "NaN":
+				f = math.NaN()
+			case "Infinity":
+				f = math.Inf(1)
+			case "-Infinity":
+				f = math.Inf(-1)
+			default:
+				parsed, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return fmt.Errorf("parsing %s: %+v", edmType, err)
+				}
+				f = parsed
+			}
+		default:
+			return fmt.Errorf("expected a number for %s but got %T", edmType, raw)
+		}
+		if !v.CanFloat() {
+			return fmt.Errorf("cannot assign %s to %s", edmType, v.Type())
+		}
+		v.SetFloat(f)
+		return nil
+
+	case EdmGuid:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("expected a string for %s but got %T", edmType, raw)
+		}
+		if v.Kind() == reflect.String {
+			v.SetString(s)
+			return nil
+		}
+		if guidType.ConvertibleTo(v.Type()) {
+			parsed, err := uuid.Parse(s)
+			if err != nil {
+				return fmt.Errorf("parsing %s: %+v", edmType, err)
+			}
+			v.Set(reflect.ValueOf([16]byte(parsed)).Convert(v.Type()))
+			return nil
+		}
+		return fmt.Errorf("cannot assign %s to %s", edmType, v.Type())
+
+	case EdmInt32, EdmInt64:
+		var i int64
+		switch value := raw.(type) {
+		case float64:
+			if value != math.Trunc(value) {
+				return fmt.Errorf("expected an integer for %s but got %v", edmType, value)
+			}
+			i = int64(value)
+		case int64:
+			i = value
+		case uint64:
+			if value > math.MaxInt64 {
+				return fmt.Errorf("%d overflows %s", value, edmType)
+			}
+			i = int64(value)
+		case json.Number:
+			parsed, err := value.Int64()
+			if err != nil {
+				return fmt.Errorf("parsing %s: %+v", edmType, err)
+			}
+			i = parsed
+		case string:
+			// Int64's are returned as strings since they can exceed the precision of a JSON number
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parsing %s: %+v", edmType, err)
+			}
+			i = parsed
+		default:
+			return fmt.Errorf("expected a number for %s but got %T", edmType, raw)
+		}
+		switch {
+		case v.CanInt():
+			if v.OverflowInt(i) {
+				return fmt.Errorf("%d overflows %s", i, v.Type())
+			}
+			v.SetInt(i)
+		case v.CanUint():
+			if i < 0 || v.OverflowUint(uint64(i)) {
+				return fmt.Errorf("%d overflows %s", i, v.Type())
+			}
+			v.SetUint(uint64(i))
+		case v.CanFloat():
+			v.SetFloat(float64(i))
+		default:
+			return fmt.Errorf("cannot assign %s to %s", edmType, v.Type())
+		}
+		return nil
+
+	case EdmString:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("expected a string for %s but got %T", edmType, raw)
+		}
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("cannot assign %s to %s", edmType, v.Type())
+		}
+		v.SetString(s)
+		return nil
+	}
+
+	return fmt.Errorf("unsupported EDM Type %q", string(edmType))
+}
@@ -0,0 +1,259 @@
+package entities
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type marshalTestEntity struct {
+	PartitionKey string
+	RowKey       string
+	ETag         string    `table:"odata.etag"`
+	Timestamp    time.Time `table:",omitempty"`
+
+	Name      string    `table:"name"`
+	Enabled   bool      `table:"enabled"`
+	Count     int32     `table:"count"`
+	Total     int64     `table:"total"`
+	Small     int       `table:"small,edm=Int32"`
+	Ratio     float64   `table:"ratio"`
+	CreatedAt time.Time `table:"createdAt"`
+	Data      []byte    `table:"data"`
+	Id        uuid.UUID `table:"id"`
+	OtherId   string    `table:"otherId,edm=Guid"`
+	Optional  *string   `table:"optional"`
+	Ignored   string    `table:"-"`
+}
+
+func TestMarshalMinimalMetaData(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	input := marshalTestEntity{
+		PartitionKey: "partition1",
+		RowKey:       "row1",
+		ETag:         "W/\"datetime'2024-01-02T03%3A04%3A05.6Z'\"",
+		Name:         "hello",
+		Enabled:      true,
+		Count:        42,
+		Total:        math.MaxInt64,
+		Small:        7,
+		Ratio:        1,
+		CreatedAt:    createdAt,
+		Data:         []byte("binary"),
+		Id:           uuid.MustParse("4a6e9c3e-4b0f-4d6c-8d1a-0f0e6c2a9b1d"),
+		OtherId:      "5b7f0d4f-5c1a-4e7d-9e2b-1a1f7d3b0c2e",
+		Ignored:      "ignored",
+	}
+	actual, err := Marshal(input, MinimalMetaData)
+	if err != nil {
+		t.Fatalf("marshalling: %+v", err)
+	}
+
+	expected := map[string]interface{}{
+		"PartitionKey":         "partition1",
+		"RowKey":               "row1",
+		"name":                 "hello",
+		"enabled":              true,
+		"count":                int64(42),
+		"total":                "9223372036854775807",
+		"total@odata.type":     "Edm.Int64",
+		"small":                int64(7),
+		"ratio":                float64(1),
+		"ratio@odata.type":     "Edm.Double",
+		"createdAt":            "2024-01-02T03:04:05.6000000Z",
+		"createdAt@odata.type": "Edm.DateTime",
+		"data":                 "YmluYXJ5",
+		"data@odata.type":      "Edm.Binary",
+		"id":                   "4a6e9c3e-4b0f-4d6c-8d1a-0f0e6c2a9b1d",
+		"id@odata.type":        "Edm.Guid",
+		"otherId":              "5b7f0d4f-5c1a-4e7d-9e2b-1a1f7d3b0c2e",
+		"otherId@odata.type":   "Edm.Guid",
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d properties but got %d: %+v", len(expected), len(actual), actual)
+	}
+	for k, v := range expected {
+		if actual[k] != v {
+			t.Fatalf("expected %q to be %v (%T) but got %v (%T)", k, v, v, actual[k], actual[k])
+		}
+	}
+}
+
+func TestMarshalFullMetaData(t *testing.T) {
+	input := struct {
+		Name  string `table:"name"`
+		Count int32  `table:"count"`
+	}{
+		Name:  "hello",
+		Count: 1,
+	}
+	actual, err := Marshal(&input, FullMetaData)
+	if err != nil {
+		t.Fatalf("marshalling: %+v", err)
+	}
+	if actual["name@odata.type"] != "Edm.String" {
+		t.Fatalf("expected `name@odata.type` to be `Edm.String` but got %v", actual["name@odata.type"])
+	}
+	if actual["count@odata.type"] != "Edm.Int32" {
+		t.Fatalf("expected `count@odata.type` to be `Edm.Int32` but got %v", actual["count@odata.type"])
+	}
+}
+
+func TestMarshalInt32Overflow(t *testing.T) {
+	input := struct {
+		Count int64 `table:"count,edm=Int32"`
+	}{
+		Count: math.MaxInt32 + 1,
+	}
+	if _, err := Marshal(input, MinimalMetaData); err == nil {
+		t.Fatalf("expected an error but didn't get one")
+	}
+}
+
+func TestUnmarshalMinimalMetaData(t *testing.T) {
+	entity := map[string]interface{}{
+		"odata.metadata":       "https://example1.table.core.windows.net/$metadata#table1/@Element",
+		"odata.etag":           "W/\"datetime'2024-01-02T03%3A04%3A05.6Z'\"",
+		"PartitionKey":         "partition1",
+		"RowKey":               "row1",
+		"Timestamp@odata.type": "Edm.DateTime",
+		"Timestamp":            "2024-01-02T03:04:05.6000000Z",
+		"name":                 "hello",
+		"enabled":              true,
+		"count":                float64(42),
+		"total@odata.type":     "Edm.Int64",
+		"total":                "9223372036854775807",
+		"small":                float64(7),
+		"ratio":                float64(1.5),
+		"createdAt@odata.type": "Edm.DateTime",
+		"createdAt":            "2024-01-02T03:04:05.6000000Z",
+		"data@odata.type":      "Edm.Binary",
+		"data":                 "YmluYXJ5",
+		"id@odata.type":        "Edm.Guid",
+		"id":                   "4a6e9c3e-4b0f-4d6c-8d1a-0f0e6c2a9b1d",
+		"otherId@odata.type":   "Edm.Guid",
+		"otherId":              "5b7f0d4f-5c1a-4e7d-9e2b-1a1f7d3b0c2e",
+		"optional":             "present",
+	}
+	var actual marshalTestEntity
+	if err := Unmarshal(entity, &actual); err != nil {
+		t.Fatalf("unmarshalling: %+v", err)
+	}
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	if actual.PartitionKey != "partition1" || actual.RowKey != "row1" {
+		t.Fatalf("expected the keys to be `partition1`/`row1` but got %q/%q", actual.PartitionKey, actual.RowKey)
+	}
+	if actual.ETag != entity["odata.etag"] {
+		t.Fatalf("expected ETag to be %q but got %q", entity["odata.etag"], actual.ETag)
+	}
+	if !actual.Timestamp.Equal(createdAt) {
+		t.Fatalf("expected Timestamp to be %s but got %s", createdAt, actual.Timestamp)
+	}
+	if actual.Name != "hello" || !actual.Enabled || actual.Count != 42 || actual.Small != 7 || actual.Ratio != 1.5 {
+		t.Fatalf("unexpected values: %+v", actual)
+	}
+	if actual.Total != math.MaxInt64 {
+		t.Fatalf("expected Total to be %d but got %d", int64(math.MaxInt64), actual.Total)
+	}
+	if !actual.CreatedAt.Equal(createdAt) {
+		t.Fatalf("expected CreatedAt to be %s but got %s", createdAt, actual.CreatedAt)
+	}
+	if !bytes.Equal(actual.Data, []byte("binary")) {
+		t.Fatalf("expected Data to be `binary` but got %q", string(actual.Data))
+	}
+	if actual.Id.String() != "4a6e9c3e-4b0f-4d6c-8d1a-0f0e6c2a9b1d" {
+		t.Fatalf("expected Id to be `4a6e9c3e-4b0f-4d6c-8d1a-0f0e6c2a9b1d` but got %q", actual.Id.String())
+	}
+	if actual.OtherId != "5b7f0d4f-5c1a-4e7d-9e2b-1a1f7d3b0c2e" {
+		t.Fatalf("expected OtherId to be `5b7f0d4f-5c1a-4e7d-9e2b-1a1f7d3b0c2e` but got %q", actual.OtherId)
+	}
+	if actual.Optional == nil || *actual.Optional != "present" {
+		t.Fatalf("expected Optional to be `present` but got %v", actual.Optional)
+	}
+}
+
+func TestUnmarshalNoMetaData(t *testing.T) {
+	entity := map[string]interface{}{
+		"PartitionKey": "partition1",
+		"RowKey":       "row1",
+		"total":        "1234567890123",
+		"ratio":        "NaN",
+		"createdAt":    "2024-01-02T03:04:05Z",
+		"data":         "YmluYXJ5",
+		"id":           "4a6e9c3e-4b0f-4d6c-8d1a-0f0e6c2a9b1d",
+	}
+	var actual marshalTestEntity
+	if err := Unmarshal(entity, &actual); err != nil {
+		t.Fatalf("unmarshalling: %+v", err)
+	}
+	if actual.Total != 1234567890123 {
+		t.Fatalf("expected Total to be 1234567890123 but got %d", actual.Total)
+	}
+	if !math.IsNaN(actual.Ratio) {
+		t.Fatalf("expected Ratio to be NaN but got %v", actual.Ratio)
+	}
+	if !actual.CreatedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("expected CreatedAt to be 2024-01-02T03:04:05Z but got %s", actual.CreatedAt)
+	}
+	if !bytes.Equal(actual.Data, []byte("binary")) {
+		t.Fatalf("expected Data to be `binary` but got %q", string(actual.Data))
+	}
+}
+
+func TestUnmarshalMismatchedType(t *testing.T) {
+	entity := map[string]interface{}{
+		"name@odata.type": "Edm.Int64",
+		"name":            "12",
+	}
+	var actual marshalTestEntity
+	if err := Unmarshal(entity, &actual); err == nil {
+		t.Fatalf("expected an error but didn't get one")
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	input := marshalTestEntity{
+		PartitionKey: "partition1",
+		RowKey:       "row1",
+		Total:        -5,
+		Ratio:        math.Inf(-1),
+		CreatedAt:    time.Date(2024, 1, 2, 3, 4, 5, 123456700, time.UTC),
+		Data:         []byte{0x00, 0xff},
+		Id:           uuid.New(),
+	}
+	marshalled, err := Marshal(input, MinimalMetaData)
+	if err != nil {
+		t.Fatalf("marshalling: %+v", err)
+	}
+
+	var actual marshalTestEntity
+	if err := Unmarshal(marshalled, &actual); err != nil {
+		t.Fatalf("unmarshalling: %+v", err)
+	}
+	if actual.Total != input.Total || !math.IsInf(actual.Ratio, -1) || !actual.CreatedAt.Equal(input.CreatedAt) || actual.Id != input.Id {
+		t.Fatalf("expected %+v but got %+v", input, actual)
+	}
+	if !bytes.Equal(actual.Data, input.Data) {
+		t.Fatalf("expected Data to be %v but got %v", input.Data, actual.Data)
+	}
+}
+
+func TestMarshalDateTimePrecision(t *testing.T) {
+	input := struct {
+		CreatedAt time.Time
+	}{
+		// Edm.DateTime has a precision of 100 nanoseconds, so only 7 fractional digits are sent
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC),
+	}
+	actual, err := Marshal(input, MinimalMetaData)
+	if err != nil {
+		t.Fatalf("marshalling: %+v", err)
+	}
+	if actual["CreatedAt"] != "2024-01-02T03:04:05.1234567Z" {
+		t.Fatalf("expected CreatedAt to be %q but got %v", "2024-01-02T03:04:05.1234567Z", actual["CreatedAt"])
+	}
+}
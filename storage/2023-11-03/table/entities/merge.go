@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-azure-helpers/lang/response"
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+type MergeEntityInput struct {
+	// The properties which should be merged into the existing Entity, by default all values are strings
+	// To explicitly type a property, specify the appropriate OData data type by setting
+	// the m:type attribute within the property definition
+	Entity map[string]interface{}
+
+	// The ETag of the Entity which should be merged into, as returned from Get or Query.
+	// The merge fails with ErrPreconditionFailed if the Entity has since been modified.
+	// Specify `*` to unconditionally merge into the Entity.
+	IfMatch string
+
+	// When inserting an entity into a table, you must specify values for the PartitionKey and RowKey system properties.
+	// Together, these properties form the primary key and must be unique within the table.
+	// Both the PartitionKey and RowKey values must be string values; each key value may be up to 64 KB in size.
+	// If you are using an integer value for the key value, you should convert the integer to a fixed-width string,
+	// because they are canonically sorted. For example, you should convert the value 1 to 0000001 to ensure proper sorting.
+	RowKey       string
+	PartitionKey string
+}
+
+type MergeEntityResponse struct {
+	HttpResponse *http.Response
+
+	// The new ETag of the Entity
+	ETag string
+}
+
+// Merge updates an existing entity in a table by merging in the specified properties, providing the ETag
+// specified in `IfMatch` matches the current ETag of the entity. Unlike InsertOrMerge this fails if the entity doesn't exist.
+func (c Client) Merge(ctx context.Context, tableName string, input MergeEntityInput) (result MergeEntityResponse, err error) {
+	if tableName == "" {
+		return result, fmt.Errorf("`tableName` cannot be an empty string")
+	}
+
+	if input.PartitionKey == "" {
+		return result, fmt.Errorf("`input.PartitionKey` cannot be an empty string")
+	}
+
+	if input.RowKey == "" {
+		return result, fmt.Errorf("`input.RowKey` cannot be an empty string")
+	}
+
+	if input.IfMatch == "" {
+		return result, fmt.Errorf("`input.IfMatch` cannot be an empty string")
+	}
+
+	opts := client.RequestOptions{
+		ContentType: "application/json",
+		ExpectedStatusCodes: []int{
+			http.StatusNoContent,
+		},
+		HttpMethod: "MERGE",
+		OptionsObject: mergeOptions{
+			ifMatch: input.IfMatch,
+		},
+		Path: fmt.Sprintf("/%s(PartitionKey='%s', RowKey='%s')", tableName, input.PartitionKey, input.RowKey),
+	}
+
+	req, err := c.Client.NewRequest(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("building request: %+v", err)
+		return
+	}
+
+	if input.Entity == nil {
+		input.Entity = map[string]interface{}{}
+	}
+	input.Entity["PartitionKey"] = input.PartitionKey
+	input.Entity["RowKey"] = input.RowKey
+
+	err = req.Marshal(&input.Entity)
+	if err != nil {
+		return result, fmt.Errorf("marshalling request: %+v", err)
+	}
+
+	var resp *client.Response
+	resp, err = c.execute(ctx, "Merge", req)
+	if resp != nil && resp.Response != nil {
+		result.HttpResponse = resp.Response
+
+		if err == nil {
+			result.ETag = resp.Header.Get("ETag")
+		}
+	}
+	if err != nil {
+		if response.WasStatusCode(result.HttpResponse, http.StatusPreconditionFailed) {
+			err = fmt.Errorf("executing request: %w: %+v", ErrPreconditionFailed, err)
+			return
+		}
+		err = fmt.Errorf("executing request: %+v", err)
+		return
+	}
+
+	return
+}
+
+type mergeOptions struct {
+	ifMatch string
+}
+
+func (m mergeOptions) ToHeaders() *client.Headers {
+	headers := &client.Headers{}
+	headers.Append("Accept", "application/json")
+	headers.Append("If-Match", m.ifMatch)
+	return headers
+}
+
+func (m mergeOptions) ToOData() *odata.Query {
+	return nil
+}
+
+func (m mergeOptions) ToQuery() *client.QueryParams {
+	return nil
+}
@@ -7,3 +7,12 @@ var (
 	MinimalMetaData MetaDataLevel = "minimalmetadata"
 	FullMetaData    MetaDataLevel = "fullmetadata"
 )
+
+// ETagFromEntity returns the ETag contained within the `odata.etag` property of the specified Entity
+// which is returned when the MetaDataLevel is MinimalMetaData or FullMetaData - or an empty string if it's not present.
+func ETagFromEntity(entity map[string]interface{}) string {
+	if v, ok := entity["odata.etag"].(string); ok {
+		return v
+	}
+	return ""
+}
@@ -12,7 +12,7 @@ import (
 )
 
 type QueryEntitiesInput struct {
-	// An optional OData filter
+	// An optional OData filter, which can be built using the `tablefilter` package
 	Filter *string
 
 	// An optional comma-separated
@@ -37,11 +37,17 @@ type QueryEntitiesInput struct {
 type QueryEntitiesResponse struct {
 	HttpResponse *http.Response
 
-	NextPartitionKey string
-	NextRowKey       string
+	// The Next Partition Key and Next Row Key are returned when there are further results
+	// available, these can be passed into the next Query to retrieve these - see QueryPager.
+	NextPartitionKey string `json:"-"`
+	NextRowKey       string `json:"-"`
 
 	MetaData string                   `json:"odata.metadata,omitempty"`
 	Entities []map[string]interface{} `json:"value"`
+
+	// ETags contains the ETag for each of the Entities, at the same index as the Entity within `Entities`.
+	// ETags are only returned when the MetaDataLevel is MinimalMetaData or FullMetaData, otherwise these are empty.
+	ETags []string `json:"-"`
 }
 
 // Query queries entities in a table and includes the $filter and $select options.
@@ -52,11 +58,11 @@ func (c Client) Query(ctx context.Context, tableName string, input QueryEntities
 
 	additionalParameters := make([]string, 0)
 	if input.PartitionKey != "" {
-		additionalParameters = append(additionalParameters, "PartitionKey='%s'", input.PartitionKey)
+		additionalParameters = append(additionalParameters, fmt.Sprintf("PartitionKey='%s'", input.PartitionKey))
 	}
 
 	if input.RowKey != "" {
-		additionalParameters = append(additionalParameters, "RowKey='%s'", input.RowKey)
+		additionalParameters = append(additionalParameters, fmt.Sprintf("RowKey='%s'", input.RowKey))
 	}
 
 	path := fmt.Sprintf("/%s", tableName)
@@ -83,16 +89,24 @@ func (c Client) Query(ctx context.Context, tableName string, input QueryEntities
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Query", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
 		if err == nil {
+			result.NextPartitionKey = resp.Header.Get("x-ms-continuation-NextPartitionKey")
+			result.NextRowKey = resp.Header.Get("x-ms-continuation-NextRowKey")
+
 			err = resp.Unmarshal(&result)
 			if err != nil {
 				err = fmt.Errorf("unmarshalling response: %+v", err)
 				return
 			}
+
+			result.ETags = make([]string, 0, len(result.Entities))
+			for _, entity := range result.Entities {
+				result.ETags = append(result.ETags, ETagFromEntity(entity))
+			}
 		}
 	}
 	if err != nil {
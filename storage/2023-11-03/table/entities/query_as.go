@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type QueryEntitiesAsResponse[T any] struct {
+	HttpResponse *http.Response
+
+	NextPartitionKey string
+	NextRowKey       string
+
+	Entities []T
+
+	// ETags contains the ETag for each of the Entities, at the same index as the Entity within `Entities`.
+	// ETags are only returned when the MetaDataLevel is MinimalMetaData or FullMetaData, otherwise these are empty.
+	ETags []string
+}
+
+// QueryAs queries entities in a table and unmarshals each of them into `T` - see Unmarshal for more information.
+func QueryAs[T any](ctx context.Context, c Client, tableName string, input QueryEntitiesInput) (result QueryEntitiesAsResponse[T], err error) {
+	resp, err := c.Query(ctx, tableName, input)
+	result.HttpResponse = resp.HttpResponse
+	if err != nil {
+		return
+	}
+
+	return unmarshalQueryResponse[T](resp)
+}
+
+func unmarshalQueryResponse[T any](resp QueryEntitiesResponse) (result QueryEntitiesAsResponse[T], err error) {
+	result.HttpResponse = resp.HttpResponse
+	result.NextPartitionKey = resp.NextPartitionKey
+	result.NextRowKey = resp.NextRowKey
+	result.ETags = resp.ETags
+	result.Entities = make([]T, 0, len(resp.Entities))
+	for i, entity := range resp.Entities {
+		var item T
+		if err = Unmarshal(entity, &item); err != nil {
+			err = fmt.Errorf("unmarshalling entity %d: %+v", i, err)
+			return
+		}
+		result.Entities = append(result.Entities, item)
+	}
+
+	return
+}
@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+)
+
+// QueryPager iterates over the pages of Entities returned from Query, following the continuation
+// tokens (NextPartitionKey/NextRowKey) returned from each page until all Entities have been retrieved.
+type QueryPager struct {
+	client    Client
+	tableName string
+	input     QueryEntitiesInput
+
+	limit    int
+	returned int
+	done     bool
+}
+
+// NewQueryPager returns a QueryPager for the Entities matching `input` within the table `tableName`.
+//
+// When specified, `input.Top` is used as the (maximum) page size. `limit` specifies the maximum number
+// of Entities which should be returned across all pages, where 0 means no limit.
+func NewQueryPager(client Client, tableName string, input QueryEntitiesInput, limit int) *QueryPager {
+	return &QueryPager{
+		client:    client,
+		tableName: tableName,
+		input:     input,
+		limit:     limit,
+	}
+}
+
+// More returns whether there are further pages of Entities to retrieve.
+func (p *QueryPager) More() bool {
+	return !p.done
+}
+
+// NextPage retrieves the next page of Entities.
+func (p *QueryPager) NextPage(ctx context.Context) (result QueryEntitiesResponse, err error) {
+	if p.done {
+		return result, fmt.Errorf("no more pages are available")
+	}
+
+	input := p.input
+	if p.limit > 0 {
+		remaining := p.limit - p.returned
+		if input.Top == nil || *input.Top > remaining {
+			input.Top = &remaining
+		}
+	}
+
+	result, err = p.client.Query(ctx, p.tableName, input)
+	if err != nil {
+		return
+	}
+
+	if p.limit > 0 && p.returned+len(result.Entities) > p.limit {
+		remaining := p.limit - p.returned
+		result.Entities = result.Entities[:remaining]
+		result.ETags = result.ETags[:remaining]
+	}
+	p.returned += len(result.Entities)
+
+	if result.NextPartitionKey == "" && result.NextRowKey == "" {
+		p.done = true
+	}
+	if p.limit > 0 && p.returned >= p.limit {
+		p.done = true
+	}
+
+	nextPartitionKey := result.NextPartitionKey
+	nextRowKey := result.NextRowKey
+	p.input.NextPartitionKey = &nextPartitionKey
+	p.input.NextRowKey = &nextRowKey
+	if nextRowKey == "" {
+		p.input.NextRowKey = nil
+	}
+
+	return
+}
+
+// QueryAsPager iterates over the pages of Entities returned from Query, unmarshalling each Entity into `T`.
+type QueryAsPager[T any] struct {
+	pager *QueryPager
+}
+
+// NewQueryAsPager returns a QueryAsPager for the Entities matching `input` within the table `tableName`,
+// see NewQueryPager for more information.
+func NewQueryAsPager[T any](client Client, tableName string, input QueryEntitiesInput, limit int) *QueryAsPager[T] {
+	return &QueryAsPager[T]{
+		pager: NewQueryPager(client, tableName, input, limit),
+	}
+}
+
+// More returns whether there are further pages of Entities to retrieve.
+func (p *QueryAsPager[T]) More() bool {
+	return p.pager.More()
+}
+
+// NextPage retrieves the next page of Entities, unmarshalled into `T`.
+func (p *QueryAsPager[T]) NextPage(ctx context.Context) (result QueryEntitiesAsResponse[T], err error) {
+	resp, err := p.pager.NextPage(ctx)
+	result.HttpResponse = resp.HttpResponse
+	if err != nil {
+		return
+	}
+
+	return unmarshalQueryResponse[T](resp)
+}
@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newPagedTableServer returns a server containing `total` entities within the table `table1`
+// which returns at most `pageSize` entities per page (or `$top`, if lower).
+func newPagedTableServer(t *testing.T, total, pageSize int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/table1" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		start := 0
+		if v := r.URL.Query().Get("NextPartitionKey"); v != "" {
+			start, _ = strconv.Atoi(v)
+		}
+		size := pageSize
+		if v := r.URL.Query().Get("$top"); v != "" {
+			top, _ := strconv.Atoi(v)
+			if top < size {
+				size = top
+			}
+		}
+		end := start + size
+		if end > total {
+			end = total
+		}
+
+		entities := make([]map[string]interface{}, 0)
+		for i := start; i < end; i++ {
+			entities = append(entities, map[string]interface{}{
+				"odata.etag":       fmt.Sprintf("etag%d", i),
+				"PartitionKey":     strconv.Itoa(i),
+				"RowKey":           "row",
+				"count@odata.type": "Edm.Int64",
+				"count":            strconv.Itoa(i),
+			})
+		}
+		if end < total {
+			w.Header().Set("x-ms-continuation-NextPartitionKey", strconv.Itoa(end))
+			w.Header().Set("x-ms-continuation-NextRowKey", "row")
+		}
+		w.Header().Set("Content-Type", "application/json;odata=minimalmetadata")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"value": entities,
+		})
+	}))
+}
+
+func TestQueryPagerFollowsContinuationTokens(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := newPagedTableServer(t, 7, 3)
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	pager := NewQueryPager(*client, "table1", QueryEntitiesInput{MetaDataLevel: MinimalMetaData}, 0)
+	pages := 0
+	partitionKeys := make([]string, 0)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			t.Fatalf("retrieving page: %+v", err)
+		}
+		pages++
+		for i, entity := range page.Entities {
+			partitionKeys = append(partitionKeys, entity["PartitionKey"].(string))
+			if expected := fmt.Sprintf("etag%s", entity["PartitionKey"]); page.ETags[i] != expected {
+				t.Fatalf("expected the ETag to be %q but got %q", expected, page.ETags[i])
+			}
+		}
+	}
+	if pages != 3 {
+		t.Fatalf("expected 3 pages but got %d", pages)
+	}
+	if len(partitionKeys) != 7 {
+		t.Fatalf("expected 7 entities but got %d", len(partitionKeys))
+	}
+	for i, v := range partitionKeys {
+		if v != strconv.Itoa(i) {
+			t.Fatalf("expected entity %d to have the Partition Key %d but got %q", i, i, v)
+		}
+	}
+}
+
+func TestQueryPagerHonoursTopAndLimit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := newPagedTableServer(t, 100, 1000)
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	top := 4
+	input := QueryEntitiesInput{
+		MetaDataLevel: MinimalMetaData,
+		Top:           &top,
+	}
+	type countEntity struct {
+		PartitionKey string
+		Count        int64 `table:"count"`
+	}
+	pager := NewQueryAsPager[countEntity](*client, "table1", input, 10)
+	pageSizes := make([]int, 0)
+	total := int64(0)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			t.Fatalf("retrieving page: %+v", err)
+		}
+		pageSizes = append(pageSizes, len(page.Entities))
+		for _, entity := range page.Entities {
+			total += entity.Count
+		}
+	}
+
+	expected := []int{4, 4, 2}
+	if len(pageSizes) != len(expected) {
+		t.Fatalf("expected page sizes %v but got %v", expected, pageSizes)
+	}
+	for i := range expected {
+		if pageSizes[i] != expected[i] {
+			t.Fatalf("expected page sizes %v but got %v", expected, pageSizes)
+		}
+	}
+	// 0 + 1 + ... + 9
+	if total != 45 {
+		t.Fatalf("expected the sum of the counts to be 45 but got %d", total)
+	}
+}
@@ -57,7 +57,7 @@ func ParseEntityID(input, domainSuffix string) (*EntityId, error) {
 		return nil, fmt.Errorf("`input` was empty")
 	}
 
-	account, err := accounts.ParseAccountID(input, domainSuffix)
+	account, err := accounts.ParseAccountIDWithSubDomainType(input, domainSuffix, accounts.TableSubDomainType)
 	if err != nil {
 		return nil, fmt.Errorf("parsing account %q: %+v", input, err)
 	}
@@ -71,7 +71,10 @@ func ParseEntityID(input, domainSuffix string) (*EntityId, error) {
 		return nil, fmt.Errorf("parsing %q as a uri: %+v", input, err)
 	}
 
-	path := strings.TrimPrefix(uri.Path, "/")
+	path, err := account.ResourcePath(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %+v", input, err)
+	}
 	segments := strings.Split(path, "/")
 	if len(segments) != 1 {
 		return nil, fmt.Errorf("expected the path to contain 1 segment but got %d", len(segments))
@@ -79,7 +82,7 @@ func ParseEntityID(input, domainSuffix string) (*EntityId, error) {
 
 	// Tables and Table Entities are similar with table being `table1` and entities
 	// being `table1(PartitionKey='samplepartition',RowKey='samplerow')` so we need to validate this is a table
-	slug := strings.TrimPrefix(uri.Path, "/")
+	slug := path
 	if strings.HasPrefix(slug, "Tables('") && strings.HasSuffix(slug, "')") {
 		// Ensure we do not parse a Table ID in the format: https://foo.table.core.windows.net/Table('foo')
 		return nil, fmt.Errorf("expected the path to be an entity name but got a table name: %q", slug)
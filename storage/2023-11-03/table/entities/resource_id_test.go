@@ -182,3 +182,23 @@ func TestFormatEntityIDInEdgeZone(t *testing.T) {
 		t.Fatalf("expected %q but got %q", expected, actual)
 	}
 }
+
+func TestParseEntityIDPathStyle(t *testing.T) {
+	input := "http://127.0.0.1:10002/devstoreaccount1/table1(PartitionKey='partition1',RowKey='row1')"
+	actual, err := ParseEntityID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountId.AccountName != "devstoreaccount1" {
+		t.Fatalf("expected AccountName to be %q but got %q", "devstoreaccount1", actual.AccountId.AccountName)
+	}
+	if actual.TableName != "table1" {
+		t.Fatalf("expected TableName to be %q but got %q", "table1", actual.TableName)
+	}
+	if actual.PartitionKey != "partition1" || actual.RowKey != "row1" {
+		t.Fatalf("expected PartitionKey/RowKey to be %q/%q but got %q/%q", "partition1", "row1", actual.PartitionKey, actual.RowKey)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
@@ -40,7 +40,7 @@ func (c Client) GetACL(ctx context.Context, tableName string) (result GetACLResp
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "GetACL", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
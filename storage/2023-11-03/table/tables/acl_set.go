@@ -50,7 +50,7 @@ func (c Client) SetACL(ctx context.Context, tableName string, acls []SignedIdent
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "SetACL", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
@@ -37,7 +37,7 @@ func (c Client) Exists(ctx context.Context, tableName string) (result TableExist
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Exists", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 	}
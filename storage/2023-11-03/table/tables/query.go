@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/odata"
@@ -12,12 +13,25 @@ import (
 type GetResponse struct {
 	HttpResponse *http.Response
 
+	// The Next Table Name is returned when there are further results available,
+	// this can be passed into the next Query to retrieve these - see QueryPager.
+	NextTableName string `json:"-"`
+
 	MetaData string          `json:"odata.metadata,omitempty"`
 	Tables   []GetResultItem `json:"value"`
 }
 
 type QueryInput struct {
+	// An optional OData filter, which can be built using the `tablefilter` package
+	Filter *string
+
+	// An optional OData top, specifying the maximum number of Tables to return
+	Top *int
+
 	MetaDataLevel MetaDataLevel
+
+	// The Next Table Name used to load data from a previous point
+	NextTableName *string
 }
 
 // Query returns a list of tables under the specified account.
@@ -29,7 +43,7 @@ func (c Client) Query(ctx context.Context, input QueryInput) (result GetResponse
 		},
 		HttpMethod: http.MethodGet,
 		OptionsObject: queryOptions{
-			metaDataLevel: input.MetaDataLevel,
+			input: input,
 		},
 		Path: "/Tables",
 	}
@@ -41,11 +55,13 @@ func (c Client) Query(ctx context.Context, input QueryInput) (result GetResponse
 	}
 
 	var resp *client.Response
-	resp, err = req.Execute(ctx)
+	resp, err = c.execute(ctx, "Query", req)
 	if resp != nil && resp.Response != nil {
 		result.HttpResponse = resp.Response
 
 		if err == nil {
+			result.NextTableName = resp.Header.Get("x-ms-continuation-NextTableName")
+
 			err = resp.Unmarshal(&result)
 			if err != nil {
 				err = fmt.Errorf("unmarshalling response: %+v", err)
@@ -62,15 +78,15 @@ func (c Client) Query(ctx context.Context, input QueryInput) (result GetResponse
 }
 
 type queryOptions struct {
-	metaDataLevel MetaDataLevel
+	input QueryInput
 }
 
 func (q queryOptions) ToHeaders() *client.Headers {
 	// NOTE: whilst this supports ContinuationTokens and 'Top'
 	// it appears that 'Skip' returns a '501 Not Implemented'
-	// as such, we intentionally don't support those right now
+	// as such, we intentionally don't support Skip right now
 	headers := &client.Headers{}
-	headers.Append("Accept", fmt.Sprintf("application/json;odata=%s", q.metaDataLevel))
+	headers.Append("Accept", fmt.Sprintf("application/json;odata=%s", q.input.MetaDataLevel))
 	return headers
 }
 
@@ -79,5 +95,19 @@ func (q queryOptions) ToOData() *odata.Query {
 }
 
 func (q queryOptions) ToQuery() *client.QueryParams {
-	return nil
+	out := &client.QueryParams{}
+
+	if q.input.Filter != nil {
+		out.Append("$filter", *q.input.Filter)
+	}
+
+	if q.input.Top != nil {
+		out.Append("$top", strconv.Itoa(*q.input.Top))
+	}
+
+	if q.input.NextTableName != nil {
+		out.Append("NextTableName", *q.input.NextTableName)
+	}
+
+	return out
 }
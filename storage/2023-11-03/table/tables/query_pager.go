@@ -0,0 +1,71 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+)
+
+// QueryPager iterates over the pages of Tables returned from Query, following the continuation
+// token (NextTableName) returned from each page until all Tables have been retrieved.
+type QueryPager struct {
+	client Client
+	input  QueryInput
+
+	limit    int
+	returned int
+	done     bool
+}
+
+// NewQueryPager returns a QueryPager for the Tables matching `input`.
+//
+// When specified, `input.Top` is used as the (maximum) page size. `limit` specifies the maximum number
+// of Tables which should be returned across all pages, where 0 means no limit.
+func NewQueryPager(client Client, input QueryInput, limit int) *QueryPager {
+	return &QueryPager{
+		client: client,
+		input:  input,
+		limit:  limit,
+	}
+}
+
+// More returns whether there are further pages of Tables to retrieve.
+func (p *QueryPager) More() bool {
+	return !p.done
+}
+
+// NextPage retrieves the next page of Tables.
+func (p *QueryPager) NextPage(ctx context.Context) (result GetResponse, err error) {
+	if p.done {
+		return result, fmt.Errorf("no more pages are available")
+	}
+
+	input := p.input
+	if p.limit > 0 {
+		remaining := p.limit - p.returned
+		if input.Top == nil || *input.Top > remaining {
+			input.Top = &remaining
+		}
+	}
+
+	result, err = p.client.Query(ctx, input)
+	if err != nil {
+		return
+	}
+
+	if p.limit > 0 && p.returned+len(result.Tables) > p.limit {
+		result.Tables = result.Tables[:p.limit-p.returned]
+	}
+	p.returned += len(result.Tables)
+
+	if result.NextTableName == "" {
+		p.done = true
+	}
+	if p.limit > 0 && p.returned >= p.limit {
+		p.done = true
+	}
+
+	nextTableName := result.NextTableName
+	p.input.NextTableName = &nextTableName
+
+	return
+}
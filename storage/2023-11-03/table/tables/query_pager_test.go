@@ -0,0 +1,140 @@
+package tables
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newPagedTablesServer returns a server containing `total` tables (named `table0`, `table1` etc)
+// which returns at most `pageSize` tables per page (or `$top`, if lower).
+func newPagedTablesServer(t *testing.T, total, pageSize int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Tables" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		start := 0
+		if v := r.URL.Query().Get("NextTableName"); v != "" {
+			start, _ = strconv.Atoi(v[len("table"):])
+		}
+		size := pageSize
+		if v := r.URL.Query().Get("$top"); v != "" {
+			top, _ := strconv.Atoi(v)
+			if top < size {
+				size = top
+			}
+		}
+		end := start + size
+		if end > total {
+			end = total
+		}
+
+		tables := make([]map[string]interface{}, 0)
+		for i := start; i < end; i++ {
+			tables = append(tables, map[string]interface{}{
+				"TableName": fmt.Sprintf("table%d", i),
+			})
+		}
+		if end < total {
+			w.Header().Set("x-ms-continuation-NextTableName", fmt.Sprintf("table%d", end))
+		}
+		w.Header().Set("Content-Type", "application/json;odata=nometadata")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"value": tables,
+		})
+	}))
+}
+
+func TestQueryPagerFollowsContinuationTokens(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := newPagedTablesServer(t, 7, 3)
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	pager := NewQueryPager(*client, QueryInput{MetaDataLevel: NoMetaData}, 0)
+	pages := 0
+	tableNames := make([]string, 0)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			t.Fatalf("retrieving page: %+v", err)
+		}
+		pages++
+		for _, table := range page.Tables {
+			tableNames = append(tableNames, table.TableName)
+		}
+	}
+	if pages != 3 {
+		t.Fatalf("expected 3 pages but got %d", pages)
+	}
+	if len(tableNames) != 7 {
+		t.Fatalf("expected 7 tables but got %d", len(tableNames))
+	}
+	for i, v := range tableNames {
+		if expected := fmt.Sprintf("table%d", i); v != expected {
+			t.Fatalf("expected table %d to be named %q but got %q", i, expected, v)
+		}
+	}
+	if _, err := pager.NextPage(ctx); err == nil {
+		t.Fatalf("expected an error retrieving a page once all pages have been retrieved but didn't get one")
+	}
+}
+
+func TestQueryPagerHonoursTopAndLimit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := newPagedTablesServer(t, 100, 1000)
+	defer server.Close()
+
+	client, err := NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	top := 4
+	input := QueryInput{
+		MetaDataLevel: NoMetaData,
+		Top:           &top,
+	}
+	pager := NewQueryPager(*client, input, 10)
+	pageSizes := make([]int, 0)
+	lastTableName := ""
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			t.Fatalf("retrieving page: %+v", err)
+		}
+		pageSizes = append(pageSizes, len(page.Tables))
+		for _, table := range page.Tables {
+			lastTableName = table.TableName
+		}
+	}
+
+	expected := []int{4, 4, 2}
+	if len(pageSizes) != len(expected) {
+		t.Fatalf("expected page sizes %v but got %v", expected, pageSizes)
+	}
+	for i := range expected {
+		if pageSizes[i] != expected[i] {
+			t.Fatalf("expected page sizes %v but got %v", expected, pageSizes)
+		}
+	}
+	if lastTableName != "table9" {
+		t.Fatalf("expected the last table to be %q but got %q", "table9", lastTableName)
+	}
+}
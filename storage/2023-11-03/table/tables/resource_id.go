@@ -53,7 +53,7 @@ func ParseTableID(input, domainSuffix string) (*TableId, error) {
 		return nil, fmt.Errorf("`input` was empty")
 	}
 
-	account, err := accounts.ParseAccountID(input, domainSuffix)
+	account, err := accounts.ParseAccountIDWithSubDomainType(input, domainSuffix, accounts.TableSubDomainType)
 	if err != nil {
 		return nil, fmt.Errorf("parsing account %q: %+v", input, err)
 	}
@@ -67,7 +67,10 @@ func ParseTableID(input, domainSuffix string) (*TableId, error) {
 		return nil, fmt.Errorf("parsing %q as a uri: %+v", input, err)
 	}
 
-	path := strings.TrimPrefix(uri.Path, "/")
+	path, err := account.ResourcePath(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %+v", input, err)
+	}
 	segments := strings.Split(path, "/")
 	if len(segments) != 1 {
 		return nil, fmt.Errorf("expected the path to contain 1 segment but got %d", len(segments))
@@ -80,7 +83,7 @@ func ParseTableID(input, domainSuffix string) (*TableId, error) {
 	// However, there was a period of time when Table IDs did not use the reserved namespace, so we attempt to parse
 	// both forms for maximum compatibility.
 	var tableName string
-	slug := strings.TrimPrefix(uri.Path, "/")
+	slug := path
 	if strings.HasPrefix(slug, "Tables('") && strings.HasSuffix(slug, "')") {
 		// Ensure both prefix and suffix are present before trimming them out
 		tableName = strings.TrimSuffix(strings.TrimPrefix(slug, "Tables('"), "')")
@@ -188,3 +188,20 @@ func TestFormatTableIDInEdgeZone(t *testing.T) {
 		t.Fatalf("expected %q but got %q", expected, actual)
 	}
 }
+
+func TestParseTableIDPathStyle(t *testing.T) {
+	input := "http://127.0.0.1:10002/devstoreaccount1/Tables('table1')"
+	actual, err := ParseTableID(input, "core.windows.net")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if actual.AccountId.AccountName != "devstoreaccount1" {
+		t.Fatalf("expected AccountName to be %q but got %q", "devstoreaccount1", actual.AccountId.AccountName)
+	}
+	if actual.TableName != "table1" {
+		t.Fatalf("expected TableName to be %q but got %q", "table1", actual.TableName)
+	}
+	if actual.ID() != input {
+		t.Fatalf("expected ID to be %q but got %q", input, actual.ID())
+	}
+}
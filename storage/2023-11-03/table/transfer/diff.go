@@ -0,0 +1,100 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-azure-helpers/lang/response"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/table/entities"
+)
+
+type DiffAction string
+
+var (
+	// DiffActionCreate means that the Entity doesn't exist and would be created.
+	DiffActionCreate DiffAction = "Create"
+
+	// DiffActionUnchanged means that the Entity exists and matches.
+	DiffActionUnchanged DiffAction = "Unchanged"
+
+	// DiffActionUpdate means that the Entity exists but differs, and would be replaced.
+	DiffActionUpdate DiffAction = "Update"
+)
+
+type EntityDiff struct {
+	PartitionKey string
+	RowKey       string
+
+	// The Action which would be performed for this Entity
+	Action DiffAction
+
+	// The names of the properties which have been added, removed or changed (either in value or EDM Type) when
+	// the Action is DiffActionUpdate.
+	ChangedProperties []string
+}
+
+func (i *importer) diff(ctx context.Context, entity map[string]interface{}) (*EntityDiff, error) {
+	out := EntityDiff{
+		PartitionKey: entity["PartitionKey"].(string),
+		RowKey:       entity["RowKey"].(string),
+	}
+
+	getInput := entities.GetEntityInput{
+		PartitionKey:  out.PartitionKey,
+		RowKey:        out.RowKey,
+		MetaDataLevel: entities.FullMetaData,
+	}
+	existing, err := i.client.Get(ctx, i.tableName, getInput)
+	if err != nil {
+		if response.WasNotFound(existing.HttpResponse) {
+			out.Action = DiffActionCreate
+			return &out, nil
+		}
+		return nil, fmt.Errorf("retrieving Entity (Partition Key %q / Row Key %q): %+v", out.PartitionKey, out.RowKey, err)
+	}
+
+	out.ChangedProperties = changedProperties(withoutSystemProperties(existing.Entity), withoutSystemProperties(entity))
+	out.Action = DiffActionUnchanged
+	if len(out.ChangedProperties) > 0 {
+		out.Action = DiffActionUpdate
+	}
+	return &out, nil
+}
+
+// changedProperties returns the (sorted) names of the properties which differ between `existing` and `desired`,
+// where a change to the `@odata.type` annotation of a property is reported as a change to that property.
+func changedProperties(existing, desired map[string]interface{}) []string {
+	changed := make(map[string]struct{})
+	compare := func(a, b map[string]interface{}) {
+		for k, v := range a {
+			other, ok := b[k]
+			if ok && reflect.DeepEqual(v, other) {
+				continue
+			}
+			changed[strings.TrimSuffix(k, "@odata.type")] = struct{}{}
+		}
+	}
+	compare(existing, desired)
+	compare(desired, existing)
+
+	out := make([]string, 0, len(changed))
+	for k := range changed {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// withoutSystemProperties returns a copy of `entity` without the `odata.*` properties and the system properties
+// (PartitionKey, RowKey and Timestamp) which are set by the Table Service.
+func withoutSystemProperties(entity map[string]interface{}) map[string]interface{} {
+	out := withoutODataProperties(entity)
+	for _, property := range []string{"PartitionKey", "RowKey", "Timestamp"} {
+		delete(out, property)
+		delete(out, property+"@odata.type")
+	}
+	return out
+}
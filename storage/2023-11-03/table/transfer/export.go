@@ -0,0 +1,74 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/table/entities"
+)
+
+type ExportInput struct {
+	// An optional OData filter, limiting the Entities which are exported
+	Filter *string
+
+	// The number of Entities to retrieve per page, defaults to 1000 (the maximum supported by the Table Service)
+	PageSize *int
+}
+
+type ExportResult struct {
+	// The number of Entities which were exported
+	Entities int64
+}
+
+// Export streams every Entity within the table `tableName` to `w` as JSON Lines, one Entity per line.
+//
+// Entities are retrieved with FullMetaData so that the `@odata.type` annotation for each property is
+// included, meaning the EDM Type of each property is retained when the Entities are imported using Import.
+func Export(ctx context.Context, client entities.Client, tableName string, w io.Writer, input ExportInput) (*ExportResult, error) {
+	pageSize := 1000
+	if input.PageSize != nil {
+		pageSize = *input.PageSize
+	}
+	queryInput := entities.QueryEntitiesInput{
+		Filter:        input.Filter,
+		MetaDataLevel: entities.FullMetaData,
+		Top:           &pageSize,
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	result := ExportResult{}
+	pager := entities.NewQueryPager(client, tableName, queryInput, 0)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return &result, fmt.Errorf("retrieving Entities from table %q: %+v", tableName, err)
+		}
+
+		for _, entity := range page.Entities {
+			if err := encoder.Encode(withoutODataProperties(entity)); err != nil {
+				return &result, fmt.Errorf("writing Entity: %+v", err)
+			}
+			result.Entities++
+		}
+	}
+
+	return &result, nil
+}
+
+// withoutODataProperties returns a copy of `entity` without the read-only `odata.*` properties (such as
+// `odata.etag` and `odata.editLink`) which are specific to the table the Entity was retrieved from.
+func withoutODataProperties(entity map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(entity))
+	for k, v := range entity {
+		if strings.HasPrefix(k, "odata.") {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
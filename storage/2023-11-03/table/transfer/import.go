@@ -0,0 +1,284 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/table/entities"
+)
+
+type ImportInput struct {
+	// Whether Entities should be written in Batches (Entity Group Transactions) of up to 100 Entities within
+	// the same Partition, rather than individually using InsertOrReplace. Defaults to false.
+	UseBatches bool
+
+	// The number of Partitions which should be imported concurrently. Defaults to 1.
+	Parallelism int
+
+	// The number of lines at the start of the input which should be skipped, as previously reported
+	// to Checkpoint, allowing an interrupted Import to be resumed.
+	ResumeFromLine int64
+
+	// An optional function which is called as lines are imported, with the number of lines (from the start of the
+	// input) which have been imported. This value can be persisted and provided as ResumeFromLine to resume an Import.
+	Checkpoint func(line int64) error
+
+	// When DryRun is true no changes are made to the table, instead each Entity is compared against the
+	// existing Entity and the result is reported to Diff.
+	DryRun bool
+
+	// An optional function which is called with the result of comparing each Entity when DryRun is true.
+	// This may be called concurrently when Parallelism is greater than 1.
+	Diff func(diff EntityDiff)
+}
+
+type ImportResult struct {
+	// The number of Entities which were imported (or compared, when DryRun is true)
+	Entities int64
+
+	// The number of lines (from the start of the input) which have been imported, which can be used
+	// as ResumeFromLine to resume the Import should an error occur.
+	Lines int64
+}
+
+// maxEntitiesPerRun is the maximum number of consecutive Entities within the same Partition which are imported
+// together, which matches the maximum size of a Batch
+const maxEntitiesPerRun = entities.MaxBatchOperations
+
+// Import replays the Entities within `r` (in the JSON Lines format written by Export) into the table `tableName`,
+// replacing any existing Entities with the same PartitionKey and RowKey.
+//
+// Partitions are imported concurrently (up to `input.Parallelism`), with the Entities within each Partition
+// imported in the order they appear within the input.
+func Import(ctx context.Context, client entities.Client, tableName string, r io.Reader, input ImportInput) (*ImportResult, error) {
+	parallelism := input.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	importCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	imp := &importer{
+		client:    client,
+		tableName: tableName,
+		input:     input,
+		cancel:    cancel,
+		completed: map[int64]importRun{},
+		watermark: input.ResumeFromLine,
+	}
+
+	workers := make([]chan importRun, parallelism)
+	wg := sync.WaitGroup{}
+	for i := range workers {
+		workers[i] = make(chan importRun, 1)
+		wg.Add(1)
+		go func(runs chan importRun) {
+			defer wg.Done()
+			for run := range runs {
+				if importCtx.Err() != nil {
+					continue
+				}
+				if err := imp.importRun(importCtx, run); err != nil {
+					imp.fail(fmt.Errorf("importing lines %d to %d: %+v", run.startLine+1, run.endLine, err))
+					continue
+				}
+				if err := imp.complete(run); err != nil {
+					imp.fail(err)
+				}
+			}
+		}(workers[i])
+	}
+
+	dispatch := func(run importRun) {
+		// Entities within the same Partition are always routed to the same worker to retain their ordering
+		hash := fnv.New32a()
+		hash.Write([]byte(run.partitionKey))
+		worker := workers[hash.Sum32()%uint32(len(workers))]
+		select {
+		case worker <- run:
+		case <-importCtx.Done():
+		}
+	}
+
+	readErr := readRuns(importCtx, r, input.ResumeFromLine, dispatch)
+	for _, worker := range workers {
+		close(worker)
+	}
+	wg.Wait()
+
+	result := &ImportResult{
+		Entities: imp.entities,
+		Lines:    imp.watermark,
+	}
+	if imp.err != nil {
+		return result, imp.err
+	}
+	if readErr != nil {
+		return result, readErr
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+type importRun struct {
+	partitionKey string
+	entities     []map[string]interface{}
+
+	// startLine and endLine are the (zero-indexed, exclusive) range of lines within the input for this run
+	startLine int64
+	endLine   int64
+}
+
+// readRuns reads the Entities from `r`, grouping consecutive Entities within the same Partition into runs
+func readRuns(ctx context.Context, r io.Reader, resumeFromLine int64, dispatch func(run importRun)) error {
+	scanner := bufio.NewScanner(r)
+	// an Entity can be up to 1MiB in size, which can be larger once encoded as JSON
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var current *importRun
+	line := int64(0)
+	for scanner.Scan() {
+		line++
+		if line <= resumeFromLine {
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var entity map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entity); err != nil {
+			return fmt.Errorf("parsing line %d: %+v", line, err)
+		}
+		partitionKey, _ := entity["PartitionKey"].(string)
+		rowKey, _ := entity["RowKey"].(string)
+		if partitionKey == "" || rowKey == "" {
+			return fmt.Errorf("parsing line %d: expected a PartitionKey and RowKey to be specified", line)
+		}
+
+		if current != nil && (current.partitionKey != partitionKey || len(current.entities) >= maxEntitiesPerRun) {
+			dispatch(*current)
+			current = nil
+		}
+		if current == nil {
+			current = &importRun{
+				partitionKey: partitionKey,
+				startLine:    line - 1,
+			}
+		}
+		current.entities = append(current.entities, entity)
+		current.endLine = line
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading line %d: %+v", line+1, err)
+	}
+
+	if current != nil {
+		dispatch(*current)
+	}
+	return nil
+}
+
+type importer struct {
+	client    entities.Client
+	tableName string
+	input     ImportInput
+	cancel    context.CancelFunc
+
+	mu        sync.Mutex
+	err       error
+	entities  int64
+	completed map[int64]importRun
+	watermark int64
+}
+
+func (i *importer) importRun(ctx context.Context, run importRun) error {
+	if i.input.DryRun {
+		for _, entity := range run.entities {
+			diff, err := i.diff(ctx, entity)
+			if err != nil {
+				return err
+			}
+			if i.input.Diff != nil {
+				i.input.Diff(*diff)
+			}
+		}
+		return nil
+	}
+
+	if i.input.UseBatches {
+		operations := make([]entities.BatchOperation, 0, len(run.entities))
+		for _, entity := range run.entities {
+			operations = append(operations, entities.BatchOperation{
+				Type:         entities.BatchOperationInsertOrReplace,
+				Entity:       withoutSystemProperties(entity),
+				PartitionKey: entity["PartitionKey"].(string),
+				RowKey:       entity["RowKey"].(string),
+			})
+		}
+		batchInput := entities.BatchInput{
+			Operations: operations,
+		}
+		if _, err := i.client.Batch(ctx, i.tableName, batchInput); err != nil {
+			return fmt.Errorf("performing batch: %+v", err)
+		}
+		return nil
+	}
+
+	for _, entity := range run.entities {
+		insertInput := entities.InsertOrReplaceEntityInput{
+			Entity:       withoutSystemProperties(entity),
+			PartitionKey: entity["PartitionKey"].(string),
+			RowKey:       entity["RowKey"].(string),
+		}
+		if _, err := i.client.InsertOrReplace(ctx, i.tableName, insertInput); err != nil {
+			return fmt.Errorf("inserting/replacing Entity (Partition Key %q / Row Key %q): %+v", insertInput.PartitionKey, insertInput.RowKey, err)
+		}
+	}
+	return nil
+}
+
+// complete marks `run` as imported, advancing the checkpoint once all of the preceding lines have been imported
+func (i *importer) complete(run importRun) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.entities += int64(len(run.entities))
+	i.completed[run.startLine] = run
+
+	advanced := false
+	for {
+		next, ok := i.completed[i.watermark]
+		if !ok {
+			break
+		}
+		delete(i.completed, i.watermark)
+		i.watermark = next.endLine
+		advanced = true
+	}
+
+	if advanced && i.input.Checkpoint != nil {
+		if err := i.input.Checkpoint(i.watermark); err != nil {
+			return fmt.Errorf("checkpointing at line %d: %+v", i.watermark, err)
+		}
+	}
+	return nil
+}
+
+func (i *importer) fail(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.err == nil {
+		i.err = err
+		i.cancel()
+	}
+}
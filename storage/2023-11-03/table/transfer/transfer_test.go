@@ -0,0 +1,371 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/table/entities"
+)
+
+var entityPathRegex = regexp.MustCompile(`^/([A-Za-z0-9]+)\(PartitionKey='(.*)',\s?RowKey='(.*)'\)$`)
+
+// fakeTableServer is an in-memory implementation of the subset of the Table Service used by Export and Import
+type fakeTableServer struct {
+	mu       sync.Mutex
+	tables   map[string]map[string]map[string]interface{}
+	pageSize int
+	batches  int
+	writes   int
+}
+
+func newFakeTableServer(pageSize int) *fakeTableServer {
+	return &fakeTableServer{
+		tables:   map[string]map[string]map[string]interface{}{},
+		pageSize: pageSize,
+	}
+}
+
+func (s *fakeTableServer) put(table string, entity map[string]interface{}) {
+	if _, ok := s.tables[table]; !ok {
+		s.tables[table] = map[string]map[string]interface{}{}
+	}
+	stored := make(map[string]interface{}, len(entity))
+	for k, v := range entity {
+		stored[k] = v
+	}
+	stored["Timestamp@odata.type"] = "Edm.DateTime"
+	stored["Timestamp"] = time.Now().UTC().Format(time.RFC3339Nano)
+	s.tables[table][fmt.Sprintf("%s|%s", entity["PartitionKey"], entity["RowKey"])] = stored
+	s.writes++
+}
+
+func (s *fakeTableServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/$batch" {
+		s.serveBatch(w, r)
+		return
+	}
+
+	if m := entityPathRegex.FindStringSubmatch(r.URL.Path); m != nil {
+		key := fmt.Sprintf("%s|%s", m[2], m[3])
+		switch r.Method {
+		case http.MethodGet:
+			entity, ok := s.tables[m[1]][key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json;odata=fullmetadata")
+			json.NewEncoder(w).Encode(entity)
+		case http.MethodPut:
+			var entity map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&entity)
+			s.put(m[1], entity)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	table := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), "()")
+	keys := make([]string, 0)
+	for k := range s.tables[table] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	start := 0
+	if v := r.URL.Query().Get("NextPartitionKey"); v != "" {
+		start, _ = strconv.Atoi(v)
+	}
+	end := start + s.pageSize
+	if end > len(keys) {
+		end = len(keys)
+	}
+	values := make([]map[string]interface{}, 0)
+	for _, k := range keys[start:end] {
+		entity := map[string]interface{}{
+			"odata.etag": "W/\"etag\"",
+			"odata.id":   fmt.Sprintf("https://example.table.core.windows.net/%s", k),
+		}
+		for key, value := range s.tables[table][k] {
+			entity[key] = value
+		}
+		values = append(values, entity)
+	}
+	if end < len(keys) {
+		w.Header().Set("x-ms-continuation-NextPartitionKey", strconv.Itoa(end))
+	}
+	w.Header().Set("Content-Type", "application/json;odata=fullmetadata")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"value": values,
+	})
+}
+
+func (s *fakeTableServer) serveBatch(w http.ResponseWriter, r *http.Request) {
+	s.batches++
+	_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	batchReader := multipart.NewReader(r.Body, params["boundary"])
+	changeSet, err := batchReader.NextPart()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_, params, _ = mime.ParseMediaType(changeSet.Header.Get("Content-Type"))
+	changeSetReader := multipart.NewReader(changeSet, params["boundary"])
+
+	responses := 0
+	for {
+		part, err := changeSetReader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		request, err := http.ReadRequest(bufio.NewReader(part))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m := entityPathRegex.FindStringSubmatch(request.URL.Path)
+		if request.Method != http.MethodPut || m == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var entity map[string]interface{}
+		json.NewDecoder(request.Body).Decode(&entity)
+		s.put(m[1], entity)
+		responses++
+	}
+
+	body := &bytes.Buffer{}
+	changeSetBody := &bytes.Buffer{}
+	changeSetWriter := multipart.NewWriter(changeSetBody)
+	for i := 0; i < responses; i++ {
+		part, _ := changeSetWriter.CreatePart(map[string][]string{
+			"Content-Type": {"application/http"},
+		})
+		fmt.Fprintf(part, "HTTP/1.1 204 No Content\r\nETag: W/\"etag%d\"\r\n\r\n", i)
+	}
+	changeSetWriter.Close()
+	batchWriter := multipart.NewWriter(body)
+	part, _ := batchWriter.CreatePart(map[string][]string{
+		"Content-Type": {fmt.Sprintf("multipart/mixed; boundary=%s", changeSetWriter.Boundary())},
+	})
+	part.Write(changeSetBody.Bytes())
+	batchWriter.Close()
+
+	w.Header().Set("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%s", batchWriter.Boundary()))
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(body.Bytes())
+}
+
+func buildTestEntities() []map[string]interface{} {
+	out := make([]map[string]interface{}, 0)
+	for p := 0; p < 3; p++ {
+		for r := 0; r < 150; r++ {
+			out = append(out, map[string]interface{}{
+				"PartitionKey":     fmt.Sprintf("partition%d", p),
+				"RowKey":           fmt.Sprintf("row%03d", r),
+				"name@odata.type":  "Edm.String",
+				"name":             fmt.Sprintf("entity %d/%d", p, r),
+				"count@odata.type": "Edm.Int64",
+				"count":            strconv.Itoa(p*1000 + r),
+			})
+		}
+	}
+	return out
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, useBatches := range []bool{false, true} {
+		t.Run(fmt.Sprintf("UseBatches=%t", useBatches), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+			defer cancel()
+
+			source := newFakeTableServer(100)
+			for _, entity := range buildTestEntities() {
+				source.put("source", entity)
+			}
+			sourceServer := httptest.NewServer(source)
+			defer sourceServer.Close()
+			sourceClient, err := entities.NewWithBaseUri(sourceServer.URL)
+			if err != nil {
+				t.Fatalf("building client: %+v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			exported, err := Export(ctx, *sourceClient, "source", buf, ExportInput{})
+			if err != nil {
+				t.Fatalf("exporting: %+v", err)
+			}
+			if exported.Entities != 450 {
+				t.Fatalf("expected 450 Entities to be exported but got %d", exported.Entities)
+			}
+			if strings.Contains(buf.String(), "odata.etag") {
+				t.Fatalf("expected the `odata.*` properties to be removed from the export")
+			}
+
+			destination := newFakeTableServer(100)
+			destinationServer := httptest.NewServer(destination)
+			defer destinationServer.Close()
+			destinationClient, err := entities.NewWithBaseUri(destinationServer.URL)
+			if err != nil {
+				t.Fatalf("building client: %+v", err)
+			}
+
+			checkpoints := make([]int64, 0)
+			importInput := ImportInput{
+				UseBatches:  useBatches,
+				Parallelism: 3,
+				Checkpoint: func(line int64) error {
+					checkpoints = append(checkpoints, line)
+					return nil
+				},
+			}
+			imported, err := Import(ctx, *destinationClient, "destination", buf, importInput)
+			if err != nil {
+				t.Fatalf("importing: %+v", err)
+			}
+			if imported.Entities != 450 || imported.Lines != 450 {
+				t.Fatalf("expected 450 Entities/Lines to be imported but got %d/%d", imported.Entities, imported.Lines)
+			}
+			if len(checkpoints) == 0 || checkpoints[len(checkpoints)-1] != 450 {
+				t.Fatalf("expected the final checkpoint to be 450 but got %v", checkpoints)
+			}
+			for i := 1; i < len(checkpoints); i++ {
+				if checkpoints[i] <= checkpoints[i-1] {
+					t.Fatalf("expected the checkpoints to be increasing but got %v", checkpoints)
+				}
+			}
+
+			if useBatches && destination.batches != 6 {
+				t.Fatalf("expected 6 batches but got %d", destination.batches)
+			}
+			for key, expected := range source.tables["source"] {
+				actual, ok := destination.tables["destination"][key]
+				if !ok {
+					t.Fatalf("expected the Entity %q to be imported", key)
+				}
+				if actual["count"] != expected["count"] || actual["count@odata.type"] != "Edm.Int64" {
+					t.Fatalf("expected the Entity %q to have the count %v (Edm.Int64) but got %v (%v)", key, expected["count"], actual["count"], actual["count@odata.type"])
+				}
+			}
+		})
+	}
+}
+
+func TestImportResumeFromCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	buf := &bytes.Buffer{}
+	for _, entity := range buildTestEntities() {
+		json.NewEncoder(buf).Encode(entity)
+	}
+
+	destination := newFakeTableServer(100)
+	server := httptest.NewServer(destination)
+	defer server.Close()
+	client, err := entities.NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	importInput := ImportInput{
+		ResumeFromLine: 400,
+	}
+	imported, err := Import(ctx, *client, "destination", buf, importInput)
+	if err != nil {
+		t.Fatalf("importing: %+v", err)
+	}
+	if imported.Entities != 50 || imported.Lines != 450 {
+		t.Fatalf("expected 50 Entities to be imported up to line 450 but got %d/%d", imported.Entities, imported.Lines)
+	}
+	if destination.writes != 50 {
+		t.Fatalf("expected 50 writes but got %d", destination.writes)
+	}
+	if _, ok := destination.tables["destination"]["partition2|row149"]; !ok {
+		t.Fatalf("expected the last Entity to be imported")
+	}
+	if _, ok := destination.tables["destination"]["partition2|row099"]; ok {
+		t.Fatalf("expected the Entities prior to the checkpoint to be skipped")
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	destination := newFakeTableServer(100)
+	destination.put("destination", map[string]interface{}{
+		"PartitionKey":     "partition1",
+		"RowKey":           "unchanged",
+		"count@odata.type": "Edm.Int64",
+		"count":            "1",
+	})
+	destination.put("destination", map[string]interface{}{
+		"PartitionKey":     "partition1",
+		"RowKey":           "updated",
+		"count@odata.type": "Edm.Int64",
+		"count":            "1",
+		"removed":          "value",
+	})
+	destination.writes = 0
+
+	input := strings.Join([]string{
+		`{"PartitionKey":"partition1","RowKey":"created","count@odata.type":"Edm.Int64","count":"1"}`,
+		`{"PartitionKey":"partition1","RowKey":"unchanged","count@odata.type":"Edm.Int64","count":"1","Timestamp":"2020-01-01T00:00:00Z"}`,
+		`{"PartitionKey":"partition1","RowKey":"updated","count@odata.type":"Edm.Int32","count":1,"added":true}`,
+	}, "\n")
+
+	server := httptest.NewServer(destination)
+	defer server.Close()
+	client, err := entities.NewWithBaseUri(server.URL)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+
+	diffs := make(map[string]EntityDiff)
+	importInput := ImportInput{
+		DryRun: true,
+		Diff: func(diff EntityDiff) {
+			diffs[diff.RowKey] = diff
+		},
+	}
+	if _, err := Import(ctx, *client, "destination", strings.NewReader(input), importInput); err != nil {
+		t.Fatalf("importing: %+v", err)
+	}
+	if destination.writes != 0 {
+		t.Fatalf("expected no writes during a dry run but got %d", destination.writes)
+	}
+
+	if diffs["created"].Action != DiffActionCreate {
+		t.Fatalf("expected `created` to be %q but got %q", DiffActionCreate, diffs["created"].Action)
+	}
+	if diffs["unchanged"].Action != DiffActionUnchanged {
+		t.Fatalf("expected `unchanged` to be %q but got %q (%v)", DiffActionUnchanged, diffs["unchanged"].Action, diffs["unchanged"].ChangedProperties)
+	}
+	if diffs["updated"].Action != DiffActionUpdate {
+		t.Fatalf("expected `updated` to be %q but got %q", DiffActionUpdate, diffs["updated"].Action)
+	}
+	if expected, actual := "added,count,removed", strings.Join(diffs["updated"].ChangedProperties, ","); expected != actual {
+		t.Fatalf("expected the changed properties to be %q but got %q", expected, actual)
+	}
+}
@@ -0,0 +1,110 @@
+package checksum
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/crc64"
+	"net/http"
+)
+
+// Algorithm is the algorithm used to compute a transactional checksum, which the Storage API uses to
+// validate the content of a request - or which is used to validate the content of a response.
+type Algorithm string
+
+const (
+	// MD5 computes an MD5 hash of the content, which is sent/returned using the `Content-MD5` header.
+	MD5 Algorithm = "MD5"
+
+	// CRC64 computes a CRC64 checksum of the content, which is sent/returned using the `x-ms-content-crc64` header.
+	// This is only supported by the Blob Service (including Data Lake Storage appends).
+	CRC64 Algorithm = "CRC64"
+)
+
+// maxRangeBytes is the largest range for which the Storage API returns a transactional checksum
+const maxRangeBytes = 4 * 1024 * 1024
+
+// crc64Table is the table for the polynomial used by Azure Storage to compute CRC64 checksums
+var crc64Table = crc64.MakeTable(0x9A6C9329AC4BC9B5)
+
+// Validate confirms that `algorithm` is a supported Algorithm.
+func (a Algorithm) Validate() error {
+	if a != MD5 && a != CRC64 {
+		return fmt.Errorf("expected the checksum algorithm to be %q or %q but got %q", string(MD5), string(CRC64), string(a))
+	}
+	return nil
+}
+
+// Compute returns the base64-encoded checksum of `content` using this Algorithm.
+func (a Algorithm) Compute(content []byte) string {
+	switch a {
+	case CRC64:
+		checksum := make([]byte, 8)
+		binary.LittleEndian.PutUint64(checksum, crc64.Checksum(content, crc64Table))
+		return base64.StdEncoding.EncodeToString(checksum)
+	default:
+		hash := md5.Sum(content)
+		return base64.StdEncoding.EncodeToString(hash[:])
+	}
+}
+
+// Header returns the name of the header containing the transactional checksum for this Algorithm.
+func (a Algorithm) Header() string {
+	if a == CRC64 {
+		return "x-ms-content-crc64"
+	}
+	return "Content-MD5"
+}
+
+// RangeHeader returns the name of the header used to request a transactional checksum for a range of
+// content using this Algorithm.
+func (a Algorithm) RangeHeader() string {
+	if a == CRC64 {
+		return "x-ms-range-get-content-crc64"
+	}
+	return "x-ms-range-get-content-md5"
+}
+
+// ValidateRange confirms that the Storage API can return a transactional checksum for a range of `length` bytes.
+func ValidateRange(length int64) error {
+	if length > maxRangeBytes {
+		return fmt.Errorf("a checksum can only be requested for a range of at most 4MB but got %d bytes", length)
+	}
+	return nil
+}
+
+// MismatchError is returned when the checksum of the content doesn't match the checksum returned by the
+// Storage API - meaning that the content was corrupted in transit.
+type MismatchError struct {
+	// Algorithm is the Algorithm used to compute the checksum.
+	Algorithm Algorithm
+
+	// Expected is the (base64-encoded) checksum returned by the Storage API.
+	Expected string
+
+	// Actual is the (base64-encoded) checksum computed from the content.
+	Actual string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("the %s checksum of the content (%q) didn't match the checksum returned by the Storage API (%q)", string(e.Algorithm), e.Actual, e.Expected)
+}
+
+// Verify confirms that the checksum of `content` matches the transactional checksum for this Algorithm
+// within the response headers - returning a *MismatchError when these differ, or an error if the
+// checksum wasn't returned.
+func (a Algorithm) Verify(header http.Header, content []byte) error {
+	expected := header.Get(a.Header())
+	if expected == "" {
+		return fmt.Errorf("expected the %q header to be returned but it wasn't", a.Header())
+	}
+	if actual := a.Compute(content); actual != expected {
+		return &MismatchError{
+			Algorithm: a,
+			Expected:  expected,
+			Actual:    actual,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package checksum
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	testData := []struct {
+		algorithm Algorithm
+		input     string
+		expected  string
+	}{
+		{
+			algorithm: MD5,
+			input:     "",
+			expected:  "1B2M2Y8AsgTpgAmY7PhCfg==",
+		},
+		{
+			algorithm: MD5,
+			input:     "hello world",
+			expected:  "XrY7u+Ae7tCTyyK7j1rNww==",
+		},
+		{
+			algorithm: CRC64,
+			input:     "",
+			expected:  "AAAAAAAAAAA=",
+		},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %s of %q", string(v.algorithm), v.input)
+		if actual := v.algorithm.Compute([]byte(v.input)); actual != v.expected {
+			t.Fatalf("expected %q but got %q", v.expected, actual)
+		}
+	}
+
+	if CRC64.Compute([]byte("hello")) == CRC64.Compute([]byte("world")) {
+		t.Fatalf("expected the CRC64 checksums of different content to differ")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	content := []byte("hello world")
+
+	header := http.Header{}
+	header.Set("x-ms-content-crc64", CRC64.Compute(content))
+	if err := CRC64.Verify(header, content); err != nil {
+		t.Fatalf("expected the checksum to match but got: %+v", err)
+	}
+
+	err := CRC64.Verify(header, []byte("hello there"))
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected a MismatchError but got: %+v", err)
+	}
+	if mismatch.Algorithm != CRC64 || mismatch.Expected != CRC64.Compute(content) {
+		t.Fatalf("expected the MismatchError to contain the algorithm and expected checksum but got %+v", *mismatch)
+	}
+
+	if err := MD5.Verify(header, content); err == nil || errors.As(err, &mismatch) {
+		t.Fatalf("expected an error when the checksum isn't returned but got: %+v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, v := range []Algorithm{MD5, CRC64} {
+		if err := v.Validate(); err != nil {
+			t.Fatalf("expected %q to be valid but got: %+v", string(v), err)
+		}
+	}
+	if err := Algorithm("SHA256").Validate(); err == nil {
+		t.Fatalf("expected an unsupported algorithm to be invalid")
+	}
+}
@@ -0,0 +1,136 @@
+package blobserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// authenticate validates the SharedKey signature (or Bearer token) within the Authorization header of the request
+func (s *Server) authenticate(r *http.Request) error {
+	authorization := r.Header.Get("Authorization")
+	if authorization == "" {
+		if s.AllowAnonymousAccess {
+			return nil
+		}
+		return newError(http.StatusForbidden, "NoAuthenticationInformation", "the request doesn't contain an Authorization header")
+	}
+
+	scheme, credential, ok := strings.Cut(authorization, " ")
+	if ok && scheme == "Bearer" {
+		if s.BearerToken == "" || credential != s.BearerToken {
+			return newError(http.StatusUnauthorized, "InvalidAuthenticationInfo", "the bearer token is not valid for this account")
+		}
+		return nil
+	}
+	if !ok || scheme != "SharedKey" {
+		return newError(http.StatusForbidden, "AuthenticationFailed", "expected the Authorization header to use the SharedKey scheme")
+	}
+	accountName, signature, ok := strings.Cut(credential, ":")
+	if !ok || strings.TrimSuffix(accountName, "-secondary") != s.AccountName {
+		return newError(http.StatusForbidden, "AuthenticationFailed", "expected the Authorization header to be for the account %q", s.AccountName)
+	}
+	if r.Header.Get("Date") == "" && r.Header.Get("x-ms-date") == "" {
+		return newError(http.StatusForbidden, "AuthenticationFailed", "either the `Date` or `x-ms-date` header must be specified")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(s.AccountKey)
+	if err != nil {
+		return newError(http.StatusInternalServerError, "InternalError", "decoding the account key: %+v", err)
+	}
+
+	// When using a path-style URI the Account Name is both the first segment of the path and the prefix
+	// of the Canonicalized Resource - however some clients (for example when using the Emulator Account)
+	// omit the prefix, so both forms are accepted.
+	candidates := []string{
+		fmt.Sprintf("/%s%s", s.AccountName, r.URL.EscapedPath()),
+		r.URL.EscapedPath(),
+	}
+	for _, resource := range candidates {
+		stringToSign := buildStringToSign(r, resource)
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(stringToSign))
+		if hmac.Equal([]byte(base64.StdEncoding.EncodeToString(h.Sum(nil))), []byte(signature)) {
+			return nil
+		}
+	}
+
+	return newError(http.StatusForbidden, "AuthenticationFailed", "the MAC signature found in the HTTP request %q is not the same as any computed signature", signature)
+}
+
+// buildStringToSign builds the string to sign for a SharedKey Authorization header, as documented at
+// https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func buildStringToSign(r *http.Request, canonicalizedPath string) string {
+	contentLength := ""
+	if r.ContentLength > 0 {
+		contentLength = fmt.Sprintf("%d", r.ContentLength)
+	}
+	date := r.Header.Get("Date")
+	if r.Header.Get("x-ms-date") != "" {
+		date = ""
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		r.Header.Get("Content-Encoding"),
+		r.Header.Get("Content-Language"),
+		contentLength,
+		r.Header.Get("Content-MD5"),
+		r.Header.Get("Content-Type"),
+		date,
+		r.Header.Get("If-Modified-Since"),
+		r.Header.Get("If-Match"),
+		r.Header.Get("If-None-Match"),
+		r.Header.Get("If-Unmodified-Since"),
+		r.Header.Get("Range"),
+		canonicalizedHeaders(r.Header),
+		canonicalizedResource(r, canonicalizedPath),
+	}, "\n")
+}
+
+func canonicalizedHeaders(header http.Header) string {
+	values := map[string]string{}
+	for k := range header {
+		key := strings.ToLower(strings.TrimSpace(k))
+		if strings.HasPrefix(key, "x-ms-") {
+			values[key] = header.Get(k)
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s:%s", k, values[k]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func canonicalizedResource(r *http.Request, canonicalizedPath string) string {
+	query := r.URL.Query()
+	if len(query) == 0 {
+		return canonicalizedPath
+	}
+
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := []string{canonicalizedPath}
+	for _, k := range keys {
+		values := append([]string{}, query[k]...)
+		sort.Strings(values)
+		lines = append(lines, fmt.Sprintf("%s:%s", strings.ToLower(k), strings.Join(values, ",")))
+	}
+	return strings.Join(lines, "\n")
+}
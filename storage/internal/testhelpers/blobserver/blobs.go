@@ -0,0 +1,923 @@
+package blobserver
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	blobTypeAppend = "AppendBlob"
+	blobTypeBlock  = "BlockBlob"
+	blobTypePage   = "PageBlob"
+
+	pageSize = 512
+)
+
+type blob struct {
+	blobType     string
+	content      []byte
+	metaData     map[string]string
+	tags         map[string]string
+	etag         string
+	creationTime time.Time
+	lastModified time.Time
+	lease        lease
+
+	cacheControl       string
+	contentDisposition string
+	contentEncoding    string
+	contentLanguage    string
+	contentMD5         string
+	contentType        string
+
+	// encryptionKeySHA256 is the SHA-256 hash of the Customer-Provided Key used to encrypt this Blob (if any)
+	encryptionKeySHA256 string
+
+	accessTier           string
+	accessTierInferred   bool
+	accessTierChangeTime time.Time
+	rehydrateTier        string
+	rehydratePriority    string
+	rehydratedAt         time.Time
+
+	// uncommitted specifies that this Blob only contains uncommitted blocks and as such doesn't exist yet
+	uncommitted       bool
+	committedBlocks   []block
+	uncommittedBlocks []block
+
+	appendBlockCount int
+	sequenceNumber   int64
+	pages            map[int64]bool
+
+	copyID                string
+	copySource            string
+	copyStatus            string
+	copyStatusDescription string
+	copyProgress          string
+	copyCompletionTime    time.Time
+	incrementalCopy       bool
+
+	immutabilityPolicyExpiry time.Time
+	immutabilityPolicyMode   string
+	legalHold                bool
+
+	snapshots map[string]*blob
+
+	// versionId is the Version ID of this Blob, which is only set when Versioning is enabled
+	versionId string
+	// previousVersion specifies that this is a previous Version of the Blob, rather than the current Version
+	previousVersion bool
+	versions        map[string]*blob
+}
+
+type block struct {
+	id   string
+	data []byte
+}
+
+// refresh completes any pending rehydration of the Blob (and its Snapshots and Versions) once the rehydration period has elapsed
+func (b *blob) refresh(now time.Time) {
+	b.lease.refresh(now)
+	b.completeRehydration(now)
+	for _, snapshot := range b.snapshots {
+		snapshot.completeRehydration(now)
+	}
+	for _, version := range b.versions {
+		version.completeRehydration(now)
+	}
+}
+
+// completeRehydration moves the Blob into the tier it's being rehydrated to once the rehydration period has elapsed -
+// completing the pending copy when the Blob is the destination of a copy from an archived source
+func (b *blob) completeRehydration(now time.Time) {
+	if b.rehydrateTier == "" || now.Before(b.rehydratedAt) {
+		return
+	}
+	b.accessTier = b.rehydrateTier
+	b.accessTierChangeTime = now
+	b.rehydrateTier = ""
+	b.rehydratePriority = ""
+	if b.copyStatus == "pending" {
+		b.copyStatus = "success"
+		b.copyProgress = fmt.Sprintf("%d/%d", len(b.content), len(b.content))
+		b.copyCompletionTime = now
+	}
+}
+
+// isArchived returns whether the content of this Blob can't be read, since it's in (or is being rehydrated from) the Archive tier
+func (b *blob) isArchived() bool {
+	return b.blobType == blobTypeBlock && (b.accessTier == "Archive" || b.rehydrateTier != "")
+}
+
+// clone returns a copy of this Blob (without any Snapshots or Versions), for use as a Snapshot, a Version or the destination of a Copy
+func (b *blob) clone() *blob {
+	out := *b
+	out.content = append([]byte{}, b.content...)
+	out.metaData = copyMetaData(b.metaData)
+	if b.tags != nil {
+		out.tags = copyMetaData(b.tags)
+	}
+	out.lease = lease{state: leaseStateAvailable}
+	out.committedBlocks = append([]block{}, b.committedBlocks...)
+	out.uncommittedBlocks = nil
+	out.pages = map[int64]bool{}
+	for k, v := range b.pages {
+		out.pages[k] = v
+	}
+	out.snapshots = nil
+	out.versions = nil
+	return &out
+}
+
+func (b *blob) sortedSnapshotIds() []string {
+	ids := make([]string, 0, len(b.snapshots))
+	for id := range b.snapshots {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (b *blob) writeHeaders(header http.Header) {
+	header.Set("ETag", b.etag)
+	header.Set("Last-Modified", formatTime(b.lastModified))
+	header.Set("x-ms-creation-time", formatTime(b.creationTime))
+	header.Set("x-ms-blob-type", b.blobType)
+	header.Set("x-ms-server-encrypted", "true")
+	setIfNotEmpty(header, "x-ms-encryption-key-sha256", b.encryptionKeySHA256)
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Content-Type", b.contentType)
+	setIfNotEmpty(header, "Cache-Control", b.cacheControl)
+	setIfNotEmpty(header, "Content-Disposition", b.contentDisposition)
+	setIfNotEmpty(header, "Content-Encoding", b.contentEncoding)
+	setIfNotEmpty(header, "Content-Language", b.contentLanguage)
+	setIfNotEmpty(header, "Content-MD5", b.contentMD5)
+	b.writeImmutabilityHeaders(header)
+	if b.versionId != "" {
+		header.Set("x-ms-version-id", b.versionId)
+		header.Set("x-ms-is-current-version", strconv.FormatBool(!b.previousVersion))
+	}
+
+	switch b.blobType {
+	case blobTypeAppend:
+		header.Set("x-ms-blob-committed-block-count", strconv.Itoa(b.appendBlockCount))
+	case blobTypeBlock:
+		header.Set("x-ms-access-tier", b.accessTier)
+		header.Set("x-ms-access-tier-inferred", strconv.FormatBool(b.accessTierInferred))
+		if !b.accessTierChangeTime.IsZero() {
+			header.Set("x-ms-access-tier-change-time", formatTime(b.accessTierChangeTime))
+		}
+		if b.rehydrateTier != "" {
+			header.Set("x-ms-archive-status", fmt.Sprintf("rehydrate-pending-to-%s", strings.ToLower(b.rehydrateTier)))
+			header.Set("x-ms-rehydrate-priority", b.rehydratePriority)
+		}
+	case blobTypePage:
+		header.Set("x-ms-blob-sequence-number", strconv.FormatInt(b.sequenceNumber, 10))
+		if b.incrementalCopy {
+			header.Set("x-ms-incremental-copy", "true")
+		}
+	}
+
+	if b.copyID != "" {
+		header.Set("x-ms-copy-id", b.copyID)
+		header.Set("x-ms-copy-source", b.copySource)
+		header.Set("x-ms-copy-status", b.copyStatus)
+		header.Set("x-ms-copy-progress", b.copyProgress)
+		setIfNotEmpty(header, "x-ms-copy-status-description", b.copyStatusDescription)
+		if !b.copyCompletionTime.IsZero() {
+			header.Set("x-ms-copy-completion-time", formatTime(b.copyCompletionTime))
+		}
+	}
+
+	if len(b.tags) > 0 {
+		header.Set("x-ms-tag-count", strconv.Itoa(len(b.tags)))
+	}
+
+	writeMetaData(header, b.metaData)
+	b.lease.writeHeaders(header)
+}
+
+func (b *blob) listItem(name, snapshotId string, include map[string]bool) listBlobItem {
+	item := listBlobItem{
+		Name:     name,
+		Snapshot: snapshotId,
+		Properties: listBlobProperties{
+			CreationTime:       formatTime(b.creationTime),
+			LastModified:       formatTime(b.lastModified),
+			ETag:               b.etag,
+			ContentLength:      int64(len(b.content)),
+			ContentType:        b.contentType,
+			ContentEncoding:    b.contentEncoding,
+			ContentLanguage:    b.contentLanguage,
+			ContentMD5:         b.contentMD5,
+			CacheControl:       b.cacheControl,
+			ContentDisposition: b.contentDisposition,
+			BlobType:           b.blobType,
+			LeaseState:         b.lease.state,
+			LeaseStatus:        "unlocked",
+			ServerEncrypted:    true,
+		},
+	}
+	if include["versions"] && b.versionId != "" {
+		item.VersionId = b.versionId
+		if !b.previousVersion {
+			item.IsCurrentVersion = "true"
+		}
+	}
+	if b.lease.isActive() {
+		item.Properties.LeaseStatus = "locked"
+	}
+	if b.lease.state == leaseStateLeased {
+		item.Properties.LeaseDuration = "fixed"
+		if b.lease.duration == -1 {
+			item.Properties.LeaseDuration = "infinite"
+		}
+	}
+	switch b.blobType {
+	case blobTypeBlock:
+		item.Properties.AccessTier = b.accessTier
+		item.Properties.AccessTierInferred = strconv.FormatBool(b.accessTierInferred)
+		if b.rehydrateTier != "" {
+			item.Properties.ArchiveStatus = fmt.Sprintf("rehydrate-pending-to-%s", strings.ToLower(b.rehydrateTier))
+			item.Properties.RehydratePriority = b.rehydratePriority
+		}
+	case blobTypePage:
+		item.Properties.BlobSequenceNumber = strconv.FormatInt(b.sequenceNumber, 10)
+		if b.incrementalCopy {
+			item.Properties.IncrementalCopy = "true"
+		}
+	}
+	if include["copy"] && b.copyID != "" {
+		item.Properties.CopyID = b.copyID
+		item.Properties.CopyStatus = b.copyStatus
+		item.Properties.CopySource = b.copySource
+		item.Properties.CopyProgress = b.copyProgress
+		item.Properties.CopyStatusDescription = b.copyStatusDescription
+		if !b.copyCompletionTime.IsZero() {
+			item.Properties.CopyCompletionTime = formatTime(b.copyCompletionTime)
+		}
+	}
+	if include["immutabilitypolicy"] && b.immutabilityPolicyMode != "" {
+		item.Properties.ImmutabilityPolicyUntilDate = formatTime(b.immutabilityPolicyExpiry)
+		item.Properties.ImmutabilityPolicyMode = b.immutabilityPolicyMode
+	}
+	if include["legalhold"] {
+		item.Properties.LegalHold = strconv.FormatBool(b.legalHold)
+	}
+	if include["metadata"] {
+		item.MetaData = &listBlobMetaData{}
+		keys := make([]string, 0, len(b.metaData))
+		for k := range b.metaData {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			item.MetaData.Items = append(item.MetaData.Items, listBlobMetaDataItem{
+				XMLName: xml.Name{Local: k},
+				Value:   b.metaData[k],
+			})
+		}
+	}
+	return item
+}
+
+func setIfNotEmpty(header http.Header, key, value string) {
+	if value != "" {
+		header.Set(key, value)
+	}
+}
+
+func contentMD5(input []byte) string {
+	hash := md5.Sum(input)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func (s *Server) handleBlob(r *request) (*response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+
+	c, exists := s.containers[r.containerName]
+	if !exists {
+		return nil, newError(http.StatusNotFound, "ContainerNotFound", "the specified container does not exist")
+	}
+
+	b := c.blobs[r.blobName]
+	if b != nil {
+		b.refresh(now)
+	}
+
+	// operations against a specific Snapshot of the Blob
+	if snapshotId := r.query.Get("snapshot"); snapshotId != "" {
+		if b == nil || b.uncommitted || b.snapshots[snapshotId] == nil {
+			return nil, newError(http.StatusNotFound, "BlobNotFound", "the specified blob snapshot does not exist")
+		}
+		return s.handleSnapshot(r, b, snapshotId, now)
+	}
+
+	// operations against a specific Version of the Blob - where the current Version is handled as the Blob itself
+	if versionId := r.query.Get("versionid"); versionId != "" {
+		if b.lookupVersion(versionId) == nil {
+			return nil, newError(http.StatusNotFound, "BlobNotFound", "the specified blob version does not exist")
+		}
+		if b.uncommitted || b.versionId != versionId {
+			return s.handleVersion(r, b, versionId, now)
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		switch r.comp() {
+		case "":
+			return s.getBlob(r, b, now)
+		case "blocklist":
+			return s.getBlockList(r, b, now)
+		case "pagelist":
+			return s.getPageRanges(r, b, now)
+		case "metadata":
+			if b == nil || b.uncommitted {
+				return nil, blobNotFound()
+			}
+			if err := checkEncryptionKey(r, b); err != nil {
+				return nil, err
+			}
+			resp := newResponse(http.StatusOK)
+			resp.header.Set("ETag", b.etag)
+			resp.header.Set("Last-Modified", formatTime(b.lastModified))
+			writeMetaData(resp.header, b.metaData)
+			return resp, nil
+		}
+
+	case http.MethodPut:
+		switch r.comp() {
+		case "":
+			if r.Header.Get("x-ms-copy-source") != "" {
+				if r.Header.Get("x-ms-blob-type") != "" {
+					return s.putBlobFromURL(r, c, b, now)
+				}
+				return s.copyBlob(r, c, b, now)
+			}
+			return s.putBlob(r, c, b, now)
+		case "appendblock":
+			return s.appendBlock(r, b, now)
+		case "block":
+			return s.putBlock(r, c, b, now)
+		case "blocklist":
+			return s.putBlockList(r, c, b, now)
+		case "copy":
+			if b == nil || b.uncommitted {
+				return nil, blobNotFound()
+			}
+			return nil, newError(http.StatusConflict, "NoPendingCopyOperation", "there is currently no pending copy operation")
+		case "immutabilityPolicies":
+			return setImmutabilityPolicy(r, b, now)
+		case "incrementalcopy":
+			return s.incrementalCopyBlob(r, c, b, now)
+		case "legalhold":
+			return setLegalHold(r, b)
+		case "lease":
+			if b == nil || b.uncommitted {
+				return nil, blobNotFound()
+			}
+			return b.lease.apply(r, now)
+		case "metadata":
+			return s.setBlobMetaData(r, b, now)
+		case "page":
+			return s.putPage(r, b, now)
+		case "properties":
+			return s.setBlobProperties(r, b, now)
+		case "snapshot":
+			return s.snapshotBlob(r, b, now)
+		case "tier":
+			return s.setBlobTier(r, b, now)
+		case "undelete":
+			if b == nil || b.uncommitted {
+				return nil, blobNotFound()
+			}
+			return newResponse(http.StatusOK), nil
+		}
+
+	case http.MethodPost:
+		if r.comp() == "query" {
+			return s.queryBlob(r, b, now)
+		}
+
+	case http.MethodDelete:
+		switch r.comp() {
+		case "":
+			return s.deleteBlob(r, c, b, now)
+		case "immutabilityPolicies":
+			return deleteImmutabilityPolicy(b)
+		}
+	}
+
+	return nil, newError(http.StatusBadRequest, "UnsupportedHttpVerb", "the operation %s with comp %q isn't supported for blobs", r.Method, r.comp())
+}
+
+func blobNotFound() error {
+	return newError(http.StatusNotFound, "BlobNotFound", "the specified blob does not exist")
+}
+
+func blobArchived() error {
+	return newError(http.StatusConflict, "BlobArchived", "this operation is not permitted on an archived blob")
+}
+
+// touch updates the ETag and Last Modified time of the Blob
+func (s *Server) touch(b *blob, now time.Time) {
+	b.etag = s.nextETag()
+	b.lastModified = now
+}
+
+func (s *Server) newBlob(r *request, blobType string, now time.Time) *blob {
+	contentType := r.Header.Get("x-ms-blob-content-type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	b := &blob{
+		blobType:           blobType,
+		metaData:           parseMetaData(r.Header),
+		creationTime:       now,
+		lease:              lease{state: leaseStateAvailable},
+		cacheControl:       r.Header.Get("x-ms-blob-cache-control"),
+		contentDisposition: r.Header.Get("x-ms-blob-content-disposition"),
+		contentEncoding:    r.Header.Get("x-ms-blob-content-encoding"),
+		contentLanguage:    r.Header.Get("x-ms-blob-content-language"),
+		contentMD5:         r.Header.Get("x-ms-blob-content-md5"),
+		contentType:        contentType,
+		pages:              map[int64]bool{},
+	}
+	if blobType == blobTypeBlock {
+		b.accessTier = "Hot"
+		b.accessTierInferred = true
+		if v := r.Header.Get("x-ms-access-tier"); v != "" {
+			b.accessTier = v
+			b.accessTierInferred = false
+			b.accessTierChangeTime = now
+		}
+	}
+	s.touch(b, now)
+	return b
+}
+
+// replaceBlob stores `replacement` as the Blob `name`, retaining the Lease, Snapshots and Versions of the existing
+// Blob - when Versioning is enabled the existing Blob is retained as a previous Version
+func (s *Server) replaceBlob(c *container, name string, existing, replacement *blob, now time.Time) {
+	replacement.versions = nil
+	if existing != nil {
+		replacement.lease = existing.lease
+		replacement.snapshots = existing.snapshots
+		replacement.versions = existing.versions
+		if !existing.uncommitted {
+			replacement.creationTime = existing.creationTime
+			archiveVersion(replacement, existing)
+		}
+	}
+	s.assignVersion(replacement, now)
+	c.blobs[name] = replacement
+}
+
+func (s *Server) checkBlobWrite(r *request, b *blob, now time.Time) error {
+	exists := b != nil && !b.uncommitted
+	etag := ""
+	lastModified := time.Time{}
+	if exists {
+		etag = b.etag
+		lastModified = b.lastModified
+	}
+	if err := checkConditions(r, exists, etag, lastModified); err != nil {
+		return err
+	}
+	if exists {
+		if err := b.checkImmutable(now); err != nil {
+			return err
+		}
+		return b.lease.checkWrite(r.leaseID(), now)
+	}
+	return nil
+}
+
+func (s *Server) putBlob(r *request, c *container, b *blob, now time.Time) (*response, error) {
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+
+	keySHA256, err := encryptionKeySHA256(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := parseTags(r.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	blobType := r.Header.Get("x-ms-blob-type")
+	replacement := s.newBlob(r, blobType, now)
+	replacement.encryptionKeySHA256 = keySHA256
+	replacement.tags = tags
+	switch blobType {
+	case blobTypeAppend:
+		// nothing to do
+
+	case blobTypeBlock:
+		if err := checkTransactionalChecksums(r, r.body); err != nil {
+			return nil, err
+		}
+		replacement.content = r.body
+		if replacement.contentMD5 == "" {
+			replacement.contentMD5 = contentMD5(r.body)
+		}
+
+	case blobTypePage:
+		length, err := strconv.ParseInt(r.Header.Get("x-ms-blob-content-length"), 10, 64)
+		if err != nil || length < 0 || length%pageSize != 0 {
+			return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the `x-ms-blob-content-length` header must be a multiple of %d", pageSize)
+		}
+		replacement.content = make([]byte, length)
+		if v := r.Header.Get("x-ms-blob-sequence-number"); v != "" {
+			sequenceNumber, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "parsing `x-ms-blob-sequence-number`: %+v", err)
+			}
+			replacement.sequenceNumber = sequenceNumber
+		}
+
+	default:
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "unsupported blob type %q", blobType)
+	}
+	s.replaceBlob(c, r.blobName, b, replacement, now)
+
+	resp := newResponse(http.StatusCreated)
+	resp.header.Set("ETag", replacement.etag)
+	resp.header.Set("Last-Modified", formatTime(replacement.lastModified))
+	setIfNotEmpty(resp.header, "x-ms-version-id", replacement.versionId)
+	resp.header.Set("x-ms-request-server-encrypted", "true")
+	setIfNotEmpty(resp.header, "x-ms-encryption-key-sha256", keySHA256)
+	if blobType == blobTypeBlock {
+		resp.header.Set("Content-MD5", contentMD5(r.body))
+	}
+	return resp, nil
+}
+
+func (s *Server) getBlob(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := b.lease.checkRead(r.leaseID(), now); err != nil {
+		return nil, err
+	}
+	return readBlob(r, b)
+}
+
+// readBlob returns the contents (for GET requests) or properties (for HEAD requests) of either a Blob or a Snapshot
+func readBlob(r *request, b *blob) (*response, error) {
+	if err := checkEncryptionKey(r, b); err != nil {
+		return nil, err
+	}
+	if err := checkConditions(r, true, b.etag, b.lastModified); err != nil {
+		return nil, err
+	}
+	if r.Method != http.MethodHead && b.isArchived() {
+		return nil, blobArchived()
+	}
+
+	resp := newResponse(http.StatusOK)
+	b.writeHeaders(resp.header)
+	if r.Method == http.MethodHead {
+		resp.header.Set("Content-Length", strconv.Itoa(len(b.content)))
+		return resp, nil
+	}
+
+	rangeHeader := r.Header.Get("x-ms-range")
+	if rangeHeader == "" {
+		rangeHeader = r.Header.Get("Range")
+	}
+	if rangeHeader == "" {
+		resp.body = b.content
+		return resp, nil
+	}
+
+	start, end, err := parseRange(rangeHeader)
+	if err != nil {
+		return nil, err
+	}
+	size := int64(len(b.content))
+	if start >= size {
+		return nil, newError(http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "the range specified is invalid for the current size of the resource")
+	}
+	if end < 0 || end >= size {
+		end = size - 1
+	}
+	resp.status = http.StatusPartialContent
+	resp.header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	resp.header.Del("Content-MD5")
+	resp.body = b.content[start : end+1]
+	if err := writeRangeChecksums(r, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// parseRange parses a range header in the format `bytes=start-end`, where `end` is optional (in which case -1 is returned)
+func parseRange(input string) (int64, int64, error) {
+	invalid := newError(http.StatusBadRequest, "InvalidHeaderValue", "the range %q is invalid", input)
+	v, ok := strings.CutPrefix(input, "bytes=")
+	if !ok {
+		return 0, 0, invalid
+	}
+	startRaw, endRaw, ok := strings.Cut(v, "-")
+	if !ok {
+		return 0, 0, invalid
+	}
+	start, err := strconv.ParseInt(startRaw, 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+	end := int64(-1)
+	if endRaw != "" {
+		end, err = strconv.ParseInt(endRaw, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, invalid
+		}
+	}
+	return start, end, nil
+}
+
+func (s *Server) setBlobMetaData(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+	if err := checkEncryptionKey(r, b); err != nil {
+		return nil, err
+	}
+	archiveVersion(b, b)
+	b.metaData = parseMetaData(r.Header)
+	s.touch(b, now)
+	s.assignVersion(b, now)
+
+	resp := newResponse(http.StatusOK)
+	resp.header.Set("ETag", b.etag)
+	resp.header.Set("Last-Modified", formatTime(b.lastModified))
+	setIfNotEmpty(resp.header, "x-ms-version-id", b.versionId)
+	return resp, nil
+}
+
+func (s *Server) setBlobProperties(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+
+	// the content headers are replaced as a set, with those which aren't specified being cleared
+	b.cacheControl = r.Header.Get("x-ms-blob-cache-control")
+	b.contentDisposition = r.Header.Get("x-ms-blob-content-disposition")
+	b.contentEncoding = r.Header.Get("x-ms-blob-content-encoding")
+	b.contentLanguage = r.Header.Get("x-ms-blob-content-language")
+	b.contentMD5 = r.Header.Get("x-ms-blob-content-md5")
+	b.contentType = r.Header.Get("x-ms-blob-content-type")
+
+	if v := r.Header.Get("x-ms-blob-content-length"); v != "" {
+		if b.blobType != blobTypePage {
+			return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the content length can only be set for page blobs")
+		}
+		length, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || length < 0 || length%pageSize != 0 {
+			return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the `x-ms-blob-content-length` header must be a multiple of %d", pageSize)
+		}
+		resized := make([]byte, length)
+		copy(resized, b.content)
+		b.content = resized
+		for page := range b.pages {
+			if page*pageSize >= length {
+				delete(b.pages, page)
+			}
+		}
+	}
+
+	if action := r.Header.Get("x-ms-sequence-number-action"); action != "" {
+		if b.blobType != blobTypePage {
+			return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the sequence number can only be set for page blobs")
+		}
+		value := int64(0)
+		if v := r.Header.Get("x-ms-blob-sequence-number"); v != "" {
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "parsing `x-ms-blob-sequence-number`: %+v", err)
+			}
+			value = i
+		}
+		switch strings.ToLower(action) {
+		case "increment":
+			b.sequenceNumber++
+		case "max":
+			if value > b.sequenceNumber {
+				b.sequenceNumber = value
+			}
+		case "update":
+			b.sequenceNumber = value
+		default:
+			return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "unsupported sequence number action %q", action)
+		}
+	}
+	s.touch(b, now)
+
+	resp := newResponse(http.StatusOK)
+	resp.header.Set("ETag", b.etag)
+	resp.header.Set("Last-Modified", formatTime(b.lastModified))
+	if b.blobType == blobTypePage {
+		resp.header.Set("x-ms-blob-sequence-number", strconv.FormatInt(b.sequenceNumber, 10))
+	}
+	return resp, nil
+}
+
+func (s *Server) setBlobTier(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := b.lease.checkRead(r.leaseID(), now); err != nil {
+		return nil, err
+	}
+	if b.blobType != blobTypeBlock {
+		return nil, newError(http.StatusBadRequest, "InvalidBlobType", "the access tier can only be set for block blobs")
+	}
+
+	tier := r.Header.Get("x-ms-access-tier")
+	valid := false
+	for _, v := range []string{"Hot", "Cool", "Cold", "Archive"} {
+		if strings.EqualFold(v, tier) {
+			tier = v
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "unsupported access tier %q", tier)
+	}
+
+	priority, err := parseRehydratePriority(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.accessTier == "Archive" && tier != "Archive" {
+		if b.rehydrateTier != "" {
+			// the only change permitted during rehydration is raising the priority from Standard to High
+			if tier != b.rehydrateTier || priority != "High" || b.rehydratePriority != "Standard" {
+				return nil, newError(http.StatusConflict, "BlobBeingRehydrated", "this operation is not permitted because the blob is being rehydrated")
+			}
+			b.rehydratePriority = priority
+			return newResponse(http.StatusAccepted), nil
+		}
+		b.rehydrateTier = tier
+		b.rehydratePriority = priority
+		b.rehydratedAt = now.Add(s.RehydrationDelay)
+		return newResponse(http.StatusAccepted), nil
+	}
+
+	b.accessTier = tier
+	b.accessTierInferred = false
+	b.accessTierChangeTime = now
+	return newResponse(http.StatusOK), nil
+}
+
+// parseRehydratePriority returns the value of the `x-ms-rehydrate-priority` header, defaulting to Standard
+func parseRehydratePriority(r *request) (string, error) {
+	priority := r.Header.Get("x-ms-rehydrate-priority")
+	if priority == "" {
+		return "Standard", nil
+	}
+	for _, v := range []string{"High", "Standard"} {
+		if strings.EqualFold(v, priority) {
+			return v, nil
+		}
+	}
+	return "", newError(http.StatusBadRequest, "InvalidHeaderValue", "unsupported rehydrate priority %q", priority)
+}
+
+func (s *Server) snapshotBlob(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := checkConditions(r, true, b.etag, b.lastModified); err != nil {
+		return nil, err
+	}
+	if err := b.lease.checkRead(r.leaseID(), now); err != nil {
+		return nil, err
+	}
+	if err := checkEncryptionKey(r, b); err != nil {
+		return nil, err
+	}
+
+	snapshot := b.clone()
+	snapshot.versionId = ""
+	if metaData := parseMetaData(r.Header); len(metaData) > 0 {
+		snapshot.metaData = metaData
+	}
+	snapshotId := formatSnapshot(now)
+	if b.snapshots == nil {
+		b.snapshots = map[string]*blob{}
+	}
+	b.snapshots[snapshotId] = snapshot
+
+	resp := newResponse(http.StatusCreated)
+	resp.header.Set("ETag", b.etag)
+	resp.header.Set("Last-Modified", formatTime(b.lastModified))
+	resp.header.Set("x-ms-snapshot", snapshotId)
+	setIfNotEmpty(resp.header, "x-ms-version-id", b.versionId)
+	return resp, nil
+}
+
+func (s *Server) handleSnapshot(r *request, b *blob, snapshotId string, now time.Time) (*response, error) {
+	snapshot := b.snapshots[snapshotId]
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		switch r.comp() {
+		case "":
+			return readBlob(r, snapshot)
+		case "blocklist":
+			return blockListResponse(r, snapshot)
+		case "pagelist":
+			return s.pageRangesResponse(r, snapshot, b)
+		}
+	case http.MethodPost:
+		if r.comp() == "query" {
+			return queryResponse(r, snapshot)
+		}
+	case http.MethodPut:
+		switch r.comp() {
+		case "tier":
+			return s.setBlobTier(r, snapshot, now)
+		case "immutabilityPolicies":
+			return setImmutabilityPolicy(r, snapshot, now)
+		case "legalhold":
+			return setLegalHold(r, snapshot)
+		}
+	case http.MethodDelete:
+		switch r.comp() {
+		case "":
+			if err := snapshot.checkImmutable(now); err != nil {
+				return nil, err
+			}
+			delete(b.snapshots, snapshotId)
+			return newResponse(http.StatusAccepted), nil
+		case "immutabilityPolicies":
+			return deleteImmutabilityPolicy(snapshot)
+		}
+	}
+
+	return nil, newError(http.StatusBadRequest, "UnsupportedHttpVerb", "the operation %s with comp %q isn't supported for snapshots", r.Method, r.comp())
+}
+
+func (s *Server) deleteBlob(r *request, c *container, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+
+	switch r.Header.Get("x-ms-delete-snapshots") {
+	case "":
+		if len(b.snapshots) > 0 {
+			return nil, newError(http.StatusConflict, "SnapshotsPresent", "this operation is not permitted because the blob has snapshots")
+		}
+		s.removeBlob(c, r.blobName, b)
+	case "include":
+		s.removeBlob(c, r.blobName, b)
+	case "only":
+		b.snapshots = nil
+	default:
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "unsupported value for `x-ms-delete-snapshots`")
+	}
+
+	resp := newResponse(http.StatusAccepted)
+	resp.header.Set("x-ms-delete-type-permanent", "true")
+	return resp, nil
+}
+
+// removeBlob deletes the Blob `name` - when Versioning is enabled the Blob is retained as a previous Version,
+// such that it can be restored by copying the Version over the (now deleted) Blob
+func (s *Server) removeBlob(c *container, name string, b *blob) {
+	if !s.VersioningEnabled || b.versionId == "" {
+		delete(c.blobs, name)
+		return
+	}
+	archiveVersion(b, b)
+	c.blobs[name] = &blob{
+		uncommitted: true,
+		lease:       lease{state: leaseStateAvailable},
+		pages:       map[int64]bool{},
+		versions:    b.versions,
+	}
+}
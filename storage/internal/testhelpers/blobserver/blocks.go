@@ -0,0 +1,617 @@
+package blobserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
+)
+
+const (
+	maxBlocks     = 50000
+	maxBlockBytes = 4000 * 1024 * 1024
+)
+
+func (s *Server) putBlock(r *request, c *container, b *blob, now time.Time) (*response, error) {
+	blockID := r.query.Get("blockid")
+	decoded, err := base64.StdEncoding.DecodeString(blockID)
+	if blockID == "" || err != nil || len(decoded) > 64 {
+		return nil, newError(http.StatusBadRequest, "InvalidQueryParameterValue", "the `blockid` query parameter must be a base64 encoded string of up to 64 bytes")
+	}
+	if b != nil && !b.uncommitted {
+		if b.blobType != blobTypeBlock {
+			return nil, newError(http.StatusConflict, "InvalidBlobType", "the blob type is invalid for this operation")
+		}
+		if err := b.lease.checkWrite(r.leaseID(), now); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := encryptionKeySHA256(r); err != nil {
+		return nil, err
+	}
+
+	content := r.body
+	if r.Header.Get("x-ms-copy-source") != "" {
+		if content, err = s.readSourceRange(r); err != nil {
+			return nil, err
+		}
+	}
+	if len(content) > maxBlockBytes {
+		return nil, newError(http.StatusRequestEntityTooLarge, "RequestBodyTooLarge", "the block is larger than the maximum permitted size")
+	}
+	if err := checkTransactionalChecksums(r, content); err != nil {
+		return nil, err
+	}
+
+	if b == nil {
+		b = s.newBlob(r, blobTypeBlock, now)
+		b.uncommitted = true
+		c.blobs[r.blobName] = b
+	}
+
+	blocks := make([]block, 0, len(b.uncommittedBlocks)+1)
+	for _, existing := range b.uncommittedBlocks {
+		if existing.id != blockID {
+			blocks = append(blocks, existing)
+		}
+	}
+	b.uncommittedBlocks = append(blocks, block{
+		id:   blockID,
+		data: append([]byte{}, content...),
+	})
+
+	resp := newResponse(http.StatusCreated)
+	resp.header.Set("Content-MD5", contentMD5(content))
+	resp.header.Set("x-ms-content-crc64", checksum.CRC64.Compute(content))
+	resp.header.Set("x-ms-request-server-encrypted", "true")
+	return resp, nil
+}
+
+// readSourceRange returns the content of the Copy Source within the `x-ms-source-range` (if specified), confirming
+// this matches any checksums specified in the request
+func (s *Server) readSourceRange(r *request) ([]byte, error) {
+	sourceBlob, err := s.resolveCopySource(r)
+	if err != nil {
+		return nil, err
+	}
+	if sourceBlob.isArchived() {
+		return nil, blobArchived()
+	}
+	content := sourceBlob.content
+	if v := r.Header.Get("x-ms-source-range"); v != "" {
+		start, end, err := parseRange(v)
+		if err != nil {
+			return nil, err
+		}
+		if end < 0 || end >= int64(len(content)) {
+			end = int64(len(content)) - 1
+		}
+		if start > end {
+			return nil, newError(http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "the source range specified is invalid for the current size of the resource")
+		}
+		content = content[start : end+1]
+	}
+	if err := checkSourceChecksums(r, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+type blockListType string
+
+const (
+	blockListCommitted   blockListType = "Committed"
+	blockListLatest      blockListType = "Latest"
+	blockListUncommitted blockListType = "Uncommitted"
+)
+
+type blockListEntry struct {
+	listType blockListType
+	id       string
+}
+
+// parseBlockList parses the BlockList within the body of a Put Block List request, retaining the order of the
+// elements (since the Committed, Uncommitted and Latest elements can be interleaved)
+func parseBlockList(body []byte) ([]blockListEntry, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	entries := make([]blockListEntry, 0)
+	var current *blockListType
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, "InvalidXmlDocument", "parsing the block list: %+v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch v := blockListType(t.Name.Local); v {
+			case blockListCommitted, blockListLatest, blockListUncommitted:
+				current = &v
+			}
+		case xml.CharData:
+			if current != nil {
+				entries = append(entries, blockListEntry{
+					listType: *current,
+					id:       string(bytes.TrimSpace(t)),
+				})
+			}
+		case xml.EndElement:
+			current = nil
+		}
+	}
+	return entries, nil
+}
+
+func (s *Server) putBlockList(r *request, c *container, b *blob, now time.Time) (*response, error) {
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+	if b != nil && b.blobType != blobTypeBlock {
+		return nil, newError(http.StatusConflict, "InvalidBlobType", "the blob type is invalid for this operation")
+	}
+
+	keySHA256, err := encryptionKeySHA256(r)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := parseTags(r.Header)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := parseBlockList(r.body)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > maxBlocks {
+		return nil, newError(http.StatusConflict, "BlockCountExceedsLimit", "the committed block count cannot exceed the maximum limit of %d blocks", maxBlocks)
+	}
+
+	find := func(blocks []block, id string) *block {
+		for i := range blocks {
+			if blocks[i].id == id {
+				return &blocks[i]
+			}
+		}
+		return nil
+	}
+	var committed, uncommitted []block
+	if b != nil {
+		committed = b.committedBlocks
+		uncommitted = b.uncommittedBlocks
+	}
+
+	blocks := make([]block, 0, len(entries))
+	content := make([]byte, 0)
+	for _, entry := range entries {
+		var match *block
+		switch entry.listType {
+		case blockListCommitted:
+			match = find(committed, entry.id)
+		case blockListUncommitted:
+			match = find(uncommitted, entry.id)
+		case blockListLatest:
+			match = find(uncommitted, entry.id)
+			if match == nil {
+				match = find(committed, entry.id)
+			}
+		}
+		if match == nil {
+			return nil, newError(http.StatusBadRequest, "InvalidBlockList", "the specified block list is invalid")
+		}
+		blocks = append(blocks, *match)
+		content = append(content, match.data...)
+	}
+
+	replacement := s.newBlob(r, blobTypeBlock, now)
+	replacement.content = content
+	replacement.committedBlocks = blocks
+	replacement.encryptionKeySHA256 = keySHA256
+	replacement.tags = tags
+	if b != nil && !b.uncommitted {
+		replacement.accessTier = b.accessTier
+		replacement.accessTierInferred = b.accessTierInferred
+		replacement.accessTierChangeTime = b.accessTierChangeTime
+	}
+	if v := r.Header.Get("x-ms-access-tier"); v != "" {
+		replacement.accessTier = v
+		replacement.accessTierInferred = false
+		replacement.accessTierChangeTime = now
+	}
+	s.replaceBlob(c, r.blobName, b, replacement, now)
+
+	resp := newResponse(http.StatusCreated)
+	resp.header.Set("ETag", replacement.etag)
+	resp.header.Set("Last-Modified", formatTime(replacement.lastModified))
+	setIfNotEmpty(resp.header, "x-ms-version-id", replacement.versionId)
+	resp.header.Set("Content-MD5", contentMD5(content))
+	resp.header.Set("x-ms-request-server-encrypted", "true")
+	setIfNotEmpty(resp.header, "x-ms-encryption-key-sha256", keySHA256)
+	return resp, nil
+}
+
+type blockListResult struct {
+	XMLName           xml.Name         `xml:"BlockList"`
+	CommittedBlocks   *blockListBlocks `xml:"CommittedBlocks,omitempty"`
+	UncommittedBlocks *blockListBlocks `xml:"UncommittedBlocks,omitempty"`
+}
+
+type blockListBlocks struct {
+	Blocks []blockListBlock `xml:"Block"`
+}
+
+type blockListBlock struct {
+	Name string `xml:"Name"`
+	Size int64  `xml:"Size"`
+}
+
+func (s *Server) getBlockList(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil {
+		return nil, blobNotFound()
+	}
+	if err := b.lease.checkRead(r.leaseID(), now); err != nil {
+		return nil, err
+	}
+	return blockListResponse(r, b)
+}
+
+func blockListResponse(r *request, b *blob) (*response, error) {
+	if b.blobType != blobTypeBlock {
+		return nil, newError(http.StatusBadRequest, "InvalidBlobType", "the blob type is invalid for this operation")
+	}
+
+	toBlocks := func(input []block) *blockListBlocks {
+		out := &blockListBlocks{
+			Blocks: make([]blockListBlock, 0, len(input)),
+		}
+		for _, v := range input {
+			out.Blocks = append(out.Blocks, blockListBlock{
+				Name: v.id,
+				Size: int64(len(v.data)),
+			})
+		}
+		return out
+	}
+
+	result := blockListResult{}
+	listType := r.query.Get("blocklisttype")
+	if listType == "" {
+		listType = "committed"
+	}
+	switch listType {
+	case "all":
+		result.CommittedBlocks = toBlocks(b.committedBlocks)
+		result.UncommittedBlocks = toBlocks(b.uncommittedBlocks)
+	case "committed":
+		result.CommittedBlocks = toBlocks(b.committedBlocks)
+	case "uncommitted":
+		result.UncommittedBlocks = toBlocks(b.uncommittedBlocks)
+	default:
+		return nil, newError(http.StatusBadRequest, "InvalidQueryParameterValue", "unsupported block list type %q", listType)
+	}
+
+	resp := newResponse(http.StatusOK).withXML(result)
+	resp.header.Set("x-ms-blob-content-length", strconv.Itoa(len(b.content)))
+	if !b.uncommitted {
+		resp.header.Set("ETag", b.etag)
+		resp.header.Set("Last-Modified", formatTime(b.lastModified))
+	}
+	return resp, nil
+}
+
+func (s *Server) appendBlock(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if b.blobType != blobTypeAppend {
+		return nil, newError(http.StatusConflict, "InvalidBlobType", "the blob type is invalid for this operation")
+	}
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+	if err := checkEncryptionKey(r, b); err != nil {
+		return nil, err
+	}
+
+	if v := r.Header.Get("x-ms-blob-condition-appendpos"); v != "" {
+		position, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || position != int64(len(b.content)) {
+			return nil, newError(http.StatusPreconditionFailed, "AppendPositionConditionNotMet", "the append position condition specified was not met")
+		}
+	}
+	if v := r.Header.Get("x-ms-blob-condition-maxsize"); v != "" {
+		maxSize, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || int64(len(b.content)+len(r.body)) > maxSize {
+			return nil, newError(http.StatusPreconditionFailed, "MaxBlobSizeConditionNotMet", "the max blob size condition specified was not met")
+		}
+	}
+	if b.appendBlockCount >= maxBlocks {
+		return nil, newError(http.StatusConflict, "BlockCountExceedsLimit", "the committed block count cannot exceed the maximum limit of %d blocks", maxBlocks)
+	}
+	if err := checkTransactionalChecksums(r, r.body); err != nil {
+		return nil, err
+	}
+
+	offset := len(b.content)
+	b.content = append(b.content, r.body...)
+	b.appendBlockCount++
+	s.touch(b, now)
+
+	resp := newResponse(http.StatusCreated)
+	resp.header.Set("ETag", b.etag)
+	resp.header.Set("Last-Modified", formatTime(b.lastModified))
+	resp.header.Set("Content-MD5", contentMD5(r.body))
+	resp.header.Set("x-ms-blob-append-offset", strconv.Itoa(offset))
+	resp.header.Set("x-ms-blob-committed-block-count", strconv.Itoa(b.appendBlockCount))
+	resp.header.Set("x-ms-request-server-encrypted", "true")
+	return resp, nil
+}
+
+func (s *Server) putPage(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if b.blobType != blobTypePage {
+		return nil, newError(http.StatusConflict, "InvalidBlobType", "the blob type is invalid for this operation")
+	}
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+	if err := checkEncryptionKey(r, b); err != nil {
+		return nil, err
+	}
+	if err := checkSequenceNumberConditions(r, b); err != nil {
+		return nil, err
+	}
+
+	rangeHeader := r.Header.Get("x-ms-range")
+	if rangeHeader == "" {
+		rangeHeader = r.Header.Get("Range")
+	}
+	start, end, err := parseRange(rangeHeader)
+	if err != nil {
+		return nil, err
+	}
+	if start%pageSize != 0 || (end+1)%pageSize != 0 || end < start {
+		return nil, newError(http.StatusRequestedRangeNotSatisfiable, "InvalidPageRange", "the page range specified is invalid")
+	}
+	if end >= int64(len(b.content)) {
+		return nil, newError(http.StatusRequestedRangeNotSatisfiable, "InvalidPageRange", "the page range specified is invalid for the current size of the blob")
+	}
+
+	switch write := r.Header.Get("x-ms-page-write"); write {
+	case "update":
+		content := r.body
+		if r.Header.Get("x-ms-copy-source") != "" {
+			if content, err = s.readSourceRange(r); err != nil {
+				return nil, err
+			}
+		}
+		if int64(len(content)) != end-start+1 {
+			return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the length of the content must match the size of the page range")
+		}
+		if err := checkTransactionalChecksums(r, r.body); err != nil {
+			return nil, err
+		}
+		copy(b.content[start:end+1], content)
+		for page := start / pageSize; page <= end/pageSize; page++ {
+			b.pages[page] = true
+		}
+	case "clear":
+		for i := start; i <= end; i++ {
+			b.content[i] = 0
+		}
+		for page := start / pageSize; page <= end/pageSize; page++ {
+			delete(b.pages, page)
+		}
+	default:
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "unsupported page write %q", write)
+	}
+	s.touch(b, now)
+
+	resp := newResponse(http.StatusCreated)
+	resp.header.Set("ETag", b.etag)
+	resp.header.Set("Last-Modified", formatTime(b.lastModified))
+	resp.header.Set("x-ms-blob-sequence-number", strconv.FormatInt(b.sequenceNumber, 10))
+	if r.Header.Get("x-ms-page-write") == "update" {
+		resp.header.Set("Content-MD5", contentMD5(b.content[start:end+1]))
+	}
+	resp.header.Set("x-ms-request-server-encrypted", "true")
+	return resp, nil
+}
+
+func checkSequenceNumberConditions(r *request, b *blob) error {
+	failed := newError(http.StatusPreconditionFailed, "SequenceNumberConditionNotMet", "the sequence number condition specified was not met")
+	conditions := map[string]func(v int64) bool{
+		"x-ms-if-sequence-number-eq": func(v int64) bool { return b.sequenceNumber == v },
+		"x-ms-if-sequence-number-le": func(v int64) bool { return b.sequenceNumber <= v },
+		"x-ms-if-sequence-number-lt": func(v int64) bool { return b.sequenceNumber < v },
+	}
+	for header, condition := range conditions {
+		raw := r.Header.Get(header)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return newError(http.StatusBadRequest, "InvalidHeaderValue", "parsing %q: %+v", header, err)
+		}
+		if !condition(v) {
+			return failed
+		}
+	}
+	return nil
+}
+
+type pageList struct {
+	XMLName xml.Name `xml:"PageList"`
+
+	// Ranges contains both the PageRange and ClearRange elements, since these are interleaved
+	Ranges     []pageListRange
+	NextMarker string `xml:"NextMarker"`
+}
+
+type pageListRange struct {
+	XMLName xml.Name
+	Start   int64 `xml:"Start"`
+	End     int64 `xml:"End"`
+}
+
+func (s *Server) getPageRanges(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := b.lease.checkRead(r.leaseID(), now); err != nil {
+		return nil, err
+	}
+	return s.pageRangesResponse(r, b, b)
+}
+
+// pageRangesResponse returns the Page Ranges of `b` (the Blob, or a Snapshot/Version of `base`) - or when a previous
+// Snapshot is specified, the Page Ranges which were changed (or cleared) since that Snapshot
+func (s *Server) pageRangesResponse(r *request, b, base *blob) (*response, error) {
+	if b.blobType != blobTypePage {
+		return nil, newError(http.StatusBadRequest, "InvalidBlobType", "the blob type is invalid for this operation")
+	}
+
+	rangeStart := int64(0)
+	rangeEnd := int64(len(b.content)) - 1
+	rangeHeader := r.Header.Get("x-ms-range")
+	if rangeHeader == "" {
+		rangeHeader = r.Header.Get("Range")
+	}
+	if rangeHeader != "" {
+		start, end, err := parseRange(rangeHeader)
+		if err != nil {
+			return nil, err
+		}
+		rangeStart = start
+		if end >= 0 && end < rangeEnd {
+			rangeEnd = end
+		}
+	}
+
+	previous, err := s.previousSnapshot(r, base)
+	if err != nil {
+		return nil, err
+	}
+
+	// the pages which are valid - or, when diffing, the pages which have changed (true) or been cleared (false)
+	changes := make(map[int64]bool, len(b.pages))
+	for page := range b.pages {
+		if previous == nil || !previous.pages[page] || !bytes.Equal(pageContent(b, page), pageContent(previous, page)) {
+			changes[page] = true
+		}
+	}
+	if previous != nil {
+		for page := range previous.pages {
+			if !b.pages[page] {
+				changes[page] = false
+			}
+		}
+	}
+	pages := make([]int64, 0, len(changes))
+	for page := range changes {
+		pages = append(pages, page)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i] < pages[j] })
+
+	ranges := make([]pageListRange, 0)
+	for _, page := range pages {
+		start := page * pageSize
+		end := start + pageSize - 1
+		if end < rangeStart || start > rangeEnd {
+			continue
+		}
+		if start < rangeStart {
+			start = rangeStart
+		}
+		if end > rangeEnd {
+			end = rangeEnd
+		}
+		name := "PageRange"
+		if !changes[page] {
+			name = "ClearRange"
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].End+1 == start && ranges[n-1].XMLName.Local == name {
+			ranges[n-1].End = end
+			continue
+		}
+		ranges = append(ranges, pageListRange{
+			XMLName: xml.Name{Local: name},
+			Start:   start,
+			End:     end,
+		})
+	}
+
+	// the Marker is the offset of the first range to return
+	if v := r.query.Get("marker"); v != "" {
+		marker, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, "InvalidQueryParameterValue", "the `marker` query parameter is not valid")
+		}
+		for len(ranges) > 0 && ranges[0].Start < marker {
+			ranges = ranges[1:]
+		}
+	}
+	result := pageList{
+		Ranges: ranges,
+	}
+	if v := r.query.Get("maxresults"); v != "" {
+		maxResults, err := strconv.Atoi(v)
+		if err != nil || maxResults < 1 {
+			return nil, newError(http.StatusBadRequest, "InvalidQueryParameterValue", "the `maxresults` query parameter must be a positive integer")
+		}
+		if len(ranges) > maxResults {
+			result.Ranges = ranges[:maxResults]
+			result.NextMarker = strconv.FormatInt(ranges[maxResults].Start, 10)
+		}
+	}
+
+	resp := newResponse(http.StatusOK).withXML(result)
+	resp.header.Set("ETag", b.etag)
+	resp.header.Set("Last-Modified", formatTime(b.lastModified))
+	resp.header.Set("x-ms-blob-content-length", strconv.Itoa(len(b.content)))
+	return resp, nil
+}
+
+// previousSnapshot returns the Snapshot of `base` specified using either the `prevsnapshot` query parameter or
+// the `x-ms-previous-snapshot-url` header (if any) - which the Page Ranges should be compared against
+func (s *Server) previousSnapshot(r *request, base *blob) (*blob, error) {
+	if v := r.query.Get("prevsnapshot"); v != "" {
+		previous, ok := base.snapshots[v]
+		if !ok {
+			return nil, newError(http.StatusNotFound, "PreviousSnapshotNotFound", "the previous snapshot %q does not exist", v)
+		}
+		return previous, nil
+	}
+	if v := r.Header.Get("x-ms-previous-snapshot-url"); v != "" {
+		uri, err := url.Parse(v)
+		if err != nil || uri.Query().Get("snapshot") == "" {
+			return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the `x-ms-previous-snapshot-url` header must reference a snapshot")
+		}
+		previous, err := s.resolveLocalBlob(uri, v)
+		if err != nil {
+			return nil, newError(http.StatusNotFound, "PreviousSnapshotNotFound", "the previous snapshot %q does not exist", v)
+		}
+		return previous, nil
+	}
+	return nil, nil
+}
+
+func pageContent(b *blob, page int64) []byte {
+	start := page * pageSize
+	if start+pageSize > int64(len(b.content)) {
+		return nil
+	}
+	return b.content[start : start+pageSize]
+}
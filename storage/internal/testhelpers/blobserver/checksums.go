@@ -0,0 +1,49 @@
+package blobserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
+)
+
+// maxRangeChecksumBytes is the largest range for which a transactional checksum can be requested
+const maxRangeChecksumBytes = 4 * 1024 * 1024
+
+// checkTransactionalChecksums confirms that the transactional MD5/CRC64 checksums specified in the request
+// (if any) match the content which was received
+func checkTransactionalChecksums(r *request, content []byte) error {
+	if v := r.Header.Get("Content-MD5"); v != "" && v != checksum.MD5.Compute(content) {
+		return newError(http.StatusBadRequest, "Md5Mismatch", "the MD5 value specified in the request did not match the MD5 value calculated by the server")
+	}
+	if v := r.Header.Get("x-ms-content-crc64"); v != "" && v != checksum.CRC64.Compute(content) {
+		return newError(http.StatusBadRequest, "Crc64Mismatch", "the CRC64 value specified in the request did not match the CRC64 value calculated by the server")
+	}
+	return nil
+}
+
+// checkSourceChecksums confirms that the MD5/CRC64 checksums of the Copy Source specified in the request (if any)
+// match the content which was read from the Copy Source
+func checkSourceChecksums(r *request, content []byte) error {
+	if v := r.Header.Get("x-ms-source-content-md5"); v != "" && v != checksum.MD5.Compute(content) {
+		return newError(http.StatusBadRequest, "Md5Mismatch", "the MD5 value specified in the request did not match the MD5 value calculated by the server")
+	}
+	if v := r.Header.Get("x-ms-source-content-crc64"); v != "" && v != checksum.CRC64.Compute(content) {
+		return newError(http.StatusBadRequest, "Crc64Mismatch", "the CRC64 value specified in the request did not match the CRC64 value calculated by the server")
+	}
+	return nil
+}
+
+// writeRangeChecksums returns the transactional MD5/CRC64 checksum of the range being returned, when requested
+func writeRangeChecksums(r *request, resp *response) error {
+	for _, algorithm := range []checksum.Algorithm{checksum.MD5, checksum.CRC64} {
+		if !strings.EqualFold(r.Header.Get(algorithm.RangeHeader()), "true") {
+			continue
+		}
+		if len(resp.body) > maxRangeChecksumBytes {
+			return newError(http.StatusBadRequest, "OutOfRangeInput", "a checksum can only be requested for a range of at most 4MB")
+		}
+		resp.header.Set(algorithm.Header(), algorithm.Compute(resp.body))
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package blobserver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type container struct {
+	name         string
+	accessLevel  string
+	metaData     map[string]string
+	etag         string
+	lastModified time.Time
+	lease        lease
+	acl          []byte
+
+	defaultEncryptionScope      string
+	denyEncryptionScopeOverride bool
+
+	blobs map[string]*blob
+}
+
+func (c *container) writeHeaders(header http.Header) {
+	header.Set("ETag", c.etag)
+	header.Set("Last-Modified", formatTime(c.lastModified))
+	if c.accessLevel != "" {
+		header.Set("x-ms-blob-public-access", c.accessLevel)
+	}
+	header.Set("x-ms-default-encryption-scope", c.defaultEncryptionScope)
+	header.Set("x-ms-deny-encryption-scope-override", strconv.FormatBool(c.denyEncryptionScopeOverride))
+	header.Set("x-ms-has-immutability-policy", "false")
+	header.Set("x-ms-has-legal-hold", "false")
+	writeMetaData(header, c.metaData)
+	c.lease.writeHeaders(header)
+}
+
+func (s *Server) handleContainer(r *request) (*response, error) {
+	if r.query.Get("restype") != "container" {
+		return nil, newError(http.StatusBadRequest, "InvalidQueryParameterValue", "expected the `restype` query parameter to be `container`")
+	}
+	if strings.ToLower(r.containerName) != r.containerName {
+		return nil, newError(http.StatusBadRequest, "InvalidResourceName", "the specified resource name contains invalid characters")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+
+	if r.Method == http.MethodPut && r.comp() == "" {
+		if _, exists := s.containers[r.containerName]; exists {
+			return nil, newError(http.StatusConflict, "ContainerAlreadyExists", "the specified container already exists")
+		}
+		encryptionScope := r.Header.Get("x-ms-default-encryption-scope")
+		if encryptionScope == "" {
+			encryptionScope = "$account-encryption-key"
+		}
+		c := &container{
+			name:                        r.containerName,
+			accessLevel:                 r.Header.Get("x-ms-blob-public-access"),
+			metaData:                    parseMetaData(r.Header),
+			etag:                        s.nextETag(),
+			lastModified:                now,
+			lease:                       lease{state: leaseStateAvailable},
+			defaultEncryptionScope:      encryptionScope,
+			denyEncryptionScopeOverride: strings.EqualFold(r.Header.Get("x-ms-deny-encryption-scope-override"), "true"),
+			blobs:                       map[string]*blob{},
+		}
+		s.containers[r.containerName] = c
+
+		resp := newResponse(http.StatusCreated)
+		resp.header.Set("ETag", c.etag)
+		resp.header.Set("Last-Modified", formatTime(c.lastModified))
+		return resp, nil
+	}
+
+	c, exists := s.containers[r.containerName]
+	if !exists {
+		return nil, newError(http.StatusNotFound, "ContainerNotFound", "the specified container does not exist")
+	}
+	c.lease.refresh(now)
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		switch r.comp() {
+		case "":
+			if err := c.lease.checkRead(r.leaseID(), now); err != nil {
+				return nil, err
+			}
+			resp := newResponse(http.StatusOK)
+			c.writeHeaders(resp.header)
+			return resp, nil
+
+		case "metadata":
+			resp := newResponse(http.StatusOK)
+			resp.header.Set("ETag", c.etag)
+			resp.header.Set("Last-Modified", formatTime(c.lastModified))
+			writeMetaData(resp.header, c.metaData)
+			return resp, nil
+
+		case "acl":
+			resp := newResponse(http.StatusOK)
+			resp.header.Set("ETag", c.etag)
+			resp.header.Set("Last-Modified", formatTime(c.lastModified))
+			if c.accessLevel != "" {
+				resp.header.Set("x-ms-blob-public-access", c.accessLevel)
+			}
+			resp.header.Set("Content-Type", "application/xml")
+			resp.body = c.acl
+			if len(resp.body) == 0 {
+				resp.body = []byte(xml.Header + "<SignedIdentifiers />")
+			}
+			return resp, nil
+
+		case "list":
+			return s.listBlobs(r, c)
+		}
+
+	case http.MethodPut:
+		switch r.comp() {
+		case "metadata":
+			if err := c.lease.checkRead(r.leaseID(), now); err != nil {
+				return nil, err
+			}
+			c.metaData = parseMetaData(r.Header)
+			return s.touchContainer(c, now, http.StatusOK), nil
+
+		case "acl":
+			if err := c.lease.checkRead(r.leaseID(), now); err != nil {
+				return nil, err
+			}
+			c.accessLevel = r.Header.Get("x-ms-blob-public-access")
+			c.acl = r.body
+			return s.touchContainer(c, now, http.StatusOK), nil
+
+		case "lease":
+			return c.lease.apply(r, now)
+		}
+
+	case http.MethodDelete:
+		if r.comp() == "" {
+			if err := c.lease.checkWrite(r.leaseID(), now); err != nil {
+				return nil, err
+			}
+			delete(s.containers, r.containerName)
+			return newResponse(http.StatusAccepted), nil
+		}
+	}
+
+	return nil, newError(http.StatusBadRequest, "UnsupportedHttpVerb", "the operation %s with comp %q isn't supported for containers", r.Method, r.comp())
+}
+
+func (s *Server) touchContainer(c *container, now time.Time, status int) *response {
+	c.etag = s.nextETag()
+	c.lastModified = now
+
+	resp := newResponse(status)
+	resp.header.Set("ETag", c.etag)
+	resp.header.Set("Last-Modified", formatTime(c.lastModified))
+	return resp
+}
+
+type enumerationResults struct {
+	XMLName         xml.Name       `xml:"EnumerationResults"`
+	ServiceEndpoint string         `xml:"ServiceEndpoint,attr"`
+	ContainerName   string         `xml:"ContainerName,attr"`
+	Prefix          string         `xml:"Prefix,omitempty"`
+	Marker          string         `xml:"Marker,omitempty"`
+	MaxResults      int            `xml:"MaxResults,omitempty"`
+	Delimiter       string         `xml:"Delimiter,omitempty"`
+	Blobs           listBlobsItems `xml:"Blobs"`
+	NextMarker      string         `xml:"NextMarker"`
+}
+
+type listBlobsItems struct {
+	Blobs    []listBlobItem   `xml:"Blob"`
+	Prefixes []listBlobPrefix `xml:"BlobPrefix"`
+}
+
+type listBlobPrefix struct {
+	Name string `xml:"Name"`
+}
+
+type listBlobItem struct {
+	Name             string             `xml:"Name"`
+	Snapshot         string             `xml:"Snapshot,omitempty"`
+	VersionId        string             `xml:"VersionId,omitempty"`
+	IsCurrentVersion string             `xml:"IsCurrentVersion,omitempty"`
+	Properties       listBlobProperties `xml:"Properties"`
+	MetaData         *listBlobMetaData  `xml:"Metadata,omitempty"`
+}
+
+type listBlobProperties struct {
+	CreationTime          string `xml:"Creation-Time"`
+	LastModified          string `xml:"Last-Modified"`
+	ETag                  string `xml:"Etag"`
+	ContentLength         int64  `xml:"Content-Length"`
+	ContentType           string `xml:"Content-Type"`
+	ContentEncoding       string `xml:"Content-Encoding"`
+	ContentLanguage       string `xml:"Content-Language"`
+	ContentMD5            string `xml:"Content-MD5"`
+	CacheControl          string `xml:"Cache-Control"`
+	ContentDisposition    string `xml:"Content-Disposition"`
+	BlobSequenceNumber    string `xml:"x-ms-blob-sequence-number,omitempty"`
+	BlobType              string `xml:"BlobType"`
+	AccessTier            string `xml:"AccessTier,omitempty"`
+	AccessTierInferred    string `xml:"AccessTierInferred,omitempty"`
+	ArchiveStatus         string `xml:"ArchiveStatus,omitempty"`
+	RehydratePriority     string `xml:"RehydratePriority,omitempty"`
+	LeaseStatus           string `xml:"LeaseStatus"`
+	LeaseState            string `xml:"LeaseState"`
+	LeaseDuration         string `xml:"LeaseDuration,omitempty"`
+	CopyID                string `xml:"CopyId,omitempty"`
+	CopyStatus            string `xml:"CopyStatus,omitempty"`
+	CopySource            string `xml:"CopySource,omitempty"`
+	CopyProgress          string `xml:"CopyProgress,omitempty"`
+	CopyCompletionTime    string `xml:"CopyCompletionTime,omitempty"`
+	CopyStatusDescription string `xml:"CopyStatusDescription,omitempty"`
+	IncrementalCopy       string `xml:"IncrementalCopy,omitempty"`
+	ServerEncrypted       bool   `xml:"ServerEncrypted"`
+
+	ImmutabilityPolicyUntilDate string `xml:"ImmutabilityPolicyUntilDate,omitempty"`
+	ImmutabilityPolicyMode      string `xml:"ImmutabilityPolicyMode,omitempty"`
+	LegalHold                   string `xml:"LegalHold,omitempty"`
+}
+
+type listBlobMetaData struct {
+	Items []listBlobMetaDataItem
+}
+
+type listBlobMetaDataItem struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+func (s *Server) listBlobs(r *request, c *container) (*response, error) {
+	prefix := r.query.Get("prefix")
+	delimiter := r.query.Get("delimiter")
+	marker := r.query.Get("marker")
+	maxResults := 5000
+	if v := r.query.Get("maxresults"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil || i <= 0 {
+			return nil, newError(http.StatusBadRequest, "OutOfRangeQueryParameterValue", "the `maxresults` query parameter must be greater than 0")
+		}
+		maxResults = i
+	}
+	include := map[string]bool{}
+	for _, v := range strings.Split(r.query.Get("include"), ",") {
+		include[strings.ToLower(strings.TrimSpace(v))] = true
+	}
+
+	names := make([]string, 0, len(c.blobs))
+	for name, b := range c.blobs {
+		// deleted Blobs are retained when Versioning is enabled, such that their previous Versions can be listed
+		if b.uncommitted && !include["uncommittedblobs"] && (!include["versions"] || len(b.versions) == 0) {
+			continue
+		}
+		if strings.HasPrefix(name, prefix) && name >= marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	now := s.now()
+	result := enumerationResults{
+		ServiceEndpoint: fmt.Sprintf("%s/", strings.TrimSuffix(s.BaseUri(), "/")),
+		ContainerName:   c.name,
+		Prefix:          prefix,
+		Marker:          marker,
+		MaxResults:      maxResults,
+		Delimiter:       delimiter,
+	}
+	seenPrefixes := map[string]bool{}
+	count := 0
+	for _, name := range names {
+		if count >= maxResults {
+			result.NextMarker = name
+			break
+		}
+
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				blobPrefix := name[:len(prefix)+i+len(delimiter)]
+				if !seenPrefixes[blobPrefix] {
+					seenPrefixes[blobPrefix] = true
+					result.Blobs.Prefixes = append(result.Blobs.Prefixes, listBlobPrefix{Name: blobPrefix})
+					count++
+				}
+				continue
+			}
+		}
+
+		b := c.blobs[name]
+		b.refresh(now)
+		if include["snapshots"] {
+			for _, snapshotId := range b.sortedSnapshotIds() {
+				result.Blobs.Blobs = append(result.Blobs.Blobs, b.snapshots[snapshotId].listItem(name, snapshotId, include))
+			}
+		}
+		if include["versions"] {
+			for _, versionId := range b.sortedVersionIds() {
+				result.Blobs.Blobs = append(result.Blobs.Blobs, b.versions[versionId].listItem(name, "", include))
+			}
+		}
+		if !b.uncommitted || include["uncommittedblobs"] {
+			result.Blobs.Blobs = append(result.Blobs.Blobs, b.listItem(name, "", include))
+		}
+		count++
+	}
+
+	return newResponse(http.StatusOK).withXML(result), nil
+}
@@ -0,0 +1,330 @@
+package blobserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tombuildsstuff/giovanni/storage/checksum"
+)
+
+// resolveCopySource returns the Blob referenced by the `x-ms-copy-source` URI of the request - Blobs (and Snapshots/Versions)
+// within this Server are resolved directly, whereas any other URI is retrieved over HTTP using the
+// `x-ms-copy-source-authorization` header (if specified). The caller must hold the lock.
+func (s *Server) resolveCopySource(r *request) (*blob, error) {
+	source := r.Header.Get("x-ms-copy-source")
+	uri, err := url.Parse(source)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "parsing the copy source %q: %+v", source, err)
+	}
+	authorization := r.Header.Get("x-ms-copy-source-authorization")
+	if authorization != "" && !strings.HasPrefix(authorization, "Bearer ") {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the `x-ms-copy-source-authorization` header must use the Bearer scheme")
+	}
+
+	serverUri, _ := url.Parse(s.server.URL)
+	if uri.Host != serverUri.Host {
+		return fetchCopySource(source, authorization)
+	}
+	if authorization != "" && authorization != fmt.Sprintf("Bearer %s", s.BearerToken) {
+		return nil, newError(http.StatusUnauthorized, "CannotVerifyCopySource", "the copy source authorization is not valid for this account")
+	}
+	return s.resolveLocalBlob(uri, source)
+}
+
+// resolveLocalBlob returns the Blob (or Snapshot/Version) within this Server referenced by `uri`. The caller must hold the lock.
+func (s *Server) resolveLocalBlob(uri *url.URL, source string) (*blob, error) {
+	prefix := fmt.Sprintf("/%s/", s.AccountName)
+	if !strings.HasPrefix(uri.Path, prefix) {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the copy source %q is not within the account %q", source, s.AccountName)
+	}
+	containerName, blobName, _ := strings.Cut(strings.TrimPrefix(uri.Path, prefix), "/")
+	notFound := newError(http.StatusNotFound, "CannotVerifyCopySource", "the specified copy source %q does not exist", source)
+
+	c, ok := s.containers[containerName]
+	if !ok {
+		return nil, notFound
+	}
+	b, ok := c.blobs[blobName]
+	if ok {
+		// complete any rehydration which has finished as of the time of the current request
+		b.refresh(s.lastTime)
+	}
+	if versionId := uri.Query().Get("versionid"); versionId != "" {
+		version := b.lookupVersion(versionId)
+		if version == nil {
+			return nil, notFound
+		}
+		return version, nil
+	}
+	if !ok || b.uncommitted {
+		return nil, notFound
+	}
+	if snapshotId := uri.Query().Get("snapshot"); snapshotId != "" {
+		snapshot, ok := b.snapshots[snapshotId]
+		if !ok {
+			return nil, notFound
+		}
+		return snapshot, nil
+	}
+	return b, nil
+}
+
+func fetchCopySource(source, authorization string) (*blob, error) {
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "CannotVerifyCopySource", "building the request for the copy source %q: %+v", source, err)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+		req.Header.Set("x-ms-version", "2023-11-03")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "CannotVerifyCopySource", "retrieving the copy source %q: %+v", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError(resp.StatusCode, "CannotVerifyCopySource", "retrieving the copy source %q returned status %d", source, resp.StatusCode)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "CannotVerifyCopySource", "reading the copy source %q: %+v", source, err)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &blob{
+		blobType:    blobTypeBlock,
+		content:     content,
+		metaData:    map[string]string{},
+		contentType: contentType,
+		contentMD5:  contentMD5(content),
+		accessTier:  "Hot",
+		pages:       map[int64]bool{},
+	}, nil
+}
+
+const (
+	// maxSyncCopyBytes is the largest Blob which can be copied using Copy Blob From URL
+	maxSyncCopyBytes = 256 * 1024 * 1024
+
+	// maxPutBlobFromURLBytes is the largest Blob which can be written using Put Blob From URL
+	maxPutBlobFromURLBytes = 5000 * 1024 * 1024
+)
+
+// copyBlob performs a Copy Blob operation - which completes synchronously, rather than being left pending. When the
+// `x-ms-requires-sync` header is specified this is a Copy Blob From URL operation, which is limited to Block Blobs of up to 256 MiB.
+func (s *Server) copyBlob(r *request, c *container, b *blob, now time.Time) (*response, error) {
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+	tags, err := parseTags(r.Header)
+	if err != nil {
+		return nil, err
+	}
+	source := r.Header.Get("x-ms-copy-source")
+	sourceBlob, err := s.resolveCopySource(r)
+	if err != nil {
+		return nil, err
+	}
+	if v := r.Header.Get("x-ms-source-lease-id"); v != "" {
+		if err := sourceBlob.lease.checkRead(v, now); err != nil {
+			return nil, err
+		}
+	}
+	requiresSync := strings.EqualFold(r.Header.Get("x-ms-requires-sync"), "true")
+
+	// an archived source can only be copied into an online tier, where the copy remains pending until the
+	// destination has been rehydrated - which isn't possible for a synchronous copy
+	rehydrateTier := ""
+	rehydratePriority := ""
+	if sourceBlob.isArchived() {
+		if requiresSync {
+			return nil, blobArchived()
+		}
+		for _, v := range []string{"Hot", "Cool", "Cold"} {
+			if strings.EqualFold(v, r.Header.Get("x-ms-access-tier")) {
+				rehydrateTier = v
+			}
+		}
+		if rehydrateTier == "" {
+			return nil, blobArchived()
+		}
+		if rehydratePriority, err = parseRehydratePriority(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if requiresSync {
+		if sourceBlob.blobType != blobTypeBlock {
+			return nil, newError(http.StatusConflict, "CannotVerifyCopySource", "the source of a synchronous copy must be a block blob")
+		}
+		if len(sourceBlob.content) > maxSyncCopyBytes {
+			return nil, newError(http.StatusConflict, "CannotVerifyCopySource", "the source of a synchronous copy must be at most %d bytes", maxSyncCopyBytes)
+		}
+		if err := checkSourceChecksums(r, sourceBlob.content); err != nil {
+			return nil, err
+		}
+	}
+
+	replacement := sourceBlob.clone()
+	replacement.creationTime = now
+	replacement.tags = tags
+	if metaData := parseMetaData(r.Header); len(metaData) > 0 {
+		replacement.metaData = metaData
+	}
+	if replacement.blobType == blobTypeBlock {
+		replacement.accessTier = "Hot"
+		replacement.accessTierInferred = true
+		replacement.accessTierChangeTime = time.Time{}
+		replacement.rehydrateTier = ""
+		if v := r.Header.Get("x-ms-access-tier"); v != "" {
+			replacement.accessTier = v
+			replacement.accessTierInferred = false
+			replacement.accessTierChangeTime = now
+		}
+	}
+	s.completeCopy(replacement, source, now)
+	if rehydrateTier != "" {
+		replacement.accessTier = "Archive"
+		replacement.rehydrateTier = rehydrateTier
+		replacement.rehydratePriority = rehydratePriority
+		replacement.rehydratedAt = now.Add(s.RehydrationDelay)
+		replacement.copyStatus = "pending"
+		replacement.copyProgress = fmt.Sprintf("0/%d", len(replacement.content))
+		replacement.copyCompletionTime = time.Time{}
+	}
+	s.replaceBlob(c, r.blobName, b, replacement, now)
+
+	resp := newResponse(http.StatusAccepted)
+	resp.header.Set("ETag", replacement.etag)
+	resp.header.Set("Last-Modified", formatTime(replacement.lastModified))
+	setIfNotEmpty(resp.header, "x-ms-version-id", replacement.versionId)
+	resp.header.Set("x-ms-copy-id", replacement.copyID)
+	resp.header.Set("x-ms-copy-status", replacement.copyStatus)
+	if requiresSync {
+		resp.header.Set("Content-MD5", contentMD5(replacement.content))
+		resp.header.Set("x-ms-content-crc64", checksum.CRC64.Compute(replacement.content))
+	}
+	return resp, nil
+}
+
+// putBlobFromURL performs a Put Blob From URL operation, replacing the Block Blob with the content of the Copy Source -
+// where the properties of the Copy Source are retained unless `x-ms-copy-source-blob-properties` is false, or these are
+// specified in the request
+func (s *Server) putBlobFromURL(r *request, c *container, b *blob, now time.Time) (*response, error) {
+	if r.Header.Get("x-ms-blob-type") != blobTypeBlock {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "only block blobs can be written from a URL")
+	}
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+	keySHA256, err := encryptionKeySHA256(r)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := parseTags(r.Header)
+	if err != nil {
+		return nil, err
+	}
+	sourceBlob, err := s.resolveCopySource(r)
+	if err != nil {
+		return nil, err
+	}
+	if sourceBlob.isArchived() {
+		return nil, blobArchived()
+	}
+	if len(sourceBlob.content) > maxPutBlobFromURLBytes {
+		return nil, newError(http.StatusConflict, "CannotVerifyCopySource", "the copy source must be at most %d bytes", maxPutBlobFromURLBytes)
+	}
+	if err := checkSourceChecksums(r, sourceBlob.content); err != nil {
+		return nil, err
+	}
+
+	replacement := s.newBlob(r, blobTypeBlock, now)
+	replacement.content = append([]byte{}, sourceBlob.content...)
+	replacement.committedBlocks = nil
+	replacement.encryptionKeySHA256 = keySHA256
+	replacement.tags = tags
+	if !strings.EqualFold(r.Header.Get("x-ms-copy-source-blob-properties"), "false") {
+		inherit := func(value *string, header, sourceValue string) {
+			if r.Header.Get(header) == "" {
+				*value = sourceValue
+			}
+		}
+		inherit(&replacement.cacheControl, "x-ms-blob-cache-control", sourceBlob.cacheControl)
+		inherit(&replacement.contentDisposition, "x-ms-blob-content-disposition", sourceBlob.contentDisposition)
+		inherit(&replacement.contentEncoding, "x-ms-blob-content-encoding", sourceBlob.contentEncoding)
+		inherit(&replacement.contentLanguage, "x-ms-blob-content-language", sourceBlob.contentLanguage)
+		inherit(&replacement.contentType, "x-ms-blob-content-type", sourceBlob.contentType)
+	}
+	if replacement.contentMD5 == "" {
+		replacement.contentMD5 = contentMD5(replacement.content)
+	}
+	s.replaceBlob(c, r.blobName, b, replacement, now)
+
+	resp := newResponse(http.StatusCreated)
+	resp.header.Set("ETag", replacement.etag)
+	resp.header.Set("Last-Modified", formatTime(replacement.lastModified))
+	setIfNotEmpty(resp.header, "x-ms-version-id", replacement.versionId)
+	resp.header.Set("Content-MD5", contentMD5(replacement.content))
+	resp.header.Set("x-ms-content-crc64", checksum.CRC64.Compute(replacement.content))
+	resp.header.Set("x-ms-request-server-encrypted", "true")
+	setIfNotEmpty(resp.header, "x-ms-encryption-key-sha256", keySHA256)
+	return resp, nil
+}
+
+// incrementalCopyBlob performs an Incremental Copy from a Snapshot of a Page Blob, which (as with copyBlob)
+// completes synchronously
+func (s *Server) incrementalCopyBlob(r *request, c *container, b *blob, now time.Time) (*response, error) {
+	if err := s.checkBlobWrite(r, b, now); err != nil {
+		return nil, err
+	}
+	if b != nil && !b.uncommitted && !b.incrementalCopy {
+		return nil, newError(http.StatusConflict, "InvalidBlobType", "the destination of an incremental copy must be an incremental copy blob")
+	}
+
+	source := r.Header.Get("x-ms-copy-source")
+	uri, err := url.Parse(source)
+	if err != nil || uri.Query().Get("snapshot") == "" {
+		return nil, newError(http.StatusBadRequest, "InvalidSourceBlobUrl", "the source of an incremental copy must be a snapshot of a page blob")
+	}
+	sourceBlob, err := s.resolveCopySource(r)
+	if err != nil {
+		return nil, err
+	}
+	if sourceBlob.blobType != blobTypePage {
+		return nil, newError(http.StatusConflict, "InvalidBlobType", "the source of an incremental copy must be a page blob")
+	}
+
+	replacement := sourceBlob.clone()
+	replacement.creationTime = now
+	replacement.incrementalCopy = true
+	s.completeCopy(replacement, source, now)
+	s.replaceBlob(c, r.blobName, b, replacement, now)
+
+	resp := newResponse(http.StatusAccepted)
+	resp.header.Set("ETag", replacement.etag)
+	resp.header.Set("Last-Modified", formatTime(replacement.lastModified))
+	setIfNotEmpty(resp.header, "x-ms-version-id", replacement.versionId)
+	resp.header.Set("x-ms-copy-id", replacement.copyID)
+	resp.header.Set("x-ms-copy-status", replacement.copyStatus)
+	return resp, nil
+}
+
+func (s *Server) completeCopy(b *blob, source string, now time.Time) {
+	b.copyID = uuid.New().String()
+	b.copySource = source
+	b.copyStatus = "success"
+	b.copyStatusDescription = ""
+	b.copyProgress = fmt.Sprintf("%d/%d", len(b.content), len(b.content))
+	b.copyCompletionTime = now
+	s.touch(b, now)
+}
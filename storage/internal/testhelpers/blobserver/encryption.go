@@ -0,0 +1,52 @@
+package blobserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+)
+
+// encryptionKeySHA256 validates the Customer-Provided Key specified in the request (if any), returning the
+// SHA-256 hash of the key - or an empty string when no key was specified
+func encryptionKeySHA256(r *request) (string, error) {
+	key := r.Header.Get("x-ms-encryption-key")
+	if key == "" {
+		if r.Header.Get("x-ms-encryption-key-sha256") != "" || r.Header.Get("x-ms-encryption-algorithm") != "" {
+			return "", newError(http.StatusBadRequest, "MissingRequiredHeader", "the `x-ms-encryption-key` header must be specified along with the `x-ms-encryption-key-sha256` and `x-ms-encryption-algorithm` headers")
+		}
+		return "", nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(decoded) != 32 {
+		return "", newError(http.StatusBadRequest, "InvalidHeaderValue", "the `x-ms-encryption-key` header must be a base64 encoded 256-bit key")
+	}
+	if algorithm := r.Header.Get("x-ms-encryption-algorithm"); algorithm != "AES256" {
+		return "", newError(http.StatusBadRequest, "InvalidHeaderValue", "unsupported encryption algorithm %q", algorithm)
+	}
+	hash := sha256.Sum256(decoded)
+	keySHA256 := base64.StdEncoding.EncodeToString(hash[:])
+	if r.Header.Get("x-ms-encryption-key-sha256") != keySHA256 {
+		return "", newError(http.StatusBadRequest, "InvalidHeaderValue", "the `x-ms-encryption-key-sha256` header doesn't match the SHA-256 hash of the key")
+	}
+	return keySHA256, nil
+}
+
+// checkEncryptionKey confirms that the Customer-Provided Key specified in the request matches the key which
+// was used to encrypt the Blob, since Blobs encrypted using a Customer-Provided Key can't be read or updated without it
+func checkEncryptionKey(r *request, b *blob) error {
+	keySHA256, err := encryptionKeySHA256(r)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case b.encryptionKeySHA256 == "" && keySHA256 != "":
+		return newError(http.StatusConflict, "BlobDoesNotUseCustomerSpecifiedEncryption", "the blob is not encrypted with customer specified encryption")
+	case b.encryptionKeySHA256 != "" && keySHA256 == "":
+		return newError(http.StatusConflict, "BlobUsesCustomerSpecifiedEncryption", "the blob is encrypted with customer specified encryption, but it was not provided")
+	case b.encryptionKeySHA256 != keySHA256:
+		return newError(http.StatusConflict, "BlobCustomerSpecifiedEncryptionMismatch", "the customer specified encryption does not match the encryption used to encrypt the blob")
+	}
+	return nil
+}
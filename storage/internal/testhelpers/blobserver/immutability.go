@@ -0,0 +1,101 @@
+package blobserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	immutabilityPolicyModeLocked   = "Locked"
+	immutabilityPolicyModeUnlocked = "Unlocked"
+)
+
+// checkImmutable returns an error if the Blob (or Version) can't be modified or deleted, because it has either
+// a Legal Hold or an unexpired Immutability Policy
+func (b *blob) checkImmutable(now time.Time) error {
+	if b.legalHold {
+		return newError(http.StatusConflict, "BlobImmutableDueToLegalHold", "this operation is not permitted as the blob is immutable due to a legal hold")
+	}
+	if b.immutabilityPolicyMode != "" && now.Before(b.immutabilityPolicyExpiry) {
+		return newError(http.StatusConflict, "BlobImmutableDueToPolicy", "this operation is not permitted as the blob is immutable due to a policy")
+	}
+	return nil
+}
+
+func (b *blob) writeImmutabilityHeaders(header http.Header) {
+	if b.immutabilityPolicyMode != "" {
+		header.Set("x-ms-immutability-policy-until-date", formatTime(b.immutabilityPolicyExpiry))
+		header.Set("x-ms-immutability-policy-mode", b.immutabilityPolicyMode)
+	}
+	if b.legalHold {
+		header.Set("x-ms-legal-hold", "true")
+	}
+}
+
+// setImmutabilityPolicy sets the Immutability Policy on a Blob, Snapshot or Version - where a Locked policy
+// can only have its expiry extended
+func setImmutabilityPolicy(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := checkConditions(r, true, b.etag, b.lastModified); err != nil {
+		return nil, err
+	}
+
+	expiry, err := time.Parse(http.TimeFormat, r.Header.Get("x-ms-immutability-policy-until-date"))
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the value for `x-ms-immutability-policy-until-date` is invalid")
+	}
+	if !expiry.After(now) {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the value for `x-ms-immutability-policy-until-date` must be in the future")
+	}
+	mode := r.Header.Get("x-ms-immutability-policy-mode")
+	if mode == "" {
+		mode = immutabilityPolicyModeUnlocked
+	}
+	if mode != immutabilityPolicyModeLocked && mode != immutabilityPolicyModeUnlocked {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the value for `x-ms-immutability-policy-mode` is invalid")
+	}
+	if b.immutabilityPolicyMode == immutabilityPolicyModeLocked {
+		if mode != immutabilityPolicyModeLocked || expiry.Before(b.immutabilityPolicyExpiry) {
+			return nil, newError(http.StatusConflict, "ImmutabilityPolicyLocked", "a locked immutability policy can only have its expiry extended")
+		}
+	}
+
+	b.immutabilityPolicyExpiry = expiry
+	b.immutabilityPolicyMode = mode
+
+	resp := newResponse(http.StatusOK)
+	b.writeImmutabilityHeaders(resp.header)
+	return resp, nil
+}
+
+func deleteImmutabilityPolicy(b *blob) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if b.immutabilityPolicyMode == immutabilityPolicyModeLocked {
+		return nil, newError(http.StatusConflict, "ImmutabilityPolicyLocked", "a locked immutability policy cannot be deleted")
+	}
+
+	b.immutabilityPolicyExpiry = time.Time{}
+	b.immutabilityPolicyMode = ""
+	return newResponse(http.StatusOK), nil
+}
+
+func setLegalHold(r *request, b *blob) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	legalHold, err := strconv.ParseBool(r.Header.Get("x-ms-legal-hold"))
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the value for `x-ms-legal-hold` is invalid")
+	}
+
+	b.legalHold = legalHold
+
+	resp := newResponse(http.StatusOK)
+	resp.header.Set("x-ms-legal-hold", strconv.FormatBool(legalHold))
+	return resp, nil
+}
@@ -0,0 +1,221 @@
+package blobserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	leaseStateAvailable = "available"
+	leaseStateBreaking  = "breaking"
+	leaseStateBroken    = "broken"
+	leaseStateExpired   = "expired"
+	leaseStateLeased    = "leased"
+)
+
+// lease tracks the state of a Lease on either a Container or a Blob
+type lease struct {
+	id       string
+	state    string
+	duration int
+
+	// expiresAt is when a fixed-duration Lease expires
+	expiresAt time.Time
+
+	// brokenAt is when a Lease which is being broken becomes broken
+	brokenAt time.Time
+}
+
+// refresh transitions the Lease to the expired/broken state once the relevant period has elapsed
+func (l *lease) refresh(now time.Time) {
+	switch l.state {
+	case "":
+		l.state = leaseStateAvailable
+	case leaseStateLeased:
+		if l.duration != -1 && !now.Before(l.expiresAt) {
+			l.state = leaseStateExpired
+		}
+	case leaseStateBreaking:
+		if !now.Before(l.brokenAt) {
+			l.state = leaseStateBroken
+		}
+	}
+}
+
+// isActive returns whether the Lease is currently held, and as such whether write operations require the Lease ID
+func (l *lease) isActive() bool {
+	return l.state == leaseStateLeased || l.state == leaseStateBreaking
+}
+
+func (l *lease) writeHeaders(header http.Header) {
+	status := "unlocked"
+	if l.isActive() {
+		status = "locked"
+	}
+	header.Set("x-ms-lease-state", l.state)
+	header.Set("x-ms-lease-status", status)
+	if l.state == leaseStateLeased {
+		duration := "fixed"
+		if l.duration == -1 {
+			duration = "infinite"
+		}
+		header.Set("x-ms-lease-duration", duration)
+	}
+}
+
+// checkWrite validates that the Lease ID specified for a write (or delete) operation matches the active Lease
+func (l *lease) checkWrite(leaseID string, now time.Time) error {
+	l.refresh(now)
+	if l.isActive() {
+		if leaseID == "" {
+			return newError(http.StatusPreconditionFailed, "LeaseIdMissing", "there is currently a lease on the resource and no lease ID was specified in the request")
+		}
+		if leaseID != l.id {
+			return newError(http.StatusPreconditionFailed, "LeaseIdMismatchWithBlobOperation", "the lease ID specified did not match the lease ID for the resource")
+		}
+		return nil
+	}
+
+	if leaseID != "" {
+		return newError(http.StatusPreconditionFailed, "LeaseNotPresentWithBlobOperation", "there is currently no lease on the resource")
+	}
+	return nil
+}
+
+// checkRead validates the (optional) Lease ID specified for a read operation matches the active Lease
+func (l *lease) checkRead(leaseID string, now time.Time) error {
+	if leaseID == "" {
+		return nil
+	}
+	return l.checkWrite(leaseID, now)
+}
+
+// apply performs the Lease operation specified using the `x-ms-lease-action` header
+func (l *lease) apply(r *request, now time.Time) (*response, error) {
+	l.refresh(now)
+	leaseID := r.leaseID()
+	proposedLeaseID := r.Header.Get("x-ms-proposed-lease-id")
+
+	switch action := r.Header.Get("x-ms-lease-action"); action {
+	case "acquire":
+		duration, err := strconv.Atoi(r.Header.Get("x-ms-lease-duration"))
+		if err != nil || (duration != -1 && (duration < 15 || duration > 60)) {
+			return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the lease duration must be -1 or between 15 and 60 seconds")
+		}
+		switch l.state {
+		case leaseStateLeased:
+			if proposedLeaseID == "" || proposedLeaseID != l.id {
+				return nil, newError(http.StatusConflict, "LeaseAlreadyPresent", "there is already a lease present")
+			}
+		case leaseStateBreaking:
+			return nil, newError(http.StatusConflict, "LeaseIsBreakingAndCannotBeAcquired", "there is currently a lease on the resource which is being broken")
+		}
+		if proposedLeaseID == "" {
+			proposedLeaseID = uuid.New().String()
+		}
+		l.acquire(proposedLeaseID, duration, now)
+
+		resp := newResponse(http.StatusCreated)
+		resp.header.Set("x-ms-lease-id", l.id)
+		return resp, nil
+
+	case "renew":
+		if err := l.checkLeaseOperation(leaseID); err != nil {
+			return nil, err
+		}
+		if l.state == leaseStateBreaking || l.state == leaseStateBroken {
+			return nil, newError(http.StatusConflict, "LeaseIsBrokenAndCannotBeRenewed", "the lease has been broken and cannot be renewed")
+		}
+		l.acquire(l.id, l.duration, now)
+
+		resp := newResponse(http.StatusOK)
+		resp.header.Set("x-ms-lease-id", l.id)
+		return resp, nil
+
+	case "change":
+		if l.state != leaseStateLeased {
+			return nil, newError(http.StatusConflict, "LeaseNotPresentWithLeaseOperation", "there is currently no lease on the resource")
+		}
+		if proposedLeaseID == "" {
+			return nil, newError(http.StatusBadRequest, "MissingRequiredHeader", "the `x-ms-proposed-lease-id` header must be specified")
+		}
+		if leaseID != l.id && leaseID != proposedLeaseID {
+			return nil, newError(http.StatusConflict, "LeaseIdMismatchWithLeaseOperation", "the lease ID specified did not match the lease ID for the resource")
+		}
+		l.id = proposedLeaseID
+
+		resp := newResponse(http.StatusOK)
+		resp.header.Set("x-ms-lease-id", l.id)
+		return resp, nil
+
+	case "release":
+		if err := l.checkLeaseOperation(leaseID); err != nil {
+			return nil, err
+		}
+		*l = lease{
+			state: leaseStateAvailable,
+		}
+		return newResponse(http.StatusOK), nil
+
+	case "break":
+		if l.state == leaseStateAvailable {
+			return nil, newError(http.StatusConflict, "LeaseNotPresentWithLeaseOperation", "there is currently no lease on the resource")
+		}
+
+		remaining := 0
+		if l.state == leaseStateLeased && l.duration != -1 {
+			remaining = int(l.expiresAt.Sub(now).Round(time.Second) / time.Second)
+		}
+		if l.state == leaseStateBreaking {
+			remaining = int(l.brokenAt.Sub(now).Round(time.Second) / time.Second)
+		}
+		if v := r.Header.Get("x-ms-lease-break-period"); v != "" && l.state == leaseStateLeased {
+			period, err := strconv.Atoi(v)
+			if err != nil || period < 0 || period > 60 {
+				return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "the lease break period must be between 0 and 60 seconds")
+			}
+			if l.duration == -1 || period < remaining {
+				remaining = period
+			}
+		}
+
+		if l.state == leaseStateLeased || l.state == leaseStateBreaking {
+			if remaining <= 0 {
+				l.state = leaseStateBroken
+				remaining = 0
+			} else {
+				l.state = leaseStateBreaking
+				l.brokenAt = now.Add(time.Duration(remaining) * time.Second)
+			}
+		}
+
+		resp := newResponse(http.StatusAccepted)
+		resp.header.Set("x-ms-lease-time", strconv.Itoa(remaining))
+		return resp, nil
+
+	default:
+		return nil, newError(http.StatusBadRequest, "InvalidHeaderValue", "unsupported lease action %q", action)
+	}
+}
+
+func (l *lease) acquire(id string, duration int, now time.Time) {
+	l.id = id
+	l.state = leaseStateLeased
+	l.duration = duration
+	if duration != -1 {
+		l.expiresAt = now.Add(time.Duration(duration) * time.Second)
+	}
+}
+
+func (l *lease) checkLeaseOperation(leaseID string) error {
+	if l.state == leaseStateAvailable || l.id == "" {
+		return newError(http.StatusConflict, "LeaseNotPresentWithLeaseOperation", "there is currently no lease on the resource")
+	}
+	if leaseID != l.id {
+		return newError(http.StatusConflict, "LeaseIdMismatchWithLeaseOperation", "the lease ID specified did not match the lease ID for the resource")
+	}
+	return nil
+}
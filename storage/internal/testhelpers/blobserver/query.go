@@ -0,0 +1,379 @@
+package blobserver
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// queryChunkSize is the number of bytes of results which are returned in each result data record
+	queryChunkSize = 1024
+
+	// queryProgressInterval is the number of bytes scanned between each progress record
+	queryProgressInterval = 1024
+
+	// querySchema is the Avro Schema of the records returned by a Query, as used by the Storage API
+	querySchema = `[{"type":"record","name":"com.microsoft.azure.storage.queryBlobContents.resultData","fields":[{"name":"data","type":"bytes"}]},` +
+		`{"type":"record","name":"com.microsoft.azure.storage.queryBlobContents.error","fields":[{"name":"fatal","type":"boolean"},{"name":"name","type":"string"},{"name":"description","type":"string"},{"name":"position","type":"long"}]},` +
+		`{"type":"record","name":"com.microsoft.azure.storage.queryBlobContents.progress","fields":[{"name":"bytesScanned","type":"long"},{"name":"totalBytes","type":"long"}]},` +
+		`{"type":"record","name":"com.microsoft.azure.storage.queryBlobContents.end","fields":[{"name":"totalBytes","type":"long"}]}]`
+)
+
+// the index of each record within the union defined by querySchema
+const (
+	queryResultDataRecord int64 = iota
+	queryErrorRecord
+	queryProgressRecord
+	queryEndRecord
+)
+
+// queryExpressionRegex matches the subset of the SQL syntax supported by this Server - which is selecting all
+// records, optionally where a single column (or, for JSON, a top-level property) is equal to a value
+var queryExpressionRegex = regexp.MustCompile(`(?i)^\s*SELECT\s+\*\s+FROM\s+BlobStorage(?:\s+WHERE\s+(\S+)\s*=\s*'([^']*)')?\s*$`)
+
+type queryRequest struct {
+	XMLName             xml.Name            `xml:"QueryRequest"`
+	QueryType           string              `xml:"QueryType"`
+	Expression          string              `xml:"Expression"`
+	InputSerialization  *querySerialization `xml:"InputSerialization"`
+	OutputSerialization *querySerialization `xml:"OutputSerialization"`
+}
+
+type querySerialization struct {
+	Type                       string                      `xml:"Format>Type"`
+	DelimitedTextConfiguration *delimitedTextConfiguration `xml:"Format>DelimitedTextConfiguration"`
+	JSONTextConfiguration      *jsonTextConfiguration      `xml:"Format>JsonTextConfiguration"`
+}
+
+type delimitedTextConfiguration struct {
+	ColumnSeparator string `xml:"ColumnSeparator"`
+	RecordSeparator string `xml:"RecordSeparator"`
+	HasHeaders      bool   `xml:"HasHeaders"`
+}
+
+type jsonTextConfiguration struct {
+	RecordSeparator string `xml:"RecordSeparator"`
+}
+
+// recordSeparator returns the character used to separate records, defaulting to a newline
+func (q querySerialization) recordSeparator() string {
+	separator := ""
+	if q.DelimitedTextConfiguration != nil {
+		separator = q.DelimitedTextConfiguration.RecordSeparator
+	}
+	if q.JSONTextConfiguration != nil {
+		separator = q.JSONTextConfiguration.RecordSeparator
+	}
+	if separator == "" {
+		return "\n"
+	}
+	return separator
+}
+
+// columnSeparator returns the character used to separate columns, defaulting to a comma
+func (q querySerialization) columnSeparator() string {
+	if q.DelimitedTextConfiguration != nil && q.DelimitedTextConfiguration.ColumnSeparator != "" {
+		return q.DelimitedTextConfiguration.ColumnSeparator
+	}
+	return ","
+}
+
+func (q querySerialization) hasHeaders() bool {
+	return q.DelimitedTextConfiguration != nil && q.DelimitedTextConfiguration.HasHeaders
+}
+
+func (s *Server) queryBlob(r *request, b *blob, now time.Time) (*response, error) {
+	if b == nil || b.uncommitted {
+		return nil, blobNotFound()
+	}
+	if err := b.lease.checkRead(r.leaseID(), now); err != nil {
+		return nil, err
+	}
+	return queryResponse(r, b)
+}
+
+// queryResponse runs the Query against either a Blob or a Snapshot, returning the results as an Avro Object
+// Container File. Only delimited text (where quoted fields aren't supported) and JSON records are supported.
+func queryResponse(r *request, b *blob) (*response, error) {
+	if err := checkEncryptionKey(r, b); err != nil {
+		return nil, err
+	}
+	if err := checkConditions(r, true, b.etag, b.lastModified); err != nil {
+		return nil, err
+	}
+
+	var query queryRequest
+	if err := xml.Unmarshal(r.body, &query); err != nil {
+		return nil, newError(http.StatusBadRequest, "InvalidXmlDocument", "the query request is invalid: %+v", err)
+	}
+	if !strings.EqualFold(query.QueryType, "SQL") {
+		return nil, newError(http.StatusBadRequest, "InvalidQueryType", "the query type %q is not supported", query.QueryType)
+	}
+	input := querySerialization{Type: "delimited"}
+	if query.InputSerialization != nil {
+		input = *query.InputSerialization
+	}
+	output := input
+	if query.OutputSerialization != nil {
+		output = *query.OutputSerialization
+	}
+	for _, format := range []string{input.Type, output.Type} {
+		if format != "delimited" && format != "json" {
+			return nil, newError(http.StatusBadRequest, "InvalidQueryFormat", "the format %q is not supported", format)
+		}
+	}
+	match := queryExpressionRegex.FindStringSubmatch(query.Expression)
+	if match == nil {
+		return nil, newError(http.StatusBadRequest, "InvalidQueryExpression", "the query expression %q is not supported", query.Expression)
+	}
+	column, value := match[1], match[2]
+
+	q := &queryWriter{
+		input:      input,
+		output:     output,
+		totalBytes: int64(len(b.content)),
+	}
+	q.run(b.content, column, value)
+
+	resp := newResponse(http.StatusOK)
+	resp.header.Set("Content-Type", "avro/binary")
+	resp.header.Set("ETag", b.etag)
+	resp.header.Set("Last-Modified", formatTime(b.lastModified))
+	resp.header.Set("x-ms-blob-type", b.blobType)
+	resp.body = q.avro.Bytes()
+	return resp, nil
+}
+
+// queryWriter runs a Query, writing the results as Avro-framed records
+type queryWriter struct {
+	input      querySerialization
+	output     querySerialization
+	totalBytes int64
+
+	avro         *avroWriter
+	results      bytes.Buffer
+	lastProgress int64
+}
+
+func (q *queryWriter) run(content []byte, column, value string) {
+	q.avro = newAvroWriter(querySchema)
+
+	separator := q.input.recordSeparator()
+	var headers []string
+	position := int64(0)
+	for _, record := range strings.SplitAfter(string(content), separator) {
+		start := position
+		position += int64(len(record))
+		record = strings.TrimSuffix(record, separator)
+		if record == "" {
+			continue
+		}
+
+		var fields map[string]string
+		switch q.input.Type {
+		case "delimited":
+			values := strings.Split(record, q.input.columnSeparator())
+			if q.input.hasHeaders() && headers == nil {
+				headers = values
+				if column != "" && !strings.HasPrefix(column, "_") && indexOf(headers, column) == -1 {
+					q.writeError(true, "InvalidColumnName", fmt.Sprintf("the column %q does not exist", column), 0)
+					return
+				}
+				if q.output.Type == "delimited" && q.output.hasHeaders() {
+					q.writeRecord(headers)
+				}
+				continue
+			}
+			fields = make(map[string]string, len(values))
+			for i, v := range values {
+				fields[fmt.Sprintf("_%d", i+1)] = v
+				if i < len(headers) {
+					fields[headers[i]] = v
+				}
+			}
+			if column == "" || fields[column] == value {
+				q.writeDelimitedRecord(headers, values)
+			}
+
+		case "json":
+			var object map[string]interface{}
+			if err := json.Unmarshal([]byte(record), &object); err != nil {
+				q.writeError(false, "InvalidJsonRecord", fmt.Sprintf("the record could not be parsed: %+v", err), start)
+				continue
+			}
+			if column == "" || fmt.Sprint(object[column]) == value {
+				q.writeJSONRecord(object)
+			}
+		}
+
+		if position-q.lastProgress >= queryProgressInterval {
+			q.writeProgress(position)
+		}
+	}
+
+	q.flushResults()
+	q.avro.writeBlock(
+		avroRecord{queryProgressRecord, []interface{}{q.totalBytes, q.totalBytes}},
+		avroRecord{queryEndRecord, []interface{}{q.totalBytes}},
+	)
+}
+
+// writeDelimitedRecord writes a record read from delimited text in the output format
+func (q *queryWriter) writeDelimitedRecord(headers, values []string) {
+	if q.output.Type == "delimited" {
+		q.writeRecord(values)
+		return
+	}
+	object := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		name := fmt.Sprintf("_%d", i+1)
+		if i < len(headers) {
+			name = headers[i]
+		}
+		object[name] = v
+	}
+	q.writeJSONRecord(object)
+}
+
+// writeJSONRecord writes a record read from JSON in the output format
+func (q *queryWriter) writeJSONRecord(object map[string]interface{}) {
+	if q.output.Type == "delimited" {
+		keys := make([]string, 0, len(object))
+		for k := range object {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]string, 0, len(keys))
+		for _, k := range keys {
+			values = append(values, fmt.Sprint(object[k]))
+		}
+		q.writeRecord(values)
+		return
+	}
+	// json.Marshal sorts the keys of a map, so the output is stable
+	record, _ := json.Marshal(object)
+	q.results.Write(record)
+	q.results.WriteString(q.output.recordSeparator())
+	q.flushResultsIfFull()
+}
+
+// writeRecord writes a delimited text record
+func (q *queryWriter) writeRecord(values []string) {
+	q.results.WriteString(strings.Join(values, q.output.columnSeparator()))
+	q.results.WriteString(q.output.recordSeparator())
+	q.flushResultsIfFull()
+}
+
+func (q *queryWriter) flushResultsIfFull() {
+	if q.results.Len() >= queryChunkSize {
+		q.flushResults()
+	}
+}
+
+func (q *queryWriter) flushResults() {
+	for q.results.Len() > 0 {
+		chunk := q.results.Next(queryChunkSize)
+		q.avro.writeBlock(avroRecord{queryResultDataRecord, []interface{}{append([]byte{}, chunk...)}})
+	}
+}
+
+func (q *queryWriter) writeProgress(bytesScanned int64) {
+	q.flushResults()
+	q.avro.writeBlock(avroRecord{queryProgressRecord, []interface{}{bytesScanned, q.totalBytes}})
+	q.lastProgress = bytesScanned
+}
+
+func (q *queryWriter) writeError(fatal bool, name, description string, position int64) {
+	q.flushResults()
+	q.avro.writeBlock(avroRecord{queryErrorRecord, []interface{}{fatal, name, description, position}})
+}
+
+// avroRecord is a record within the union defined by the schema, along with the values of each of its fields
+type avroRecord struct {
+	index  int64
+	fields []interface{}
+}
+
+// avroWriter writes an Avro Object Container File using the `null` codec
+type avroWriter struct {
+	bytes.Buffer
+	sync []byte
+}
+
+func newAvroWriter(schema string) *avroWriter {
+	w := &avroWriter{
+		sync: make([]byte, 16),
+	}
+	rand.Read(w.sync)
+
+	w.WriteString("Obj\x01")
+	writeAvroLong(&w.Buffer, 2)
+	writeAvroBytes(&w.Buffer, []byte("avro.schema"))
+	writeAvroBytes(&w.Buffer, []byte(schema))
+	writeAvroBytes(&w.Buffer, []byte("avro.codec"))
+	writeAvroBytes(&w.Buffer, []byte("null"))
+	writeAvroLong(&w.Buffer, 0)
+	w.Write(w.sync)
+	return w
+}
+
+// writeBlock writes a block containing the records, followed by the sync marker
+func (w *avroWriter) writeBlock(records ...avroRecord) {
+	var block bytes.Buffer
+	for _, record := range records {
+		writeAvroLong(&block, record.index)
+		for _, field := range record.fields {
+			switch v := field.(type) {
+			case bool:
+				if v {
+					block.WriteByte(1)
+				} else {
+					block.WriteByte(0)
+				}
+			case int64:
+				writeAvroLong(&block, v)
+			case string:
+				writeAvroBytes(&block, []byte(v))
+			case []byte:
+				writeAvroBytes(&block, v)
+			default:
+				panic(fmt.Sprintf("unsupported avro field type %T", field))
+			}
+		}
+	}
+	writeAvroLong(&w.Buffer, int64(len(records)))
+	writeAvroLong(&w.Buffer, int64(block.Len()))
+	w.Write(block.Bytes())
+	w.Write(w.sync)
+}
+
+// writeAvroLong writes a zig-zag encoded variable-length integer
+func writeAvroLong(buf *bytes.Buffer, v int64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(b, uint64((v<<1)^(v>>63)))
+	buf.Write(b[:n])
+}
+
+// writeAvroBytes writes a sequence of bytes prefixed with its length
+func writeAvroBytes(buf *bytes.Buffer, v []byte) {
+	writeAvroLong(buf, int64(len(v)))
+	buf.Write(v)
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
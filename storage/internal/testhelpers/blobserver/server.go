@@ -0,0 +1,344 @@
+package blobserver
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-azure-sdk/sdk/auth"
+)
+
+// DefaultAccountName is the name of the Storage Account exposed by a Server created using New
+const DefaultAccountName = "devstoreaccount1"
+
+// Server is an in-memory implementation of the subset of the Blob Storage REST API used by this SDK
+// (Containers, Block/Append/Page Blobs, Leases, Snapshots, Versions, MetaData, Copy, Query and Immutability) -
+// allowing tests to run without provisioning a Storage Account, by pointing `NewWithBaseUri` at BaseUri.
+//
+// The Server is addressed using a path-style URI (e.g. `http://127.0.0.1:1234/devstoreaccount1`) and
+// validates the SharedKey signature of each request.
+type Server struct {
+	// AccountName is the name of the Storage Account exposed by this Server.
+	AccountName string
+
+	// AccountKey is the (base64 encoded) key used to validate the SharedKey signature of each request.
+	AccountKey string
+
+	// AllowAnonymousAccess specifies whether requests without an Authorization header are accepted.
+	AllowAnonymousAccess bool
+
+	// BearerToken optionally specifies an OAuth token which is accepted (as `Authorization: Bearer {token}`)
+	// in place of a SharedKey signature - for example, to authorize another Server reading a Copy Source.
+	BearerToken string
+
+	// RehydrationDelay is how long it takes for a Blob to be rehydrated from the Archive tier, during
+	// which time the Blob has an Archive Status of `rehydrate-pending-to-{tier}`. Defaults to 0.
+	RehydrationDelay time.Duration
+
+	// VersioningEnabled specifies whether Blob Versioning is enabled, in which case each write to a Blob
+	// assigns a new Version ID - and the previous state of the Blob is retained as a previous Version.
+	VersioningEnabled bool
+
+	server *httptest.Server
+
+	mu         sync.Mutex
+	containers map[string]*container
+	lastEtag   int64
+	lastTime   time.Time
+}
+
+// New starts a Server for the duration of the test `t`, using the DefaultAccountName and a random Account Key.
+func New(t *testing.T) *Server {
+	key := make([]byte, 64)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("generating account key: %+v", err)
+	}
+
+	server := NewServer(DefaultAccountName, base64.StdEncoding.EncodeToString(key))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// NewServer starts a Server exposing the Storage Account `accountName`, validating requests using `accountKey`.
+// The Server must be closed using Close once it's no longer needed.
+func NewServer(accountName, accountKey string) *Server {
+	s := &Server{
+		AccountName: accountName,
+		AccountKey:  accountKey,
+		containers:  map[string]*container{},
+		lastEtag:    0x8D0000000000000,
+	}
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// BaseUri returns the path-style Base URI for the Storage Account exposed by this Server, which can be
+// passed to `NewWithBaseUri`.
+func (s *Server) BaseUri() string {
+	return fmt.Sprintf("%s/%s", s.server.URL, s.AccountName)
+}
+
+// Authorizer returns a SharedKey Authorizer for the Storage Account exposed by this Server.
+func (s *Server) Authorizer() (auth.Authorizer, error) {
+	return auth.NewSharedKeyAuthorizer(s.AccountName, s.AccountKey, auth.SharedKey)
+}
+
+// Close shuts down the Server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("x-ms-request-id", uuid.New().String())
+	if v := r.Header.Get("x-ms-version"); v != "" {
+		w.Header().Set("x-ms-version", v)
+	}
+	if v := r.Header.Get("x-ms-client-request-id"); v != "" {
+		w.Header().Set("x-ms-client-request-id", v)
+	}
+
+	if err := s.authenticate(r); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, newError(http.StatusBadRequest, "InvalidInput", "reading the request body: %+v", err))
+		return
+	}
+
+	prefix := fmt.Sprintf("/%s", s.AccountName)
+	if r.URL.Path != prefix && !strings.HasPrefix(r.URL.Path, prefix+"/") {
+		writeError(w, newError(http.StatusBadRequest, "InvalidUri", "expected the path %q to begin with the account name %q", r.URL.Path, s.AccountName))
+		return
+	}
+	containerName, blobName, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/"), "/")
+
+	req := &request{
+		Request:       r,
+		body:          body,
+		containerName: containerName,
+		blobName:      blobName,
+		query:         r.URL.Query(),
+	}
+
+	var resp *response
+	switch {
+	case containerName == "":
+		err = newError(http.StatusBadRequest, "UnsupportedHttpVerb", "account-level operations aren't supported")
+	case blobName == "":
+		resp, err = s.handleContainer(req)
+	default:
+		resp, err = s.handleBlob(req)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp.write(w, r.Method)
+}
+
+type request struct {
+	*http.Request
+
+	body          []byte
+	containerName string
+	blobName      string
+	query         url.Values
+}
+
+func (r *request) comp() string {
+	return r.query.Get("comp")
+}
+
+func (r *request) leaseID() string {
+	return r.Header.Get("x-ms-lease-id")
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func newResponse(status int) *response {
+	return &response{
+		status: status,
+		header: http.Header{},
+	}
+}
+
+func (r *response) withXML(v interface{}) *response {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("marshalling response: %+v", err))
+	}
+	r.header.Set("Content-Type", "application/xml")
+	r.body = append([]byte(xml.Header), body...)
+	return r
+}
+
+func (r *response) write(w http.ResponseWriter, method string) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	if r.status == http.StatusNotModified {
+		// a Not Modified response can't contain a body
+		w.Header().Del("Content-Type")
+		w.WriteHeader(r.status)
+		return
+	}
+	if r.header.Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(r.body)))
+	}
+	w.WriteHeader(r.status)
+	if method != http.MethodHead && len(r.body) > 0 {
+		w.Write(r.body)
+	}
+}
+
+// storageError is an error returned from the Server in the same format as the Storage API
+type storageError struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+
+	status int
+}
+
+func newError(status int, code, format string, a ...interface{}) *storageError {
+	return &storageError{
+		Code:    code,
+		Message: fmt.Sprintf(format, a...),
+		status:  status,
+	}
+}
+
+func (e *storageError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.status, e.Code, e.Message)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*storageError)
+	if !ok {
+		e = newError(http.StatusInternalServerError, "InternalError", "%+v", err)
+	}
+	resp := newResponse(e.status).withXML(e)
+	resp.header.Set("x-ms-error-code", e.Code)
+	resp.write(w, http.MethodGet)
+}
+
+// now returns the current time, ensuring that it's always later than the previously returned time - so that
+// the timestamps (and as such, Snapshot IDs) are unique. The caller must hold the lock.
+func (s *Server) now() time.Time {
+	now := time.Now().UTC().Truncate(100 * time.Nanosecond)
+	if !now.After(s.lastTime) {
+		now = s.lastTime.Add(100 * time.Nanosecond)
+	}
+	s.lastTime = now
+	return now
+}
+
+// nextETag returns a new unique ETag. The caller must hold the lock.
+func (s *Server) nextETag() string {
+	s.lastEtag++
+	return fmt.Sprintf("\"0x%X\"", s.lastEtag)
+}
+
+func formatTime(input time.Time) string {
+	return input.UTC().Format(http.TimeFormat)
+}
+
+func formatSnapshot(input time.Time) string {
+	return input.UTC().Format("2006-01-02T15:04:05.0000000Z")
+}
+
+// checkConditions validates the conditional headers (If-Match, If-None-Match, If-Modified-Since and
+// If-Unmodified-Since) within the request against the specified `etag` and `lastModified` time
+func checkConditions(r *request, exists bool, etag string, lastModified time.Time) error {
+	failed := newError(http.StatusPreconditionFailed, "ConditionNotMet", "the condition specified using HTTP conditional header(s) is not met")
+	if v := r.Header.Get("If-Match"); v != "" {
+		if !exists || (v != "*" && v != etag) {
+			return failed
+		}
+	}
+	if v := r.Header.Get("If-None-Match"); v != "" {
+		if exists && (v == "*" || v == etag) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				return newError(http.StatusNotModified, "ConditionNotMet", "the condition specified using HTTP conditional header(s) is not met")
+			}
+			return failed
+		}
+	}
+	if v := r.Header.Get("If-Modified-Since"); v != "" && exists {
+		if t, err := http.ParseTime(v); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				return newError(http.StatusNotModified, "ConditionNotMet", "the condition specified using HTTP conditional header(s) is not met")
+			}
+			return failed
+		}
+	}
+	if v := r.Header.Get("If-Unmodified-Since"); v != "" && exists {
+		if t, err := http.ParseTime(v); err == nil && lastModified.Truncate(time.Second).After(t) {
+			return failed
+		}
+	}
+	return nil
+}
+
+// parseMetaData returns the MetaData specified using the `x-ms-meta-` headers within the request
+func parseMetaData(header http.Header) map[string]string {
+	out := map[string]string{}
+	for k, v := range header {
+		key := strings.ToLower(k)
+		if strings.HasPrefix(key, "x-ms-meta-") && len(v) > 0 {
+			out[strings.TrimPrefix(key, "x-ms-meta-")] = v[0]
+		}
+	}
+	return out
+}
+
+// parseTags parses the URL-encoded Blob Index Tags within the `x-ms-tags` header (if any)
+func parseTags(header http.Header) (map[string]string, error) {
+	v := header.Get("x-ms-tags")
+	if v == "" {
+		return nil, nil
+	}
+	values, err := url.ParseQuery(v)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "InvalidTag", "parsing the `x-ms-tags` header: %+v", err)
+	}
+	if len(values) > 10 {
+		return nil, newError(http.StatusBadRequest, "InvalidTag", "at most 10 tags can be specified but got %d", len(values))
+	}
+	out := make(map[string]string, len(values))
+	for k := range values {
+		out[k] = values.Get(k)
+	}
+	return out, nil
+}
+
+func writeMetaData(header http.Header, metaData map[string]string) {
+	for k, v := range metaData {
+		header.Set(fmt.Sprintf("x-ms-meta-%s", k), v)
+	}
+}
+
+func copyMetaData(input map[string]string) map[string]string {
+	out := make(map[string]string, len(input))
+	for k, v := range input {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,399 @@
+package blobserver_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/blobs"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+)
+
+func buildClients(t *testing.T, server *blobserver.Server) (*containers.Client, *blobs.Client) {
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building containers client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+
+	blobsClient, err := blobs.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building blobs client: %+v", err)
+	}
+	blobsClient.Client.SetAuthorizer(authorizer)
+
+	return containersClient, blobsClient
+}
+
+func TestServerRejectsInvalidSignatures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	other := blobserver.New(t)
+
+	// an authorizer using a different Account Key
+	authorizer, err := other.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	client, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	client.Client.SetAuthorizer(authorizer)
+
+	if _, err := client.Create(ctx, "container1", containers.CreateInput{}); err == nil {
+		t.Fatalf("expected an error when using an invalid signature but didn't get one")
+	}
+
+	unauthenticated, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	if _, err := unauthenticated.Create(ctx, "container1", containers.CreateInput{}); err == nil {
+		t.Fatalf("expected an error when omitting the Authorization header but didn't get one")
+	}
+}
+
+func TestServerContainerLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	containersClient, blobsClient := buildClients(t, server)
+
+	containerName := "container1"
+	createInput := containers.CreateInput{
+		AccessLevel: containers.Blob,
+		MetaData: map[string]string{
+			"hello": "world",
+		},
+	}
+	if _, err := containersClient.Create(ctx, containerName, createInput); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	if _, err := containersClient.Create(ctx, containerName, createInput); err == nil {
+		t.Fatalf("expected an error creating a duplicate container but didn't get one")
+	}
+
+	props, err := containersClient.GetProperties(ctx, containerName, containers.GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.AccessLevel != containers.Blob {
+		t.Fatalf("expected the access level to be %q but got %q", containers.Blob, props.AccessLevel)
+	}
+	if props.MetaData["hello"] != "world" {
+		t.Fatalf("expected the metadata `hello` to be `world` but got %q", props.MetaData["hello"])
+	}
+
+	lease, err := containersClient.AcquireLease(ctx, containerName, containers.AcquireLeaseInput{LeaseDuration: -1})
+	if err != nil {
+		t.Fatalf("acquiring lease: %+v", err)
+	}
+	if _, err := containersClient.Delete(ctx, containerName); err == nil {
+		t.Fatalf("expected an error deleting a leased container but didn't get one")
+	}
+	if _, err := containersClient.ReleaseLease(ctx, containerName, containers.ReleaseLeaseInput{LeaseId: lease.LeaseID}); err != nil {
+		t.Fatalf("releasing lease: %+v", err)
+	}
+
+	for _, name := range []string{"a.txt", "dir/b.txt", "dir/c.txt"} {
+		input := blobs.PutBlockBlobInput{
+			Content: pointer.To([]byte(name)),
+		}
+		if _, err := blobsClient.PutBlockBlob(ctx, containerName, name, input); err != nil {
+			t.Fatalf("putting blob %q: %+v", name, err)
+		}
+	}
+
+	list, err := containersClient.ListBlobs(ctx, containerName, containers.ListBlobsInput{
+		Delimiter: pointer.To("/"),
+	})
+	if err != nil {
+		t.Fatalf("listing blobs: %+v", err)
+	}
+	if len(list.Blobs.Blobs) != 1 || list.Blobs.Blobs[0].Name != "a.txt" {
+		t.Fatalf("expected a single blob `a.txt` but got %+v", list.Blobs.Blobs)
+	}
+	if list.Blobs.BlobPrefix == nil || list.Blobs.BlobPrefix.Name != "dir/" {
+		t.Fatalf("expected the blob prefix `dir/` but got %+v", list.Blobs.BlobPrefix)
+	}
+
+	paged, err := containersClient.ListBlobs(ctx, containerName, containers.ListBlobsInput{
+		MaxResults: pointer.To(2),
+	})
+	if err != nil {
+		t.Fatalf("listing blobs: %+v", err)
+	}
+	if len(paged.Blobs.Blobs) != 2 || paged.NextMarker == nil || *paged.NextMarker != "dir/c.txt" {
+		t.Fatalf("expected 2 blobs and a next marker of `dir/c.txt` but got %d blobs and %v", len(paged.Blobs.Blobs), paged.NextMarker)
+	}
+
+	if _, err := containersClient.Delete(ctx, containerName); err != nil {
+		t.Fatalf("deleting container: %+v", err)
+	}
+	if _, err := containersClient.GetProperties(ctx, containerName, containers.GetPropertiesInput{}); err == nil {
+		t.Fatalf("expected an error retrieving a deleted container but didn't get one")
+	}
+}
+
+func TestServerBlockBlobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	containersClient, blobsClient := buildClients(t, server)
+
+	containerName := "container1"
+	if _, err := containersClient.Create(ctx, containerName, containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	blobName := "blocks.txt"
+	for i, block := range []string{"hello ", "there ", "world"} {
+		input := blobs.PutBlockInput{
+			BlockID: fmt.Sprintf("YmxvY2s%d", i),
+			Content: []byte(block),
+		}
+		if _, err := blobsClient.PutBlock(ctx, containerName, blobName, input); err != nil {
+			t.Fatalf("putting block %d: %+v", i, err)
+		}
+	}
+	if _, err := blobsClient.GetProperties(ctx, containerName, blobName, blobs.GetPropertiesInput{}); err == nil {
+		t.Fatalf("expected an error retrieving a blob containing only uncommitted blocks but didn't get one")
+	}
+
+	blockList := blobs.BlockList{
+		UncommittedBlockIDs: []blobs.BlockID{{Value: "YmxvY2s0"}, {Value: "YmxvY2s2"}},
+	}
+	if _, err := blobsClient.PutBlockList(ctx, containerName, blobName, blobs.PutBlockListInput{BlockList: blockList}); err != nil {
+		t.Fatalf("putting block list: %+v", err)
+	}
+
+	contents, err := blobsClient.Get(ctx, containerName, blobName, blobs.GetInput{})
+	if err != nil {
+		t.Fatalf("retrieving blob: %+v", err)
+	}
+	if string(*contents.Contents) != "hello world" {
+		t.Fatalf("expected the contents to be `hello world` but got %q", string(*contents.Contents))
+	}
+
+	partial, err := blobsClient.Get(ctx, containerName, blobName, blobs.GetInput{
+		StartByte: pointer.To(int64(6)),
+		EndByte:   pointer.To(int64(10)),
+	})
+	if err != nil {
+		t.Fatalf("retrieving range: %+v", err)
+	}
+	if string(*partial.Contents) != "world" {
+		t.Fatalf("expected the range to be `world` but got %q", string(*partial.Contents))
+	}
+
+	list, err := blobsClient.GetBlockList(ctx, containerName, blobName, blobs.GetBlockListInput{BlockListType: blobs.All})
+	if err != nil {
+		t.Fatalf("retrieving block list: %+v", err)
+	}
+	if len(list.CommittedBlocks.Blocks) != 2 || len(list.UncommittedBlocks.Blocks) != 0 {
+		t.Fatalf("expected 2 committed and 0 uncommitted blocks but got %d and %d", len(list.CommittedBlocks.Blocks), len(list.UncommittedBlocks.Blocks))
+	}
+
+	metaDataInput := blobs.SetMetaDataInput{
+		MetaData: map[string]string{
+			"hello": "there",
+		},
+	}
+	if _, err := blobsClient.SetMetaData(ctx, containerName, blobName, metaDataInput); err != nil {
+		t.Fatalf("setting metadata: %+v", err)
+	}
+	for _, tier := range []blobs.AccessTier{blobs.Cool, blobs.Archive} {
+		if _, err := blobsClient.SetTier(ctx, containerName, blobName, blobs.SetTierInput{Tier: tier}); err != nil {
+			t.Fatalf("setting tier to %q: %+v", tier, err)
+		}
+	}
+	props, err := blobsClient.GetProperties(ctx, containerName, blobName, blobs.GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.BlobType != blobs.BlockBlob {
+		t.Fatalf("expected the blob type to be %q but got %q", blobs.BlockBlob, props.BlobType)
+	}
+	if props.ContentLength != 11 {
+		t.Fatalf("expected the content length to be 11 but got %d", props.ContentLength)
+	}
+	if props.AccessTier != blobs.Archive {
+		t.Fatalf("expected the access tier to be %q but got %q", blobs.Archive, props.AccessTier)
+	}
+	if props.MetaData["hello"] != "there" {
+		t.Fatalf("expected the metadata `hello` to be `there` but got %q", props.MetaData["hello"])
+	}
+
+	copyInput := blobs.CopyInput{
+		CopySource: fmt.Sprintf("%s/%s/%s", server.BaseUri(), containerName, blobName),
+		AccessTier: pointer.To(blobs.Hot),
+	}
+	if _, err := blobsClient.Copy(ctx, containerName, "copied.txt", copyInput); err != nil {
+		t.Fatalf("copying blob: %+v", err)
+	}
+	copied, err := blobsClient.GetProperties(ctx, containerName, "copied.txt", blobs.GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if copied.CopyStatus != blobs.Success {
+		t.Fatalf("expected the copy status to be %q but got %q", blobs.Success, copied.CopyStatus)
+	}
+	if copied.ContentLength != 11 || copied.MetaData["hello"] != "there" {
+		t.Fatalf("expected the copy to contain the contents and metadata of the source blob")
+	}
+}
+
+func TestServerAppendAndPageBlobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	containersClient, blobsClient := buildClients(t, server)
+
+	containerName := "container1"
+	if _, err := containersClient.Create(ctx, containerName, containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	if _, err := blobsClient.PutAppendBlob(ctx, containerName, "append.log", blobs.PutAppendBlobInput{}); err != nil {
+		t.Fatalf("putting append blob: %+v", err)
+	}
+	for i, line := range []string{"one\n", "two\n"} {
+		input := blobs.AppendBlockInput{
+			Content:                     pointer.To([]byte(line)),
+			BlobConditionAppendPosition: pointer.To(int64(i * 4)),
+		}
+		if _, err := blobsClient.AppendBlock(ctx, containerName, "append.log", input); err != nil {
+			t.Fatalf("appending block %d: %+v", i, err)
+		}
+	}
+	staleInput := blobs.AppendBlockInput{
+		Content:                     pointer.To([]byte("three\n")),
+		BlobConditionAppendPosition: pointer.To(int64(0)),
+	}
+	if _, err := blobsClient.AppendBlock(ctx, containerName, "append.log", staleInput); err == nil {
+		t.Fatalf("expected an error appending at a stale position but didn't get one")
+	}
+	props, err := blobsClient.GetProperties(ctx, containerName, "append.log", blobs.GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.BlobCommittedBlockCount != "2" || props.ContentLength != 8 {
+		t.Fatalf("expected 2 committed blocks and 8 bytes but got %s and %d", props.BlobCommittedBlockCount, props.ContentLength)
+	}
+
+	pageBlobInput := blobs.PutPageBlobInput{
+		BlobContentLengthBytes: 4096,
+	}
+	if _, err := blobsClient.PutPageBlob(ctx, containerName, "disk.vhd", pageBlobInput); err != nil {
+		t.Fatalf("putting page blob: %+v", err)
+	}
+	page := bytes.Repeat([]byte("a"), 1024)
+	if _, err := blobsClient.PutPageUpdate(ctx, containerName, "disk.vhd", blobs.PutPageUpdateInput{StartByte: 512, EndByte: 1535, Content: page}); err != nil {
+		t.Fatalf("updating pages: %+v", err)
+	}
+	if _, err := blobsClient.PutPageUpdate(ctx, containerName, "disk.vhd", blobs.PutPageUpdateInput{StartByte: 2048, EndByte: 3071, Content: page}); err != nil {
+		t.Fatalf("updating pages: %+v", err)
+	}
+	if _, err := blobsClient.PutPageClear(ctx, containerName, "disk.vhd", blobs.PutPageClearInput{StartByte: 2048, EndByte: 2559}); err != nil {
+		t.Fatalf("clearing pages: %+v", err)
+	}
+	ranges, err := blobsClient.GetPageRanges(ctx, containerName, "disk.vhd", blobs.GetPageRangesInput{})
+	if err != nil {
+		t.Fatalf("retrieving page ranges: %+v", err)
+	}
+	expected := []blobs.PageRange{{Start: 512, End: 1535}, {Start: 2560, End: 3071}}
+	if len(ranges.PageRanges) != len(expected) {
+		t.Fatalf("expected the page ranges %+v but got %+v", expected, ranges.PageRanges)
+	}
+	for i := range expected {
+		if ranges.PageRanges[i] != expected[i] {
+			t.Fatalf("expected the page ranges %+v but got %+v", expected, ranges.PageRanges)
+		}
+	}
+}
+
+func TestServerLeasesAndSnapshots(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	containersClient, blobsClient := buildClients(t, server)
+
+	containerName := "container1"
+	blobName := "leased.txt"
+	if _, err := containersClient.Create(ctx, containerName, containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	if _, err := blobsClient.PutBlockBlob(ctx, containerName, blobName, blobs.PutBlockBlobInput{Content: pointer.To([]byte("v1"))}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+
+	lease, err := blobsClient.AcquireLease(ctx, containerName, blobName, blobs.AcquireLeaseInput{LeaseDuration: -1})
+	if err != nil {
+		t.Fatalf("acquiring lease: %+v", err)
+	}
+	if _, err := blobsClient.PutBlockBlob(ctx, containerName, blobName, blobs.PutBlockBlobInput{Content: pointer.To([]byte("v2"))}); err == nil {
+		t.Fatalf("expected an error writing to a leased blob without the lease ID but didn't get one")
+	}
+	if _, err := blobsClient.RenewLease(ctx, containerName, blobName, blobs.RenewLeaseInput{LeaseID: lease.LeaseID}); err != nil {
+		t.Fatalf("renewing lease: %+v", err)
+	}
+	changed, err := blobsClient.ChangeLease(ctx, containerName, blobName, blobs.ChangeLeaseInput{
+		ExistingLeaseID: lease.LeaseID,
+		ProposedLeaseID: "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
+	})
+	if err != nil {
+		t.Fatalf("changing lease: %+v", err)
+	}
+
+	snapshot, err := blobsClient.Snapshot(ctx, containerName, blobName, blobs.SnapshotInput{})
+	if err != nil {
+		t.Fatalf("snapshotting blob: %+v", err)
+	}
+	if _, err := blobsClient.PutBlockBlob(ctx, containerName, blobName, blobs.PutBlockBlobInput{Content: pointer.To([]byte("version2")), LeaseID: pointer.To(changed.LeaseID)}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	snapshotProps, err := blobsClient.GetSnapshotProperties(ctx, containerName, blobName, blobs.GetSnapshotPropertiesInput{SnapshotID: snapshot.SnapshotDateTime})
+	if err != nil {
+		t.Fatalf("retrieving snapshot properties: %+v", err)
+	}
+	if snapshotProps.ContentLength != 2 {
+		t.Fatalf("expected the snapshot to contain 2 bytes but got %d", snapshotProps.ContentLength)
+	}
+
+	props, err := blobsClient.GetProperties(ctx, containerName, blobName, blobs.GetPropertiesInput{})
+	if err != nil {
+		t.Fatalf("retrieving properties: %+v", err)
+	}
+	if props.LeaseState != blobs.Leased || props.LeaseStatus != blobs.Locked || props.LeaseDuration != blobs.Infinite {
+		t.Fatalf("expected the blob to have an infinite lease but got %q / %q / %q", props.LeaseState, props.LeaseStatus, props.LeaseDuration)
+	}
+
+	if _, err := blobsClient.BreakLease(ctx, containerName, blobName, blobs.BreakLeaseInput{BreakPeriod: pointer.To(0), LeaseID: changed.LeaseID}); err != nil {
+		t.Fatalf("breaking lease: %+v", err)
+	}
+	if _, err := blobsClient.Delete(ctx, containerName, blobName, blobs.DeleteInput{}); err == nil {
+		t.Fatalf("expected an error deleting a blob with snapshots but didn't get one")
+	}
+	if _, err := blobsClient.Delete(ctx, containerName, blobName, blobs.DeleteInput{DeleteSnapshots: true}); err != nil {
+		t.Fatalf("deleting blob: %+v", err)
+	}
+	if _, err := blobsClient.GetSnapshotProperties(ctx, containerName, blobName, blobs.GetSnapshotPropertiesInput{SnapshotID: snapshot.SnapshotDateTime}); err == nil {
+		t.Fatalf("expected an error retrieving a deleted snapshot but didn't get one")
+	}
+}
@@ -0,0 +1,88 @@
+package blobserver
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// archiveVersion retains the current state of the Blob `b` as a previous Version within `into` - which is
+// a no-op when `b` was written before Versioning was enabled (and as such has no Version ID)
+func archiveVersion(into, b *blob) {
+	if b.versionId == "" {
+		return
+	}
+	version := b.clone()
+	version.previousVersion = true
+	if into.versions == nil {
+		into.versions = map[string]*blob{}
+	}
+	into.versions[b.versionId] = version
+}
+
+// assignVersion assigns a new Version ID to the Blob `b` when Versioning is enabled
+func (s *Server) assignVersion(b *blob, now time.Time) {
+	b.versionId = ""
+	b.previousVersion = false
+	if s.VersioningEnabled {
+		b.versionId = formatSnapshot(now)
+	}
+}
+
+func (b *blob) sortedVersionIds() []string {
+	ids := make([]string, 0, len(b.versions))
+	for id := range b.versions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// lookupVersion returns the Version `versionId` of the Blob `b`, which may be either the current Version
+// or a previous Version
+func (b *blob) lookupVersion(versionId string) *blob {
+	if b == nil {
+		return nil
+	}
+	if !b.uncommitted && b.versionId == versionId {
+		return b
+	}
+	return b.versions[versionId]
+}
+
+func (s *Server) handleVersion(r *request, b *blob, versionId string, now time.Time) (*response, error) {
+	version := b.versions[versionId]
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		switch r.comp() {
+		case "":
+			return readBlob(r, version)
+		case "blocklist":
+			return blockListResponse(r, version)
+		case "pagelist":
+			return s.pageRangesResponse(r, version, b)
+		}
+	case http.MethodPut:
+		switch r.comp() {
+		case "tier":
+			return s.setBlobTier(r, version, now)
+		case "immutabilityPolicies":
+			return setImmutabilityPolicy(r, version, now)
+		case "legalhold":
+			return setLegalHold(r, version)
+		}
+	case http.MethodDelete:
+		switch r.comp() {
+		case "":
+			if err := version.checkImmutable(now); err != nil {
+				return nil, err
+			}
+			delete(b.versions, versionId)
+			return newResponse(http.StatusAccepted), nil
+		case "immutabilityPolicies":
+			return deleteImmutabilityPolicy(version)
+		}
+	}
+
+	return nil, newError(http.StatusBadRequest, "UnsupportedHttpVerb", "the operation %s with comp %q isn't supported for versions", r.Method, r.comp())
+}
@@ -3,6 +3,7 @@ package testhelpers
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"testing"
 	"time"
@@ -15,8 +16,21 @@ import (
 	"github.com/hashicorp/go-azure-sdk/sdk/client"
 	"github.com/hashicorp/go-azure-sdk/sdk/client/dataplane/storage"
 	"github.com/hashicorp/go-azure-sdk/sdk/environments"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/recording"
+	"github.com/tombuildsstuff/giovanni/storage/logging"
 )
 
+// recordingModeEnvVar is the environment variable used to specify whether the tests are run against the
+// Storage API (`live`), run against the Storage API whilst recording the interactions (`record`), or
+// replayed from previously recorded interactions (`replay`). When unset, tests are run live when `ACCTEST`
+// is set and are otherwise replayed (and skipped when no recording exists).
+const recordingModeEnvVar = "ACCTEST_RECORDING_MODE"
+
+// blobServerEnvVar is the environment variable used to specify that the tests built using BuildWithBlobServer
+// should be run against an in-memory Blob Storage server (see the `blobserver` package) rather than the Storage API.
+const blobServerEnvVar = "ACCTEST_BLOB_SERVER"
+
 type Client struct {
 	Environment          environments.Environment
 	ResourceGroupsClient *resourcegroups.ResourceGroupsClient
@@ -25,6 +39,12 @@ type Client struct {
 
 	resourceManagerAuth auth.Authorizer
 	storageAuth         auth.Authorizer
+
+	blobServer *blobserver.Server
+	logger     *logging.RequestLogger
+	mode       recording.Mode
+	random     *seededRandom
+	recorder   *recording.Recorder
 }
 
 type TestResources struct {
@@ -43,6 +63,25 @@ func (c Client) BuildTestResourcesWithSku(ctx context.Context, resourceGroup, na
 	return c.buildTestResources(ctx, resourceGroup, name, kind, false, sku)
 }
 func (c Client) buildTestResources(ctx context.Context, resourceGroup, name string, kind storageaccounts.Kind, enableHns bool, sku storageaccounts.SkuName) (*TestResources, error) {
+	if c.blobServer != nil {
+		// the Blob Storage server exposes a single Storage Account, which takes the name of the test's account
+		c.blobServer.AccountName = name
+		return &TestResources{
+			ResourceGroup:      resourceGroup,
+			StorageAccountName: name,
+			StorageAccountKey:  c.blobServer.AccountKey,
+		}, nil
+	}
+
+	if c.mode == recording.ModeReplay {
+		// the Account Key used when recording has been redacted, and isn't needed to replay the interactions
+		return &TestResources{
+			ResourceGroup:      resourceGroup,
+			StorageAccountName: name,
+			StorageAccountKey:  recording.RedactedAccountKey,
+		}, nil
+	}
+
 	location := os.Getenv("ARM_TEST_LOCATION")
 	resourceGroupId := commonids.NewResourceGroupID(c.SubscriptionId, resourceGroup)
 	resourceGroupPayload := resourcegroups.ResourceGroup{
@@ -89,6 +128,11 @@ func (c Client) buildTestResources(ctx context.Context, resourceGroup, name stri
 	time.Sleep(5 * time.Second)
 
 	accountKeys := *keys.Model.Keys
+	if c.recorder != nil {
+		for _, key := range accountKeys {
+			c.recorder.Redact(pointer.From(key.Value))
+		}
+	}
 	return &TestResources{
 		ResourceGroup:      resourceGroup,
 		StorageAccountName: name,
@@ -97,6 +141,10 @@ func (c Client) buildTestResources(ctx context.Context, resourceGroup, name stri
 }
 
 func (c Client) DestroyTestResources(ctx context.Context, resourceGroup, name string) error {
+	if c.blobServer != nil || c.mode == recording.ModeReplay {
+		return nil
+	}
+
 	storageAccountId := commonids.NewStorageAccountID(c.SubscriptionId, resourceGroup, name)
 	if _, err := c.StorageAccountClient.Delete(ctx, storageAccountId); err != nil {
 		return fmt.Errorf("error deleting %s: %+v", storageAccountId, err)
@@ -111,6 +159,14 @@ func (c Client) DestroyTestResources(ctx context.Context, resourceGroup, name st
 }
 
 func Build(ctx context.Context, t *testing.T) (*Client, error) {
+	mode, err := recordingMode()
+	if err != nil {
+		return nil, err
+	}
+	if mode == recording.ModeReplay {
+		return buildForReplay(t)
+	}
+
 	if os.Getenv("ACCTEST") == "" {
 		t.Skip("Skipping as `ACCTEST` hasn't been set")
 	}
@@ -155,6 +211,28 @@ func Build(ctx context.Context, t *testing.T) (*Client, error) {
 		// internal
 		resourceManagerAuth: resourceManagerAuth,
 		storageAuth:         storageAuthorizer,
+		logger:              buildLogger(),
+		mode:                mode,
+		random:              newSeededRandom(""),
+	}
+
+	if mode == recording.ModeRecord {
+		recorder, err := recording.New(mode, recording.FixturePath(t.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("building recorder: %+v", err)
+		}
+		t.Cleanup(func() {
+			recorder.Stop()
+			if t.Failed() {
+				t.Logf("[DEBUG] Not saving the recording since the test failed")
+				return
+			}
+			if err := recorder.Save(); err != nil {
+				t.Errorf("saving recording: %+v", err)
+			}
+		})
+		client.recorder = recorder
+		client.random = newSeededRandom(t.Name())
 	}
 
 	resourceGroupsClient, err := resourcegroups.NewResourceGroupsClientWithBaseURI(env.ResourceManager)
@@ -174,13 +252,116 @@ func Build(ctx context.Context, t *testing.T) (*Client, error) {
 	return &client, nil
 }
 
+// BuildWithBlobServer returns a Client for a test which only uses the Blob Storage API. When the `ACCTEST_BLOB_SERVER`
+// environment variable is set, the test is run against an in-memory Blob Storage server (so no Storage Account is
+// provisioned) - otherwise this behaves the same as Build. The Base URI for the Blob Storage API should be obtained
+// using BlobEndpoint.
+func BuildWithBlobServer(ctx context.Context, t *testing.T) (*Client, error) {
+	if os.Getenv(blobServerEnvVar) == "" {
+		return Build(ctx, t)
+	}
+
+	env, err := environments.FromName("public")
+	if err != nil {
+		return nil, fmt.Errorf("determining environment %q: %+v", "public", err)
+	}
+	return &Client{
+		Environment:    *env,
+		SubscriptionId: "00000000-0000-0000-0000-000000000000",
+		blobServer:     blobserver.New(t),
+		logger:         buildLogger(),
+		mode:           recording.ModeLive,
+		random:         newSeededRandom(""),
+	}, nil
+}
+
+// buildForReplay returns a Client which replays the interactions previously recorded for the test `t`,
+// skipping the test if no recording exists. No requests are sent to Resource Manager when replaying.
+func buildForReplay(t *testing.T) (*Client, error) {
+	path := recording.FixturePath(t.Name())
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		t.Skipf("Skipping as `ACCTEST` hasn't been set and no recording exists at %q", path)
+	}
+
+	environmentName := os.Getenv("ARM_ENVIRONMENT")
+	if environmentName == "" {
+		environmentName = "public"
+	}
+	env, err := environments.FromName(environmentName)
+	if err != nil {
+		return nil, fmt.Errorf("determining environment %q: %+v", environmentName, err)
+	}
+
+	recorder, err := recording.New(recording.ModeReplay, path)
+	if err != nil {
+		return nil, fmt.Errorf("building recorder: %+v", err)
+	}
+	t.Cleanup(recorder.Stop)
+
+	return &Client{
+		Environment:    *env,
+		SubscriptionId: "00000000-0000-0000-0000-000000000000",
+		logger:         buildLogger(),
+		mode:           recording.ModeReplay,
+		random:         newSeededRandom(t.Name()),
+		recorder:       recorder,
+	}, nil
+}
+
+// recordingMode returns the recording.Mode specified using the `ACCTEST_RECORDING_MODE` environment variable
+func recordingMode() (recording.Mode, error) {
+	if v := os.Getenv(recordingModeEnvVar); v != "" {
+		mode, err := recording.ParseMode(v)
+		if err != nil {
+			return "", fmt.Errorf("parsing `%s`: %+v", recordingModeEnvVar, err)
+		}
+		return mode, nil
+	}
+
+	if os.Getenv("ACCTEST") != "" {
+		return recording.ModeLive, nil
+	}
+	return recording.ModeReplay, nil
+}
+
+// buildLogger returns a RequestLogger which logs each request (and response) to stderr when the `TEST_LOG`
+// environment variable is set
+func buildLogger() *logging.RequestLogger {
+	if os.Getenv("TEST_LOG") == "" {
+		return nil
+	}
+
+	logger := logging.New(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})))
+	logger.IncludeHeaders = true
+	return logger
+}
+
+// BlobEndpoint returns the Base URI for the Blob Storage API of the Storage Account within `data` - which is the
+// in-memory Blob Storage server when the Client was built using BuildWithBlobServer with `ACCTEST_BLOB_SERVER` set.
+func (c Client) BlobEndpoint(data *TestResources) (*string, error) {
+	if c.blobServer != nil {
+		return pointer.To(c.blobServer.BaseUri()), nil
+	}
+
+	domainSuffix, ok := c.Environment.Storage.DomainSuffix()
+	if !ok {
+		return nil, fmt.Errorf("storage didn't return a domain suffix for this environment")
+	}
+	return pointer.To(fmt.Sprintf("https://%s.blob.%s", data.StorageAccountName, *domainSuffix)), nil
+}
+
 func (c Client) Configure(client *client.Client, authorizer auth.Authorizer) {
 	client.Authorizer = authorizer
-	// TODO: add logging
+	if c.logger != nil {
+		c.logger.Attach(client)
+	}
 }
 
 func (c Client) PrepareWithResourceManagerAuth(input *storage.Client) {
 	input.SetAuthorizer(c.storageAuth)
+	c.attachMiddlewares(input)
 }
 
 func (c Client) PrepareWithSharedKeyAuth(input *storage.Client, data *TestResources, keyType auth.SharedKeyType) error {
@@ -189,5 +370,17 @@ func (c Client) PrepareWithSharedKeyAuth(input *storage.Client, data *TestResour
 		return fmt.Errorf("building SharedKey authorizer: %+v", err)
 	}
 	input.SetAuthorizer(auth)
+	c.attachMiddlewares(input)
 	return nil
 }
+
+// attachMiddlewares configures the Storage client to send requests via the Recorder (when recording or replaying)
+// and to log each request (when `TEST_LOG` is set)
+func (c Client) attachMiddlewares(input *storage.Client) {
+	if c.logger != nil {
+		c.logger.Attach(input.Client.Client)
+	}
+	if c.recorder != nil {
+		c.recorder.Attach(input)
+	}
+}
@@ -1,7 +1,9 @@
 package testhelpers
 
 import (
+	"hash/fnv"
 	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -11,13 +13,17 @@ func RandomInt() int {
 }
 
 func RandomString() string {
+	reseed()
+	return randomString(rand.Intn)
+}
+
+func randomString(intn func(n int) int) string {
 	size := 5
 	charSet := "abcdefghijklmnopqrstuvwxyz0123456789"
 
-	reseed()
 	result := make([]byte, size)
 	for i := 0; i < size; i++ {
-		result[i] = charSet[rand.Intn(len(charSet))]
+		result[i] = charSet[intn(len(charSet))]
 	}
 	return string(result)
 }
@@ -25,3 +31,54 @@ func RandomString() string {
 func reseed() {
 	rand.Seed(time.Now().UTC().UnixNano())
 }
+
+// seededRandom is a source of random values for a single test, which (when recording or replaying) is seeded
+// using the name of the test - so that the names of the resources used within the test are the same each time
+// it's run, regardless of any other tests running in parallel.
+type seededRandom struct {
+	mu     sync.Mutex
+	source *rand.Rand
+}
+
+// newSeededRandom returns a seededRandom for the test `name`, or (when `name` is empty) using a time-based seed.
+func newSeededRandom(name string) *seededRandom {
+	seed := time.Now().UnixNano()
+	if name != "" {
+		h := fnv.New64a()
+		h.Write([]byte(name))
+		seed = int64(h.Sum64())
+	}
+	return &seededRandom{
+		source: rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (r *seededRandom) Int() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.source.Int()
+}
+
+func (r *seededRandom) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return randomString(r.source.Intn)
+}
+
+// RandomInt returns a random integer for use within the test this Client was built for - which is derived from
+// the name of the test when recording or replaying.
+func (c Client) RandomInt() int {
+	if c.random == nil {
+		return RandomInt()
+	}
+	return c.random.Int()
+}
+
+// RandomString returns a random 5 character string for use within the test this Client was built for - which is
+// derived from the name of the test when recording or replaying.
+func (c Client) RandomString() string {
+	if c.random == nil {
+		return RandomString()
+	}
+	return c.random.String()
+}
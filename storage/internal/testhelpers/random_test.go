@@ -0,0 +1,27 @@
+package testhelpers
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSeededRandomIsDeterministicPerTest(t *testing.T) {
+	expected := Client{random: newSeededRandom("TestExample")}
+	expectedName := fmt.Sprintf("%d-%s", expected.RandomInt(), expected.RandomString())
+
+	for i := 0; i < 5; i++ {
+		t.Run(fmt.Sprintf("parallel-%d", i), func(t *testing.T) {
+			t.Parallel()
+
+			// another test using its own source mustn't change the values returned for this test
+			other := Client{random: newSeededRandom(t.Name())}
+			other.RandomInt()
+			other.RandomString()
+
+			client := Client{random: newSeededRandom("TestExample")}
+			if actual := fmt.Sprintf("%d-%s", client.RandomInt(), client.RandomString()); actual != expectedName {
+				t.Fatalf("expected %q but got %q", expectedName, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client/dataplane/storage"
+)
+
+// targetHeader is used to pass the original scheme and host of a request to the Recorder
+const targetHeader = "X-Recording-Target"
+
+// DefaultMatchHeaders are the request headers which (in addition to the method, host, path and query string)
+// are used to match a request to a recorded interaction - since these change the behaviour of an operation
+// which otherwise shares the same URI.
+var DefaultMatchHeaders = []string{
+	"Range",
+	"x-ms-access-tier",
+	"x-ms-blob-type",
+	"x-ms-copy-source",
+	"x-ms-delete-snapshots",
+	"x-ms-lease-action",
+	"x-ms-page-write",
+	"x-ms-range",
+	"x-ms-type",
+}
+
+// Recorder is an http.RoundTripper which either records interactions with the Storage API to a fixture file
+// (ModeRecord), or replays them from a fixture file (ModeReplay).
+//
+// Since the base SDK builds a new transport for each request, a Recorder is plugged into a client using
+// Attach - which redirects requests (after they've been authorized) to a local server backed by the Recorder.
+type Recorder struct {
+	// MatchHeaders are the request headers used to match a request to a recorded interaction.
+	// Defaults to DefaultMatchHeaders.
+	MatchHeaders []string
+
+	// Transport is used to send requests to the Storage API when recording. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	mode     Mode
+	path     string
+	redactor redactor
+	server   *httptest.Server
+
+	mu           sync.Mutex
+	interactions []Interaction
+	indexed      bool
+	pending      map[string][]Interaction
+}
+
+// New returns a Recorder using the specified Mode, which records to or replays from the fixture file at `path`.
+// When replaying, the fixture file must exist. The Recorder must be stopped using Stop once it's no longer needed.
+func New(mode Mode, path string) (*Recorder, error) {
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, fmt.Errorf("a Recorder can only be used to record or replay, but got the mode %q", mode)
+	}
+
+	r := &Recorder{
+		MatchHeaders: DefaultMatchHeaders,
+		Transport:    http.DefaultTransport,
+		mode:         mode,
+		path:         path,
+		pending:      map[string][]Interaction{},
+	}
+
+	if mode == ModeReplay {
+		recording, err := Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading recording: %+v", err)
+		}
+		r.interactions = recording.Interactions
+	}
+
+	r.server = httptest.NewServer(r)
+	return r, nil
+}
+
+// Mode returns the Mode used by this Recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Redact registers a secret (such as an Account Key) which is replaced wherever it appears within the
+// headers or body of a recorded interaction.
+func (r *Recorder) Redact(secret string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactor.addSecret(secret)
+}
+
+// Attach configures the Storage client (e.g. as built by `NewWithBaseUri`) to send requests via this Recorder.
+func (r *Recorder) Attach(c *storage.Client) {
+	serverUri, _ := url.Parse(r.server.URL)
+	c.AppendRequestMiddleware(func(req *http.Request) (*http.Request, error) {
+		// the request has already been authorized at this point, so the original target is passed in a header
+		req.Header.Set(targetHeader, fmt.Sprintf("%s://%s", req.URL.Scheme, req.URL.Host))
+		req.URL.Scheme = serverUri.Scheme
+		req.URL.Host = serverUri.Host
+		req.Host = ""
+		return req, nil
+	})
+}
+
+// Stop shuts down the local server used by this Recorder.
+func (r *Recorder) Stop() {
+	r.server.Close()
+}
+
+// Save writes the interactions recorded so far to the fixture file. This is a no-op when replaying.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Recording{Interactions: r.interactions}.Save(r.path)
+}
+
+// RoundTrip either sends the request to the Storage API and records the interaction, or returns the response
+// from the matching recorded interaction - in the order in which matching interactions were recorded.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %+v", err)
+		}
+		req.Body.Close()
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	outbound := req.Clone(req.Context())
+	outbound.Body = io.NopCloser(bytes.NewReader(body))
+	outbound.ContentLength = int64(len(body))
+	resp, err := r.Transport.RoundTrip(outbound)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %+v", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			Url:    r.redactor.redactString(req.URL.String()),
+			Header: r.redactor.redactHeader(req.Header),
+			Body:   r.redactor.redactBody(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     r.redactor.redactHeader(resp.Header),
+			Body:       r.redactor.redactBody(respBody),
+		},
+	})
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the recorded interactions are indexed on first use, since the MatchHeaders can be changed after New
+	if !r.indexed {
+		r.pending = map[string][]Interaction{}
+		for _, interaction := range r.interactions {
+			key, err := r.keyForRecordedRequest(interaction.Request)
+			if err != nil {
+				return nil, err
+			}
+			r.pending[key] = append(r.pending[key], interaction)
+		}
+		r.indexed = true
+	}
+
+	key := r.keyFor(req.Method, req.URL, req.Header)
+	matches := r.pending[key]
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no recorded interaction matches the request %q", key)
+	}
+	interaction := matches[0]
+	r.pending[key] = matches[1:]
+
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) keyForRecordedRequest(input Request) (string, error) {
+	uri, err := url.Parse(input.Url)
+	if err != nil {
+		return "", fmt.Errorf("parsing the recorded URI %q: %+v", input.Url, err)
+	}
+	return r.keyFor(input.Method, uri, input.Header), nil
+}
+
+// keyFor returns the key used to match a request to a recorded interaction, comprised of the method,
+// host, path, sorted query string and the values of the MatchHeaders - after redaction.
+func (r *Recorder) keyFor(method string, uri *url.URL, header http.Header) string {
+	query := uri.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	queryValues := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string{}, query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			queryValues = append(queryValues, fmt.Sprintf("%s=%s", k, r.redactor.redactString(v)))
+		}
+	}
+
+	headerValues := make([]string, 0)
+	for _, k := range r.MatchHeaders {
+		if v := header.Get(k); v != "" {
+			headerValues = append(headerValues, fmt.Sprintf("%s: %s", strings.ToLower(k), r.redactor.redactString(v)))
+		}
+	}
+
+	key := fmt.Sprintf("%s %s%s", method, uri.Host, uri.EscapedPath())
+	if len(queryValues) > 0 {
+		key = fmt.Sprintf("%s?%s", key, strings.Join(queryValues, "&"))
+	}
+	if len(headerValues) > 0 {
+		key = fmt.Sprintf("%s [%s]", key, strings.Join(headerValues, ", "))
+	}
+	return key
+}
+
+// ServeHTTP handles the requests redirected to the local server by Attach
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	target, err := url.Parse(req.Header.Get(targetHeader))
+	if err != nil || target.Host == "" {
+		writeError(w, fmt.Errorf("the %q header must contain the original scheme and host of the request", targetHeader))
+		return
+	}
+
+	outbound := req.Clone(req.Context())
+	outbound.RequestURI = ""
+	outbound.URL.Scheme = target.Scheme
+	outbound.URL.Host = target.Host
+	outbound.Host = target.Host
+	outbound.Header.Del(targetHeader)
+
+	resp, err := r.RoundTrip(outbound)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+// recorderError is returned (using the same format as the Storage API) when a request can't be recorded or replayed
+type recorderError struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	body, _ := xml.Marshal(recorderError{
+		Code:    "RecordingError",
+		Message: err.Error(),
+	})
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("x-ms-error-code", "RecordingError")
+	// a 400 is returned (rather than a 5xx) so that the request isn't retried
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(body)
+}
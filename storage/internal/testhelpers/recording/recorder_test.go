@@ -0,0 +1,161 @@
+package recording_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-sdk/sdk/auth"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/blobs"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/recording"
+)
+
+func TestParseMode(t *testing.T) {
+	testData := []struct {
+		Input    string
+		Expected recording.Mode
+		Error    bool
+	}{
+		{Input: "live", Expected: recording.ModeLive},
+		{Input: "Record", Expected: recording.ModeRecord},
+		{Input: "REPLAY", Expected: recording.ModeReplay},
+		{Input: "", Error: true},
+		{Input: "playback", Error: true},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.Input)
+		actual, err := recording.ParseMode(v.Input)
+		if err != nil {
+			if v.Error {
+				continue
+			}
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if v.Error {
+			t.Fatalf("expected an error but didn't get one")
+		}
+		if actual != v.Expected {
+			t.Fatalf("expected %q but got %q", v.Expected, actual)
+		}
+	}
+}
+
+func TestFixturePath(t *testing.T) {
+	actual := recording.FixturePath("TestLifecycle/with spaces")
+	expected := filepath.Join("testdata", "recordings", "TestLifecycle_with_spaces.json")
+	if actual != expected {
+		t.Fatalf("expected %q but got %q", expected, actual)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+	server := blobserver.New(t)
+
+	// first record the interactions against the in-memory server..
+	recorder, err := recording.New(recording.ModeRecord, path)
+	if err != nil {
+		t.Fatalf("building recorder: %+v", err)
+	}
+	recorder.Redact(server.AccountKey)
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	recorded := runScenario(ctx, t, recorder, server.BaseUri(), authorizer)
+	recorder.Stop()
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("saving recording: %+v", err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading recording: %+v", err)
+	}
+	if strings.Contains(string(contents), server.AccountKey) {
+		t.Fatalf("expected the Account Key to be redacted from the recording")
+	}
+	if strings.Contains(string(contents), "SharedKey ") {
+		t.Fatalf("expected the Authorization header to be redacted from the recording")
+	}
+
+	// .. then replay them once the server's gone away, using the placeholder Account Key
+	server.Close()
+	replayer, err := recording.New(recording.ModeReplay, path)
+	if err != nil {
+		t.Fatalf("building replayer: %+v", err)
+	}
+	defer replayer.Stop()
+	replayAuthorizer, err := auth.NewSharedKeyAuthorizer(server.AccountName, recording.RedactedAccountKey, auth.SharedKey)
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	replayed := runScenario(ctx, t, replayer, server.BaseUri(), replayAuthorizer)
+	if recorded != replayed {
+		t.Fatalf("expected the replayed contents to be %q but got %q", recorded, replayed)
+	}
+
+	// all of the interactions have been consumed, so further requests should fail without being retried
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	replayer.Attach(containersClient.Client)
+	if _, err := containersClient.GetProperties(ctx, "container1", containers.GetPropertiesInput{}); err == nil {
+		t.Fatalf("expected an error for an unrecorded request but didn't get one")
+	}
+}
+
+// runScenario writes a Blob twice (reading it back each time) and then reads a range, returning the contents of each read
+func runScenario(ctx context.Context, t *testing.T, recorder *recording.Recorder, baseUri string, authorizer auth.Authorizer) string {
+	containersClient, err := containers.NewWithBaseUri(baseUri)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	recorder.Attach(containersClient.Client)
+
+	blobsClient, err := blobs.NewWithBaseUri(baseUri)
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobsClient.Client.SetAuthorizer(authorizer)
+	recorder.Attach(blobsClient.Client)
+
+	if _, err := containersClient.Create(ctx, "container1", containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+
+	// the same request is sent twice, so the responses must be replayed in order
+	contents := make([]string, 0)
+	for _, v := range []string{"first", "second"} {
+		if _, err := blobsClient.PutBlockBlob(ctx, "container1", "example.txt", blobs.PutBlockBlobInput{Content: pointer.To([]byte(v))}); err != nil {
+			t.Fatalf("putting blob: %+v", err)
+		}
+		resp, err := blobsClient.Get(ctx, "container1", "example.txt", blobs.GetInput{})
+		if err != nil {
+			t.Fatalf("retrieving blob: %+v", err)
+		}
+		contents = append(contents, string(*resp.Contents))
+	}
+
+	partial, err := blobsClient.Get(ctx, "container1", "example.txt", blobs.GetInput{
+		StartByte: pointer.To(int64(0)),
+		EndByte:   pointer.To(int64(2)),
+	})
+	if err != nil {
+		t.Fatalf("retrieving range: %+v", err)
+	}
+	contents = append(contents, string(*partial.Contents))
+
+	return strings.Join(contents, ",")
+}
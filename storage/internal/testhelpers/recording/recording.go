@@ -0,0 +1,131 @@
+package recording
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mode specifies whether requests are sent to the Storage API, recorded or replayed
+type Mode string
+
+const (
+	// ModeLive sends requests directly to the Storage API, without recording them.
+	ModeLive Mode = "live"
+
+	// ModeRecord sends requests to the Storage API, recording each interaction to a fixture file.
+	ModeRecord Mode = "record"
+
+	// ModeReplay serves responses from a previously recorded fixture file, without sending any requests.
+	ModeReplay Mode = "replay"
+)
+
+// ParseMode parses the Mode from the specified value (e.g. from an environment variable)
+func ParseMode(input string) (Mode, error) {
+	for _, v := range []Mode{ModeLive, ModeRecord, ModeReplay} {
+		if strings.EqualFold(string(v), input) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("expected the mode to be one of %q, %q or %q but got %q", ModeLive, ModeRecord, ModeReplay, input)
+}
+
+// FixturePath returns the path to the fixture file for the test `testName`, relative to the
+// directory containing the test (which is the working directory when running `go test`).
+func FixturePath(testName string) string {
+	replacer := strings.NewReplacer("/", "_", "\\", "_", ":", "_", " ", "_")
+	return filepath.Join("testdata", "recordings", fmt.Sprintf("%s.json", replacer.Replace(testName)))
+}
+
+// Recording is the contents of a fixture file
+type Recording struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single request sent to the Storage API, along with the response which was returned
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a (redacted) request sent to the Storage API
+type Request struct {
+	Method string      `json:"method"`
+	Url    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response is a (redacted) response returned from the Storage API
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is a request or response body, which is stored as a string when it's valid UTF-8 (to keep
+// the fixture files readable/diffable) and otherwise base64 encoded.
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{
+		"base64": base64.StdEncoding.EncodeToString(b),
+	})
+}
+
+func (b *Body) UnmarshalJSON(input []byte) error {
+	var text string
+	if err := json.Unmarshal(input, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+
+	var encoded map[string]string
+	if err := json.Unmarshal(input, &encoded); err != nil {
+		return fmt.Errorf("expected the body to be a string or an object containing `base64`: %+v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded["base64"])
+	if err != nil {
+		return fmt.Errorf("decoding body: %+v", err)
+	}
+	*b = decoded
+	return nil
+}
+
+// Load reads the Recording from the fixture file at `path`
+func Load(path string) (*Recording, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %+v", path, err)
+	}
+
+	var recording Recording
+	if err := json.Unmarshal(contents, &recording); err != nil {
+		return nil, fmt.Errorf("parsing %q: %+v", path, err)
+	}
+	return &recording, nil
+}
+
+// Save writes the Recording to the fixture file at `path`, creating any parent directories as required
+func (r Recording) Save(path string) error {
+	contents, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling recording: %+v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory for %q: %+v", path, err)
+	}
+	if err := os.WriteFile(path, append(contents, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %q: %+v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Redacted is the value used in place of any secret within a fixture file
+const Redacted = "REDACTED"
+
+// RedactedAccountKey is a (valid, base64 encoded) Account Key which should be used to authorize requests
+// when replaying, since the Account Key used to record the interactions isn't available.
+var RedactedAccountKey = base64.StdEncoding.EncodeToString([]byte(Redacted))
+
+// redactedHeaders are the headers which contain credentials and are removed in their entirety
+var redactedHeaders = []string{
+	"Authorization",
+	"x-ms-copy-source-authorization",
+	"x-ms-encryption-key",
+}
+
+// signaturePattern matches the signature of a SAS Token within a URI
+var signaturePattern = regexp.MustCompile(`([?&]sig=)[^&"<\s]*`)
+
+type redactor struct {
+	secrets []string
+}
+
+func (r *redactor) addSecret(secret string) {
+	if secret == "" || secret == Redacted {
+		return
+	}
+	r.secrets = append(r.secrets, secret)
+}
+
+func (r *redactor) redactString(input string) string {
+	for _, secret := range r.secrets {
+		input = strings.ReplaceAll(input, secret, Redacted)
+	}
+	return signaturePattern.ReplaceAllString(input, "${1}"+Redacted)
+}
+
+func (r *redactor) redactBody(input []byte) []byte {
+	for _, secret := range r.secrets {
+		input = bytes.ReplaceAll(input, []byte(secret), []byte(Redacted))
+	}
+	return signaturePattern.ReplaceAll(input, []byte("${1}"+Redacted))
+}
+
+func (r *redactor) redactHeader(input http.Header) http.Header {
+	output := http.Header{}
+	for k, values := range input {
+		for _, v := range values {
+			output.Add(k, r.redactString(v))
+		}
+	}
+	for _, k := range redactedHeaders {
+		if output.Get(k) != "" {
+			output.Set(k, Redacted)
+		}
+	}
+	return output
+}
@@ -0,0 +1,273 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrLeaseAlreadyPresent is returned by a Lessor when the resource is already leased by another client.
+	ErrLeaseAlreadyPresent = errors.New("the resource is already leased")
+
+	// ErrLeaseLost is returned by a Lessor when the Lease can no longer be renewed (for example as it's
+	// been broken, or acquired by another client after expiring) - and is the cause of the Keeper's
+	// Context being cancelled.
+	ErrLeaseLost = errors.New("the lease has been lost")
+
+	// ErrLeaseReleased is the cause of the Keeper's Context being cancelled once the Lease is released.
+	ErrLeaseReleased = errors.New("the lease has been released")
+)
+
+// Lessor acquires, renews and releases the Lease on a single resource (such as a Blob or a Container).
+type Lessor interface {
+	// AcquireLease acquires a Lease for `duration` seconds using the Lease ID `proposedLeaseId`, returning
+	// ErrLeaseAlreadyPresent if the resource is already leased by another client.
+	AcquireLease(ctx context.Context, duration int, proposedLeaseId string) error
+
+	// RenewLease renews the Lease `leaseId`, returning ErrLeaseLost if the Lease is no longer held.
+	RenewLease(ctx context.Context, leaseId string) error
+
+	// ReleaseLease releases the Lease `leaseId`.
+	ReleaseLease(ctx context.Context, leaseId string) error
+}
+
+// Options configures how a Keeper acquires and renews a Lease.
+type Options struct {
+	// Duration is the duration of the Lease, which must be between 15 and 60 seconds. Defaults to 60 seconds.
+	Duration time.Duration
+
+	// RenewInterval is how often the Lease is renewed. Defaults to half of the Duration.
+	RenewInterval time.Duration
+
+	// LeaseID is the ID of the Lease to acquire. Defaults to a random UUID.
+	LeaseID string
+
+	// RetryDelay is the delay before Lock first retries acquiring the Lease, which is doubled for each
+	// subsequent attempt. Defaults to 1 second.
+	RetryDelay time.Duration
+
+	// MaxRetryDelay is the maximum delay between attempts to acquire the Lease. Defaults to 15 seconds.
+	MaxRetryDelay time.Duration
+}
+
+// Keeper holds a Lease on a resource, renewing it in the background until it's Closed - allowing a Lease to be
+// used as a distributed lock (for example, for leader election).
+type Keeper struct {
+	lessor  Lessor
+	options Options
+
+	// mu guards the fields below - but isn't held whilst acquiring or releasing the Lease, so that callers of
+	// Context aren't blocked by a slow request (or by waiting for a renewal to finish)
+	mu     sync.Mutex
+	held   bool
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	stop   chan struct{}
+	done   chan struct{}
+
+	// pending is closed once the call to TryLock or Close which is acquiring or releasing the Lease completes
+	pending chan struct{}
+}
+
+// NewKeeper returns a Keeper which holds a Lease acquired using `lessor`.
+func NewKeeper(lessor Lessor, options Options) (*Keeper, error) {
+	if lessor == nil {
+		return nil, fmt.Errorf("`lessor` cannot be nil")
+	}
+	if options.Duration == 0 {
+		options.Duration = 60 * time.Second
+	}
+	if options.Duration < 15*time.Second || options.Duration > 60*time.Second || options.Duration%time.Second != 0 {
+		return nil, fmt.Errorf("`options.Duration` must be a whole number of seconds between 15 and 60 seconds but got %s", options.Duration)
+	}
+	if options.RenewInterval == 0 {
+		options.RenewInterval = options.Duration / 2
+	}
+	if options.RenewInterval < 0 || options.RenewInterval >= options.Duration {
+		return nil, fmt.Errorf("`options.RenewInterval` must be less than `options.Duration` (%s) but got %s", options.Duration, options.RenewInterval)
+	}
+	if options.LeaseID == "" {
+		options.LeaseID = uuid.New().String()
+	}
+	if options.RetryDelay <= 0 {
+		options.RetryDelay = 1 * time.Second
+	}
+	if options.MaxRetryDelay <= 0 {
+		options.MaxRetryDelay = 15 * time.Second
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrLeaseReleased)
+	return &Keeper{
+		lessor:  lessor,
+		options: options,
+		ctx:     ctx,
+		cancel:  cancel,
+	}, nil
+}
+
+// LeaseID returns the ID of the Lease held by this Keeper.
+func (k *Keeper) LeaseID() string {
+	return k.options.LeaseID
+}
+
+// Context returns a Context which is cancelled when the Lease is lost or released - the cause of which
+// (available using `context.Cause`) is either ErrLeaseLost or ErrLeaseReleased.
+func (k *Keeper) Context() context.Context {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.ctx
+}
+
+// TryLock attempts to acquire the Lease once, returning ErrLeaseAlreadyPresent if the resource is already
+// leased by another client. Once acquired the Lease is renewed in the background until Close is called.
+func (k *Keeper) TryLock(ctx context.Context) error {
+	end, err := k.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("waiting for the lease to be released: %+v", err)
+	}
+	defer end()
+
+	if k.held && context.Cause(k.ctx) == ErrLeaseLost {
+		// the Lease was lost, so can be acquired again without being Closed
+		<-k.done
+		k.mu.Lock()
+		k.held = false
+		k.mu.Unlock()
+	}
+	if k.held {
+		return fmt.Errorf("the lease %q is already held by this keeper", k.options.LeaseID)
+	}
+	if err := k.lessor.AcquireLease(ctx, int(k.options.Duration/time.Second), k.options.LeaseID); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.held = true
+	k.ctx, k.cancel = context.WithCancelCause(context.Background())
+	k.stop = make(chan struct{})
+	k.done = make(chan struct{})
+	go k.renew(k.ctx, k.cancel, k.stop, k.done)
+	return nil
+}
+
+// Lock acquires the Lease, retrying with an exponential backoff whilst the resource is leased by another
+// client, until either the Lease is acquired or `ctx` is done.
+func (k *Keeper) Lock(ctx context.Context) error {
+	delay := k.options.RetryDelay
+	for {
+		err := k.TryLock(ctx)
+		if err == nil || !errors.Is(err, ErrLeaseAlreadyPresent) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting to acquire the lease: %+v", ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > k.options.MaxRetryDelay {
+			delay = k.options.MaxRetryDelay
+		}
+	}
+}
+
+// Close stops renewing the Lease and releases it, cancelling the Context. Close is a no-op if the Lease
+// isn't held - and the Lease can be acquired again afterwards using Lock.
+func (k *Keeper) Close() error {
+	end, _ := k.begin(context.Background())
+	defer end()
+
+	if !k.held {
+		return nil
+	}
+	k.mu.Lock()
+	k.held = false
+	k.mu.Unlock()
+
+	// the renewals are stopped without holding the mutex, since this waits for any renewal in progress to finish
+	close(k.stop)
+	<-k.done
+
+	lost := context.Cause(k.ctx) == ErrLeaseLost
+	k.cancel(ErrLeaseReleased)
+	if lost {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), k.options.Duration)
+	defer cancel()
+	if err := k.lessor.ReleaseLease(ctx, k.options.LeaseID); err != nil && !errors.Is(err, ErrLeaseLost) {
+		return fmt.Errorf("releasing lease %q: %+v", k.options.LeaseID, err)
+	}
+	return nil
+}
+
+// begin waits until no other call to TryLock or Close is acquiring or releasing the Lease (or `ctx` is done), so
+// that only one of these is in progress at a time - returning a function which must be called once it's complete.
+// Only the caller in progress modifies the fields of the Keeper, which it does whilst holding the mutex.
+func (k *Keeper) begin(ctx context.Context) (func(), error) {
+	for {
+		k.mu.Lock()
+		pending := k.pending
+		if pending == nil {
+			done := make(chan struct{})
+			k.pending = done
+			k.mu.Unlock()
+			return func() {
+				k.mu.Lock()
+				k.pending = nil
+				k.mu.Unlock()
+				close(done)
+			}, nil
+		}
+		k.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-pending:
+		}
+	}
+}
+
+// renew renews the Lease every RenewInterval until `stop` is closed - cancelling the Context if the Lease is
+// lost, or if it couldn't be renewed before it expired
+func (k *Keeper) renew(ctx context.Context, cancel context.CancelCauseFunc, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	expiresAt := time.Now().Add(k.options.Duration)
+	interval := k.options.RenewInterval
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+
+		// each attempt is bounded by the expiry of the Lease, after which it may have been acquired by another client
+		attemptCtx, attemptCancel := context.WithDeadline(ctx, expiresAt)
+		attemptStarted := time.Now()
+		err := k.lessor.RenewLease(attemptCtx, k.options.LeaseID)
+		attemptCancel()
+
+		switch {
+		case err == nil:
+			expiresAt = attemptStarted.Add(k.options.Duration)
+			interval = k.options.RenewInterval
+		case errors.Is(err, ErrLeaseLost) || !time.Now().Before(expiresAt):
+			cancel(ErrLeaseLost)
+			return
+		default:
+			// transient failures are retried more frequently, whilst the Lease is still held
+			interval = k.options.RenewInterval / 4
+		}
+	}
+}
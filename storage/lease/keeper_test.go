@@ -0,0 +1,258 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLessor is an in-memory Lessor, whose Lease can be taken by "another client" to simulate it being lost
+type fakeLessor struct {
+	mu       sync.Mutex
+	holder   string
+	renewals int
+	failures int
+
+	// renewing is optionally sent to when a renewal starts, which then blocks until `unblock` is closed
+	renewing chan struct{}
+	unblock  chan struct{}
+}
+
+func (f *fakeLessor) AcquireLease(_ context.Context, duration int, proposedLeaseId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if duration < 15 || duration > 60 {
+		return errors.New("invalid duration")
+	}
+	if f.holder != "" && f.holder != proposedLeaseId {
+		return ErrLeaseAlreadyPresent
+	}
+	f.holder = proposedLeaseId
+	return nil
+}
+
+func (f *fakeLessor) RenewLease(_ context.Context, leaseId string) error {
+	if f.renewing != nil {
+		select {
+		case f.renewing <- struct{}{}:
+		default:
+		}
+		<-f.unblock
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("connection reset")
+	}
+	if f.holder != leaseId {
+		return ErrLeaseLost
+	}
+	f.renewals++
+	return nil
+}
+
+func (f *fakeLessor) ReleaseLease(_ context.Context, leaseId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.holder != leaseId {
+		return ErrLeaseLost
+	}
+	f.holder = ""
+	return nil
+}
+
+func (f *fakeLessor) set(holder string, failures int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.holder = holder
+	f.failures = failures
+}
+
+func (f *fakeLessor) state() (string, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.holder, f.renewals
+}
+
+func TestNewKeeperValidation(t *testing.T) {
+	testData := []struct {
+		options Options
+		valid   bool
+	}{
+		{
+			options: Options{},
+			valid:   true,
+		},
+		{
+			options: Options{Duration: 15 * time.Second, RenewInterval: 5 * time.Second},
+			valid:   true,
+		},
+		{
+			options: Options{Duration: 10 * time.Second},
+			valid:   false,
+		},
+		{
+			options: Options{Duration: 90 * time.Second},
+			valid:   false,
+		},
+		{
+			options: Options{Duration: 30 * time.Second, RenewInterval: 30 * time.Second},
+			valid:   false,
+		},
+	}
+	for i, v := range testData {
+		t.Logf("[DEBUG] Testing %d", i)
+		_, err := NewKeeper(&fakeLessor{}, v.options)
+		if v.valid && err != nil {
+			t.Fatalf("expected the options to be valid but got: %+v", err)
+		}
+		if !v.valid && err == nil {
+			t.Fatalf("expected the options to be invalid but didn't get an error")
+		}
+	}
+}
+
+func TestKeeperRenewsUntilClosed(t *testing.T) {
+	lessor := &fakeLessor{}
+	keeper, err := NewKeeper(lessor, Options{Duration: 15 * time.Second, RenewInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("building keeper: %+v", err)
+	}
+	if keeper.Context().Err() == nil {
+		t.Fatalf("expected the context to be cancelled before the lease is acquired")
+	}
+
+	if err := keeper.Lock(context.Background()); err != nil {
+		t.Fatalf("acquiring lease: %+v", err)
+	}
+	ctx := keeper.Context()
+
+	// a transient failure to renew shouldn't lose the lease
+	lessor.set(keeper.LeaseID(), 1)
+	for {
+		if _, renewals := lessor.state(); renewals >= 3 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("expected the context to remain active whilst the lease is held")
+	}
+
+	if err := keeper.Close(); err != nil {
+		t.Fatalf("closing keeper: %+v", err)
+	}
+	if holder, _ := lessor.state(); holder != "" {
+		t.Fatalf("expected the lease to be released but it's held by %q", holder)
+	}
+	if !errors.Is(context.Cause(ctx), ErrLeaseReleased) {
+		t.Fatalf("expected the context to be cancelled with ErrLeaseReleased but got %+v", context.Cause(ctx))
+	}
+}
+
+func TestKeeperLostLease(t *testing.T) {
+	lessor := &fakeLessor{}
+	keeper, err := NewKeeper(lessor, Options{Duration: 15 * time.Second, RenewInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("building keeper: %+v", err)
+	}
+	if err := keeper.TryLock(context.Background()); err != nil {
+		t.Fatalf("acquiring lease: %+v", err)
+	}
+	ctx := keeper.Context()
+
+	lessor.set("another-client", 0)
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the context to be cancelled once the lease was lost")
+	}
+	if !errors.Is(context.Cause(ctx), ErrLeaseLost) {
+		t.Fatalf("expected the context to be cancelled with ErrLeaseLost but got %+v", context.Cause(ctx))
+	}
+
+	// the lease shouldn't be released, since it's held by another client
+	if err := keeper.Close(); err != nil {
+		t.Fatalf("closing keeper: %+v", err)
+	}
+	if holder, _ := lessor.state(); holder != "another-client" {
+		t.Fatalf("expected the lease to remain held by another client but got %q", holder)
+	}
+}
+
+func TestKeeperLockWaitsForLease(t *testing.T) {
+	lessor := &fakeLessor{}
+	lessor.set("another-client", 0)
+	keeper, err := NewKeeper(lessor, Options{RetryDelay: 5 * time.Millisecond, MaxRetryDelay: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("building keeper: %+v", err)
+	}
+	defer keeper.Close()
+
+	if err := keeper.TryLock(context.Background()); !errors.Is(err, ErrLeaseAlreadyPresent) {
+		t.Fatalf("expected ErrLeaseAlreadyPresent but got: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := keeper.Lock(ctx); err == nil {
+		t.Fatalf("expected an error when the context is done before the lease is acquired")
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		lessor.set("", 0)
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := keeper.Lock(ctx); err != nil {
+		t.Fatalf("acquiring lease: %+v", err)
+	}
+	if holder, _ := lessor.state(); holder != keeper.LeaseID() {
+		t.Fatalf("expected the lease to be held by %q but got %q", keeper.LeaseID(), holder)
+	}
+}
+
+func TestKeeperCloseDoesNotBlockContext(t *testing.T) {
+	lessor := &fakeLessor{
+		renewing: make(chan struct{}, 1),
+		unblock:  make(chan struct{}),
+	}
+	keeper, err := NewKeeper(lessor, Options{Duration: 15 * time.Second, RenewInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("building keeper: %+v", err)
+	}
+	if err := keeper.TryLock(context.Background()); err != nil {
+		t.Fatalf("acquiring lease: %+v", err)
+	}
+	<-lessor.renewing
+
+	// Close waits for the renewal in progress to finish, during which the Context should remain available
+	closed := make(chan error, 1)
+	go func() {
+		closed <- keeper.Close()
+	}()
+	time.Sleep(50 * time.Millisecond)
+	contextReturned := make(chan struct{})
+	go func() {
+		keeper.Context()
+		close(contextReturned)
+	}()
+	select {
+	case <-contextReturned:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected Context to return whilst the keeper was being closed")
+	}
+
+	close(lessor.unblock)
+	if err := <-closed; err != nil {
+		t.Fatalf("closing keeper: %+v", err)
+	}
+	if holder, _ := lessor.state(); holder != "" {
+		t.Fatalf("expected the lease to be released but it's held by %q", holder)
+	}
+}
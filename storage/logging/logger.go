@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+)
+
+// Logger is the interface used to write log messages, which is satisfied by `*slog.Logger`.
+type Logger interface {
+	Log(ctx context.Context, level slog.Level, msg string, args ...any)
+}
+
+// RequestLogger logs each operation performed by a client, along with any progress messages from the helper
+// methods (such as `files.PutFile`). Secrets within the URI and headers are redacted before being logged.
+//
+// A RequestLogger can be set on any client (for example `blobs.Client.Logger`) - when unset nothing is logged.
+type RequestLogger struct {
+	// Logger is the Logger which messages are written to.
+	Logger Logger
+
+	// RequestLevel is the level at which successful requests are logged.
+	RequestLevel slog.Level
+
+	// FailureLevel is the level at which failed requests are logged.
+	FailureLevel slog.Level
+
+	// IncludeHeaders specifies whether the (redacted) request and response headers are logged.
+	IncludeHeaders bool
+
+	mu      sync.Mutex
+	started map[*http.Request]time.Time
+}
+
+// New returns a RequestLogger which writes to `logger`, logging successful requests at the Debug level
+// and failed requests at the Warn level.
+func New(logger Logger) *RequestLogger {
+	return &RequestLogger{
+		Logger:       logger,
+		RequestLevel: slog.LevelDebug,
+		FailureLevel: slog.LevelWarn,
+	}
+}
+
+// Log writes a message to the Logger. This is a no-op when the RequestLogger is nil.
+func (l *RequestLogger) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if l == nil || l.Logger == nil {
+		return
+	}
+	l.Logger.Log(ctx, level, msg, args...)
+}
+
+// Execute performs the request using `execute`, logging the outcome of the operation once it's completed.
+func (l *RequestLogger) Execute(ctx context.Context, req *client.Request, operation string, execute func(ctx context.Context) (*client.Response, error)) (*client.Response, error) {
+	if l == nil || l.Logger == nil {
+		return execute(ctx)
+	}
+
+	start := time.Now()
+	resp, err := execute(ctx)
+
+	var httpResp *http.Response
+	if resp != nil {
+		httpResp = resp.Response
+	}
+	args := append([]any{slog.String("operation", operation)}, l.attributes(req.Request, httpResp, time.Since(start))...)
+	if err != nil {
+		l.Logger.Log(ctx, l.FailureLevel, "request failed", append(args, slog.String("error", err.Error()))...)
+	} else {
+		l.Logger.Log(ctx, l.RequestLevel, "request completed", args...)
+	}
+	return resp, err
+}
+
+// Attach configures the base client to log each HTTP request it sends - which can be used to log requests
+// from clients which aren't part of this SDK (e.g. Resource Manager clients).
+func (l *RequestLogger) Attach(c *client.Client) {
+	c.AppendRequestMiddleware(func(req *http.Request) (*http.Request, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.started == nil {
+			l.started = make(map[*http.Request]time.Time)
+		}
+		l.started[req] = time.Now()
+		return req, nil
+	})
+	c.AppendResponseMiddleware(func(req *http.Request, resp *http.Response) (*http.Response, error) {
+		l.mu.Lock()
+		start, ok := l.started[req]
+		delete(l.started, req)
+		l.mu.Unlock()
+
+		var duration time.Duration
+		if ok {
+			duration = time.Since(start)
+		}
+		level := l.RequestLevel
+		if resp != nil && resp.StatusCode >= 400 {
+			level = l.FailureLevel
+		}
+		l.Log(req.Context(), level, "request completed", l.attributes(req, resp, duration)...)
+		return resp, nil
+	})
+}
+
+func (l *RequestLogger) attributes(req *http.Request, resp *http.Response, duration time.Duration) []any {
+	args := []any{
+		slog.String("method", req.Method),
+		slog.String("url", RedactURL(req.URL)),
+		slog.Duration("duration", duration),
+	}
+	if v := req.Header.Get("x-ms-client-request-id"); v != "" {
+		args = append(args, slog.String("client_request_id", v))
+	}
+	if l.IncludeHeaders {
+		args = append(args, slog.Any("request_headers", RedactHeaders(req.Header)))
+	}
+
+	if resp != nil {
+		args = append(args, slog.Int("status", resp.StatusCode))
+		if v := resp.Header.Get("x-ms-request-id"); v != "" {
+			args = append(args, slog.String("request_id", v))
+		}
+		if v := resp.Header.Get("x-ms-error-code"); v != "" {
+			args = append(args, slog.String("error_code", v))
+		}
+		if l.IncludeHeaders {
+			args = append(args, slog.Any("response_headers", RedactHeaders(resp.Header)))
+		}
+	}
+	return args
+}
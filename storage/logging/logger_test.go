@@ -0,0 +1,128 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/blobs"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+	"github.com/tombuildsstuff/giovanni/storage/logging"
+)
+
+func TestRequestLogger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var buf bytes.Buffer
+	logger := logging.New(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	logger.IncludeHeaders = true
+
+	server := blobserver.New(t)
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	containersClient.Logger = logger
+
+	blobsClient, err := blobs.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobsClient.Client.SetAuthorizer(authorizer)
+	blobsClient.Logger = logger
+
+	if _, err := containersClient.Create(ctx, "container1", containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	if _, err := blobsClient.PutBlockBlob(ctx, "container1", "source.txt", blobs.PutBlockBlobInput{Content: pointer.To([]byte("hello"))}); err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	copyInput := blobs.CopyInput{
+		CopySource: server.BaseUri() + "/container1/source.txt?sv=2023-11-03&sig=supersecret",
+	}
+	if _, err := blobsClient.Copy(ctx, "container1", "destination.txt", copyInput); err != nil {
+		t.Fatalf("copying blob: %+v", err)
+	}
+	if _, err := blobsClient.GetProperties(ctx, "container1", "missing.txt", blobs.GetPropertiesInput{}); err == nil {
+		t.Fatalf("expected an error retrieving a missing blob but didn't get one")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 log lines but got %d:\n%s", len(lines), buf.String())
+	}
+	for i, expected := range []string{"operation=containers.Create", "operation=blobs.PutBlockBlob", "operation=blobs.Copy", "operation=blobs.GetProperties"} {
+		if !strings.Contains(lines[i], expected) {
+			t.Fatalf("expected line %d to contain %q but got: %s", i, expected, lines[i])
+		}
+	}
+	if !strings.Contains(lines[1], "level=DEBUG") || !strings.Contains(lines[1], "status=201") || !strings.Contains(lines[1], "method=PUT") || !strings.Contains(lines[1], "request_id=") {
+		t.Fatalf("expected the successful request to be logged at the Debug level with the method, status and request id but got: %s", lines[1])
+	}
+	if !strings.Contains(lines[3], "level=WARN") || !strings.Contains(lines[3], "status=404") || !strings.Contains(lines[3], "error_code=BlobNotFound") {
+		t.Fatalf("expected the failed request to be logged at the Warn level with the status and error code but got: %s", lines[3])
+	}
+	if strings.Contains(buf.String(), "supersecret") {
+		t.Fatalf("expected the SAS signature to be redacted but got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "SharedKey ") {
+		t.Fatalf("expected the Authorization header to be redacted but got:\n%s", buf.String())
+	}
+}
+
+func TestRequestLoggerNil(t *testing.T) {
+	var logger *logging.RequestLogger
+	// this mustn't panic
+	logger.Log(context.Background(), slog.LevelInfo, "hello")
+}
+
+func TestRedactURL(t *testing.T) {
+	testData := map[string]string{
+		"https://example.blob.core.windows.net/container/blob":                          "https://example.blob.core.windows.net/container/blob",
+		"https://example.blob.core.windows.net/container/blob?comp=block&blockid=abc":   "https://example.blob.core.windows.net/container/blob?blockid=abc&comp=block",
+		"https://example.blob.core.windows.net/container/blob?sv=2023-11-03&sig=abc%3D": "https://example.blob.core.windows.net/container/blob?sig=REDACTED&sv=2023-11-03",
+	}
+	for input, expected := range testData {
+		uri, err := url.Parse(input)
+		if err != nil {
+			t.Fatalf("parsing %q: %+v", input, err)
+		}
+		if actual := logging.RedactURL(uri); actual != expected {
+			t.Fatalf("expected %q but got %q", expected, actual)
+		}
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	input := http.Header{
+		"Authorization":                  []string{"SharedKey account:signature"},
+		"X-Ms-Copy-Source":               []string{"https://example.blob.core.windows.net/container/blob?sv=2023-11-03&sig=abc"},
+		"X-Ms-Copy-Source-Authorization": []string{"Bearer token"},
+		"X-Ms-Version":                   []string{"2023-11-03"},
+	}
+	expected := map[string]string{
+		"Authorization":                  logging.Redacted,
+		"X-Ms-Copy-Source":               "https://example.blob.core.windows.net/container/blob?sig=REDACTED&sv=2023-11-03",
+		"X-Ms-Copy-Source-Authorization": logging.Redacted,
+		"X-Ms-Version":                   "2023-11-03",
+	}
+	actual := logging.RedactHeaders(input)
+	for k, v := range expected {
+		if actual[k] != v {
+			t.Fatalf("expected the header %q to be %q but got %q", k, v, actual[k])
+		}
+	}
+}
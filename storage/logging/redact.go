@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted is logged in place of any secret
+const Redacted = "REDACTED"
+
+// redactedHeaders are the headers whose values are credentials, and are redacted in their entirety
+var redactedHeaders = map[string]struct{}{
+	"authorization":                  {},
+	"x-ms-copy-source-authorization": {},
+	"x-ms-encryption-key":            {},
+}
+
+// redactedQueryParameters are the query string parameters whose values are credentials (such as the
+// signature of a SAS Token)
+var redactedQueryParameters = map[string]struct{}{
+	"sig": {},
+}
+
+// RedactURL returns the URI with the values of any secrets within the query string (such as the
+// signature of a SAS Token) redacted.
+func RedactURL(uri *url.URL) string {
+	if uri == nil {
+		return ""
+	}
+	if uri.RawQuery == "" {
+		return uri.String()
+	}
+
+	redacted := *uri
+	values := uri.Query()
+	for k := range values {
+		if _, ok := redactedQueryParameters[strings.ToLower(k)]; ok {
+			values.Set(k, Redacted)
+		}
+	}
+	redacted.RawQuery = values.Encode()
+	return redacted.String()
+}
+
+// RedactHeaders returns a copy of the headers with any credentials redacted - including the signature of
+// any SAS Token within a header containing a URI (such as `x-ms-copy-source`).
+func RedactHeaders(input http.Header) map[string]string {
+	out := make(map[string]string, len(input))
+	for k, values := range input {
+		value := strings.Join(values, ", ")
+		if _, ok := redactedHeaders[strings.ToLower(k)]; ok {
+			value = Redacted
+		} else if strings.Contains(value, "sig=") {
+			if uri, err := url.Parse(value); err == nil && uri.Scheme != "" {
+				value = RedactURL(uri)
+			}
+		}
+		out[k] = value
+	}
+	return out
+}
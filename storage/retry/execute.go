@@ -0,0 +1,149 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+)
+
+type safeToRetryKey struct{}
+
+// WithSafeToRetry returns a copy of `ctx` which marks requests sent using it as safe to resend when no response
+// is received (e.g. as the connection was reset). By default this is only the case for GET, HEAD and OPTIONS
+// requests, since a request which wasn't idempotent (for example appending a block or inserting an entity) may
+// have been processed before the connection was lost.
+func WithSafeToRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, safeToRetryKey{}, true)
+}
+
+// Execute sends the request using the specified Policy - or when `policy` is nil, using the default retry
+// behaviour of the base layer.
+func Execute(ctx context.Context, req *client.Request, policy *Policy) (*client.Response, error) {
+	if policy == nil {
+		return req.Execute(ctx)
+	}
+	return policy.execute(ctx, req)
+}
+
+func (p Policy) execute(ctx context.Context, req *client.Request) (*client.Response, error) {
+	// the body is buffered so that it can be sent on each attempt
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %+v", err)
+		}
+		req.Body.Close()
+	}
+
+	// each attempt is sent once, without the retries performed by the base layer, so that only the Policy
+	// determines whether (and how many times) the request is retried
+	base := baseClientFor(req.Client)
+
+	original := req.Request
+	secondary := ""
+	if p.UseSecondaryForReads && (original.Method == http.MethodGet || original.Method == http.MethodHead) {
+		secondary = secondaryHost(original.URL.Host)
+	}
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	useSecondary := false
+	for attempt := 1; ; attempt++ {
+		// each attempt is made using a copy of the original request, since it's modified when it's authorized and sent
+		attemptReq := original.Clone(ctx)
+		attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+		attemptReq.ContentLength = int64(len(body))
+		attemptReq.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		if body == nil {
+			attemptReq.Body = nil
+			attemptReq.GetBody = nil
+		}
+		if useSecondary {
+			attemptReq.URL.Host = secondary
+			attemptReq.Host = secondary
+		}
+		req.Request = attemptReq
+
+		var resp *client.Response
+		var err error
+		if base != nil {
+			resp, err = send(ctx, p.httpClient(), base, req)
+		} else {
+			resp, err = req.Execute(ctx)
+		}
+		if err == nil || resp == nil {
+			// when no response is returned the request wasn't sent (e.g. it couldn't be authorized), so isn't retried
+			return resp, err
+		}
+
+		httpResp := resp.Response
+		if httpResp == nil && !safeToRetry(ctx, original.Method) {
+			// the request may have been processed before the connection was lost, so resending it could
+			// (for example) append the same block twice
+			return resp, err
+		}
+		if useSecondary && httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+			// the data may not have been replicated to the secondary yet, so only use the primary from here on
+			secondary = ""
+		} else if !p.shouldRetry(httpResp) {
+			return resp, err
+		}
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if httpResp != nil && httpResp.Body != nil {
+			io.Copy(io.Discard, httpResp.Body)
+			httpResp.Body.Close()
+		}
+
+		timer := time.NewTimer(p.delay(attempt, httpResp))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		case <-timer.C:
+		}
+
+		useSecondary = secondary != "" && !useSecondary
+	}
+}
+
+// safeToRetry returns whether a request which didn't receive a response can be resent - which is the case for
+// idempotent methods, or when the request has been marked as safe to retry using WithSafeToRetry
+func safeToRetry(ctx context.Context, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	v, ok := ctx.Value(safeToRetryKey{}).(bool)
+	return ok && v
+}
+
+// secondaryHost returns the host for the secondary endpoint of a Read-Access Geo-Redundant Storage Account,
+// (e.g. `example-secondary.blob.core.windows.net` for `example.blob.core.windows.net`) - or an empty string
+// when the host doesn't have a secondary endpoint (e.g. for a Storage Emulator).
+func secondaryHost(host string) string {
+	if strings.Contains(host, ":") || net.ParseIP(host) != nil {
+		return ""
+	}
+	account, domain, ok := strings.Cut(host, ".")
+	if !ok || account == "" || strings.HasSuffix(account, "-secondary") || domain == "localhost" {
+		return ""
+	}
+	return fmt.Sprintf("%s-secondary.%s", account, domain)
+}
@@ -0,0 +1,387 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/client/dataplane/storage"
+)
+
+type recordedRequest struct {
+	method string
+	body   string
+}
+
+// testServer returns the status codes (and error codes) in order, returning a 201 once they've been exhausted
+type testServer struct {
+	responses []testResponse
+
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+type testResponse struct {
+	statusCode int
+	errorCode  string
+	retryAfter string
+
+	// resetConnection closes the connection without returning a response
+	resetConnection bool
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attempt := len(s.requests)
+	s.requests = append(s.requests, recordedRequest{
+		method: r.Method,
+		body:   string(body),
+	})
+
+	if attempt >= len(s.responses) {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	resp := s.responses[attempt]
+	if resp.resetConnection {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+	if resp.errorCode != "" {
+		w.Header().Set("x-ms-error-code", resp.errorCode)
+	}
+	if resp.retryAfter != "" {
+		w.Header().Set("Retry-After", resp.retryAfter)
+	}
+	w.WriteHeader(resp.statusCode)
+	w.Write([]byte("<?xml version=\"1.0\" encoding=\"utf-8\"?><Error><Code>" + resp.errorCode + "</Code><Message>oops</Message></Error>"))
+}
+
+// requestCount returns the number of requests which have been received
+func (s *testServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// recorded returns a copy of the requests which have been received
+func (s *testServer) recorded() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]recordedRequest{}, s.requests...)
+}
+
+func sendRequest(t *testing.T, baseUri string, policy *Policy, body []byte) (*client.Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return sendRequestWithMethod(t, ctx, baseUri, http.MethodPut, policy, body)
+}
+
+func sendRequestWithMethod(t *testing.T, ctx context.Context, baseUri, method string, policy *Policy, body []byte) (*client.Response, error) {
+	c, err := storage.NewStorageClient(baseUri, "blob", "2023-11-03")
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	req, err := c.NewRequest(ctx, client.RequestOptions{
+		ExpectedStatusCodes: []int{http.StatusCreated},
+		HttpMethod:          method,
+		Path:                "/container/blob",
+	})
+	if err != nil {
+		t.Fatalf("building request: %+v", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return Execute(ctx, req, policy)
+}
+
+func testPolicy() *Policy {
+	policy := DefaultPolicy()
+	policy.BaseDelay = 1 * time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+	return policy
+}
+
+func TestExecuteRetriesTransientErrors(t *testing.T) {
+	s := &testServer{
+		responses: []testResponse{
+			{statusCode: http.StatusServiceUnavailable, errorCode: "ServerBusy"},
+			{statusCode: http.StatusInternalServerError, errorCode: "OperationTimedOut"},
+		},
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	resp, err := sendRequest(t, server.URL, testPolicy(), []byte("hello world"))
+	if err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected a 201 but got %d", resp.StatusCode)
+	}
+	if s.requestCount() != 3 {
+		t.Fatalf("expected 3 attempts but got %d", s.requestCount())
+	}
+	for i, v := range s.recorded() {
+		if v.body != "hello world" {
+			t.Fatalf("expected attempt %d to send the body `hello world` but got %q", i+1, v.body)
+		}
+	}
+}
+
+func TestExecuteRetriesErrorCodes(t *testing.T) {
+	s := &testServer{
+		responses: []testResponse{
+			{statusCode: http.StatusConflict, errorCode: "OperationBlockedByPendingCopy"},
+		},
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	policy := testPolicy()
+	policy.RetryableErrorCodes = append(policy.RetryableErrorCodes, "OperationBlockedByPendingCopy")
+	if _, err := sendRequest(t, server.URL, policy, nil); err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+	if s.requestCount() != 2 {
+		t.Fatalf("expected 2 attempts but got %d", s.requestCount())
+	}
+}
+
+func TestExecuteDoesNotRetryOtherErrors(t *testing.T) {
+	s := &testServer{
+		responses: []testResponse{
+			{statusCode: http.StatusForbidden, errorCode: "AuthorizationFailure"},
+		},
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	resp, err := sendRequest(t, server.URL, testPolicy(), nil)
+	if err == nil {
+		t.Fatalf("expected an error but didn't get one")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected the 403 response to be returned")
+	}
+	if s.requestCount() != 1 {
+		t.Fatalf("expected 1 attempt but got %d", s.requestCount())
+	}
+}
+
+func TestExecuteStopsAfterMaxAttempts(t *testing.T) {
+	responses := make([]testResponse, 0)
+	for i := 0; i < 10; i++ {
+		responses = append(responses, testResponse{statusCode: http.StatusServiceUnavailable, errorCode: "ServerBusy"})
+	}
+	s := &testServer{
+		responses: responses,
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	policy := testPolicy()
+	policy.MaxAttempts = 3
+	resp, err := sendRequest(t, server.URL, policy, []byte("hello"))
+	if err == nil {
+		t.Fatalf("expected an error but didn't get one")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the final 503 response to be returned")
+	}
+	// the base layer mustn't retry these itself
+	if s.requestCount() != 3 {
+		t.Fatalf("expected 3 attempts but got %d", s.requestCount())
+	}
+}
+
+func TestExecuteRequestTimeoutUsesPolicy(t *testing.T) {
+	responses := make([]testResponse, 0)
+	for i := 0; i < 10; i++ {
+		responses = append(responses, testResponse{statusCode: http.StatusRequestTimeout, errorCode: "OperationTimedOut"})
+	}
+	s := &testServer{
+		responses: responses,
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	policy := testPolicy()
+	policy.MaxAttempts = 2
+	resp, err := sendRequest(t, server.URL, policy, nil)
+	if err == nil {
+		t.Fatalf("expected an error but didn't get one")
+	}
+	if resp == nil || resp.StatusCode != http.StatusRequestTimeout {
+		t.Fatalf("expected the final 408 response to be returned")
+	}
+	// the base layer retries a 408 itself, which mustn't happen when using a Policy
+	if s.requestCount() != 2 {
+		t.Fatalf("expected 2 attempts but got %d", s.requestCount())
+	}
+}
+
+func TestExecuteConnectionReset(t *testing.T) {
+	testData := []struct {
+		name             string
+		method           string
+		safeToRetry      bool
+		expectedAttempts int
+	}{
+		{
+			name:             "non-idempotent request",
+			method:           http.MethodPut,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "non-idempotent request marked as safe to retry",
+			method:           http.MethodPut,
+			safeToRetry:      true,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "idempotent request",
+			method:           http.MethodGet,
+			expectedAttempts: 2,
+		},
+	}
+	for _, v := range testData {
+		t.Run(v.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			s := &testServer{
+				responses: []testResponse{
+					{resetConnection: true},
+				},
+			}
+			server := httptest.NewServer(s)
+			defer server.Close()
+
+			if v.safeToRetry {
+				ctx = WithSafeToRetry(ctx)
+			}
+			_, err := sendRequestWithMethod(t, ctx, server.URL, v.method, testPolicy(), nil)
+			if v.expectedAttempts == 1 && err == nil {
+				t.Fatalf("expected an error but didn't get one")
+			}
+			if v.expectedAttempts > 1 && err != nil {
+				t.Fatalf("sending request: %+v", err)
+			}
+			if s.requestCount() != v.expectedAttempts {
+				t.Fatalf("expected %d attempts but got %d", v.expectedAttempts, s.requestCount())
+			}
+		})
+	}
+}
+
+func TestExecuteHonoursRetryAfter(t *testing.T) {
+	s := &testServer{
+		responses: []testResponse{
+			{statusCode: http.StatusTooManyRequests, retryAfter: "1"},
+		},
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	start := time.Now()
+	if _, err := sendRequest(t, server.URL, testPolicy(), nil); err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 1*time.Second {
+		t.Fatalf("expected the `Retry-After` header to be honoured, but the request completed in %s", elapsed)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	policy := Policy{
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  10 * time.Second,
+		Jitter:    0.5,
+	}
+	testData := []struct {
+		retry int
+		min   time.Duration
+		max   time.Duration
+	}{
+		{retry: 1, min: 500 * time.Millisecond, max: 1 * time.Second},
+		{retry: 2, min: 1 * time.Second, max: 2 * time.Second},
+		{retry: 3, min: 2 * time.Second, max: 4 * time.Second},
+		{retry: 10, min: 5 * time.Second, max: 10 * time.Second},
+	}
+	for _, v := range testData {
+		for i := 0; i < 100; i++ {
+			actual := policy.delay(v.retry, nil)
+			if actual < v.min || actual > v.max {
+				t.Fatalf("expected the delay for retry %d to be between %s and %s but got %s", v.retry, v.min, v.max, actual)
+			}
+		}
+	}
+
+	resp := &http.Response{
+		Header: http.Header{
+			"Retry-After": []string{"30"},
+		},
+	}
+	if actual := policy.delay(1, resp); actual != 30*time.Second {
+		t.Fatalf("expected the delay to be 30s from the `Retry-After` header but got %s", actual)
+	}
+}
+
+func TestSecondaryHost(t *testing.T) {
+	testData := map[string]string{
+		"example.blob.core.windows.net":           "example-secondary.blob.core.windows.net",
+		"example.queue.core.chinacloudapi.cn":     "example-secondary.queue.core.chinacloudapi.cn",
+		"example-secondary.blob.core.windows.net": "",
+		"127.0.0.1":         "",
+		"127.0.0.1:10000":   "",
+		"example.localhost": "",
+		"localhost":         "",
+	}
+	for input, expected := range testData {
+		if actual := secondaryHost(input); actual != expected {
+			t.Fatalf("expected the secondary host for %q to be %q but got %q", input, expected, actual)
+		}
+	}
+}
+
+type countingTransport struct {
+	count int
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.count++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestExecuteUsesHTTPClient(t *testing.T) {
+	s := &testServer{
+		responses: []testResponse{
+			{statusCode: http.StatusServiceUnavailable, errorCode: "ServerBusy"},
+		},
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	transport := &countingTransport{}
+	policy := testPolicy()
+	policy.HTTPClient = &http.Client{Transport: transport}
+	if _, err := sendRequest(t, server.URL, policy, nil); err != nil {
+		t.Fatalf("sending request: %+v", err)
+	}
+	if transport.count != 2 {
+		t.Fatalf("expected both attempts to be sent using the HTTP Client but got %d", transport.count)
+	}
+}
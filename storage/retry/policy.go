@@ -0,0 +1,129 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Policy configures how failed requests are retried by a client.
+//
+// A Policy can be set on any client (for example `blobs.Client.RetryPolicy`) and replaces the retry behaviour
+// of the base layer entirely - each attempt is sent once, so that the Policy alone determines whether a request
+// is retried. Requests are retried when either the status code is one of the RetryableStatusCodes or the
+// `x-ms-error-code` is one of the RetryableErrorCodes. Requests which don't receive a response (e.g. as the
+// connection was reset) are only retried when they're idempotent (GET, HEAD and OPTIONS requests), or have been
+// marked as safe to retry using WithSafeToRetry. Request bodies are buffered, so that each attempt sends the same body.
+type Policy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including the first attempt.
+	MaxAttempts int
+
+	// RetryableStatusCodes are the HTTP Status Codes which are retried.
+	RetryableStatusCodes []int
+
+	// RetryableErrorCodes are the Storage Error Codes (returned in the `x-ms-error-code` header) which are
+	// retried, regardless of the HTTP Status Code.
+	RetryableErrorCodes []string
+
+	// BaseDelay is the delay before the first retry, which is doubled for each subsequent retry.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay between attempts - unless a longer delay is requested by the API
+	// using the `Retry-After` header.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (between 0 and 1) of each delay which is randomised, to avoid concurrent
+	// clients retrying in lockstep. For example a Jitter of 0.5 waits between 50% and 100% of the delay.
+	Jitter float64
+
+	// UseSecondaryForReads specifies whether GET and HEAD requests which fail are retried against the
+	// secondary endpoint of a Read-Access Geo-Redundant Storage Account (`{account}-secondary.{service}`),
+	// alternating between the primary and secondary endpoints. Should the secondary endpoint return a
+	// 404 (e.g. as the data hasn't been replicated yet) the remaining attempts use the primary endpoint.
+	UseSecondaryForReads bool
+
+	// HTTPClient optionally specifies the HTTP Client used to send each attempt. Defaults to an HTTP Client whose
+	// transport is shared by all Policies, so that connections are reused across requests and clients.
+	HTTPClient *http.Client
+}
+
+// DefaultPolicy returns a Policy which retries requests up to 5 times with exponential backoff, for the
+// status/error codes which the Storage API documents as transient.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MaxAttempts: 5,
+		RetryableStatusCodes: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableErrorCodes: []string{
+			"InternalError",
+			"OperationTimedOut",
+			"ServerBusy",
+		},
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  60 * time.Second,
+		Jitter:    0.5,
+	}
+}
+
+// shouldRetry returns whether a request which returned `resp` (which is nil if no response was received)
+// should be retried
+func (p Policy) shouldRetry(resp *http.Response) bool {
+	if resp == nil {
+		return true
+	}
+	for _, v := range p.RetryableStatusCodes {
+		if resp.StatusCode == v {
+			return true
+		}
+	}
+	if code := resp.Header.Get("x-ms-error-code"); code != "" {
+		for _, v := range p.RetryableErrorCodes {
+			if code == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before the specified retry (starting at 1), honouring any `Retry-After`
+// header within the previous response
+func (p Policy) delay(retry int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if v := retryAfter(resp.Header.Get("Retry-After")); v > 0 {
+			return v
+		}
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay = delay * (1 - jitter*rand.Float64())
+	}
+	return time.Duration(delay)
+}
+
+// retryAfter parses the value of a `Retry-After` header, which is either a number of seconds or an HTTP Date
+func retryAfter(input string) time.Duration {
+	if input == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(input); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(input); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
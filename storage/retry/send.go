@@ -0,0 +1,156 @@
+package retry
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/auth"
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"github.com/hashicorp/go-azure-sdk/sdk/client/dataplane"
+	"github.com/hashicorp/go-azure-sdk/sdk/client/dataplane/storage"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+// sharedHttpClient is used to send each attempt of a request which is retried using a Policy without an HTTPClient.
+// Unlike the base layer (which creates a new transport for each request) this is shared, so that connections are
+// reused across requests.
+var sharedHttpClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConnsPerHost:   runtime.GOMAXPROCS(0) + 1,
+	},
+}
+
+// httpClient returns the HTTP Client used to send each attempt of a request
+func (p Policy) httpClient() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+	return sharedHttpClient
+}
+
+// baseClientFor returns the base client used to send requests for `c` - or nil when this isn't known, in which
+// case the request is sent using the base layer (including its own retries)
+func baseClientFor(c client.BaseClient) *client.Client {
+	switch v := c.(type) {
+	case *storage.Client:
+		if v.Client != nil {
+			return v.Client.Client
+		}
+	case *dataplane.Client:
+		return v.Client
+	case *client.Client:
+		return v
+	}
+	return nil
+}
+
+// send sends a single attempt of the request - authorizing it, calling any middlewares and validating the
+// response in the same way as the base layer, but without the base layer retrying the request itself (for
+// example for a 408, or when no response is received).
+func send(ctx context.Context, httpClient *http.Client, base *client.Client, req *client.Request) (*client.Response, error) {
+	if base.AuthorizeRequest != nil {
+		if err := base.AuthorizeRequest(ctx, req.Request, base.Authorizer); err != nil {
+			return nil, fmt.Errorf("authorizing request: %+v", err)
+		}
+	} else if base.Authorizer != nil {
+		if err := auth.SetAuthHeader(ctx, req.Request, base.Authorizer); err != nil {
+			return nil, fmt.Errorf("authorizing request: %+v", err)
+		}
+	}
+
+	if base.RequestMiddlewares != nil {
+		for _, m := range *base.RequestMiddlewares {
+			r, err := m(req.Request)
+			if err != nil {
+				return nil, err
+			}
+			req.Request = r
+		}
+	}
+
+	var err error
+	resp := &client.Response{}
+	resp.Response, err = httpClient.Do(req.Request)
+	if err != nil {
+		return resp, err
+	}
+
+	if base.ResponseMiddlewares != nil {
+		for _, m := range *base.ResponseMiddlewares {
+			r, err := m(req.Request, resp.Response)
+			if err != nil {
+				return resp, err
+			}
+			resp.Response = r
+		}
+	}
+
+	// as with the base layer, any errors extracting OData are ignored since this isn't crucial at this point
+	resp.OData, _ = odata.FromResponse(resp.Response)
+
+	for _, v := range req.ValidStatusCodes {
+		if resp.StatusCode == v {
+			return resp, nil
+		}
+	}
+	if f := req.ValidStatusFunc; f != nil && f(resp.Response, resp.OData) {
+		return resp, nil
+	}
+	return resp, unexpectedStatusError(req, resp)
+}
+
+// unexpectedStatusError returns an error describing a response with an unexpected status code, in the same
+// format as the base layer
+func unexpectedStatusError(req *client.Request, resp *client.Response) error {
+	status := fmt.Sprintf("%d", resp.StatusCode)
+	statusText := resp.Status
+	if statusText == "" {
+		statusText = http.StatusText(resp.StatusCode)
+	}
+	if statusText != "" {
+		status = fmt.Sprintf("%s (%s)", status, statusText)
+	}
+
+	errText := ""
+	if req.CustomErrorParser != nil {
+		if err := req.CustomErrorParser.FromResponse(resp.Response); err != nil {
+			errText = err.Error()
+		}
+	}
+	if errText == "" {
+		if resp.OData != nil && resp.OData.Error != nil && resp.OData.Error.String() != "" {
+			errText = fmt.Sprintf("error: %s", resp.OData.Error)
+		} else {
+			defer resp.Body.Close()
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return fmt.Errorf("unexpected status %s, could not read response body", status)
+			}
+			if len(respBody) == 0 {
+				return fmt.Errorf("unexpected status %s received with no body", status)
+			}
+			errText = fmt.Sprintf("response: %s", respBody)
+		}
+	}
+
+	return fmt.Errorf("unexpected status %s with %s", status, errText)
+}
@@ -0,0 +1,112 @@
+package tablefilter
+
+import (
+	"strings"
+)
+
+// Filter is an OData filter expression which can be used to query Tables and Table Entities.
+//
+// The zero value is an empty Filter, which matches everything - and is ignored when combined using And/Or.
+type Filter struct {
+	expression string
+}
+
+// String returns the OData representation of this Filter, for use in `entities.QueryEntitiesInput.Filter`
+// and `tables.QueryInput.Filter`.
+func (f Filter) String() string {
+	return f.expression
+}
+
+// IsEmpty returns whether this Filter contains an expression.
+func (f Filter) IsEmpty() bool {
+	return f.expression == ""
+}
+
+// And returns a Filter matching both this Filter and `other`.
+func (f Filter) And(other Filter) Filter {
+	return And(f, other)
+}
+
+// Or returns a Filter matching either this Filter or `other`.
+func (f Filter) Or(other Filter) Filter {
+	return Or(f, other)
+}
+
+// And returns a Filter matching all of the specified Filters.
+func And(filters ...Filter) Filter {
+	return join("and", filters)
+}
+
+// Or returns a Filter matching any of the specified Filters.
+func Or(filters ...Filter) Filter {
+	return join("or", filters)
+}
+
+// Not returns a Filter matching anything which isn't matched by `filter`.
+func Not(filter Filter) Filter {
+	if filter.IsEmpty() {
+		return filter
+	}
+	return Filter{
+		expression: "not (" + filter.expression + ")",
+	}
+}
+
+// Equal returns a Filter matching when the property `name` is equal to `value`.
+func Equal(name string, value Literal) Filter {
+	return compare(name, "eq", value)
+}
+
+// NotEqual returns a Filter matching when the property `name` is not equal to `value`.
+func NotEqual(name string, value Literal) Filter {
+	return compare(name, "ne", value)
+}
+
+// GreaterThan returns a Filter matching when the property `name` is greater than `value`.
+func GreaterThan(name string, value Literal) Filter {
+	return compare(name, "gt", value)
+}
+
+// GreaterThanOrEqual returns a Filter matching when the property `name` is greater than or equal to `value`.
+func GreaterThanOrEqual(name string, value Literal) Filter {
+	return compare(name, "ge", value)
+}
+
+// LessThan returns a Filter matching when the property `name` is less than `value`.
+func LessThan(name string, value Literal) Filter {
+	return compare(name, "lt", value)
+}
+
+// LessThanOrEqual returns a Filter matching when the property `name` is less than or equal to `value`.
+func LessThanOrEqual(name string, value Literal) Filter {
+	return compare(name, "le", value)
+}
+
+func compare(name, operator string, value Literal) Filter {
+	return Filter{
+		expression: strings.Join([]string{name, operator, value.value}, " "),
+	}
+}
+
+func join(operator string, filters []Filter) Filter {
+	expressions := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		if filter.IsEmpty() {
+			continue
+		}
+		expressions = append(expressions, filter.expression)
+	}
+
+	switch len(expressions) {
+	case 0:
+		return Filter{}
+	case 1:
+		return Filter{
+			expression: expressions[0],
+		}
+	}
+
+	return Filter{
+		expression: "(" + strings.Join(expressions, ") "+operator+" (") + ")",
+	}
+}
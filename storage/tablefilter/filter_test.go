@@ -0,0 +1,141 @@
+package tablefilter
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLiterals(t *testing.T) {
+	testData := []struct {
+		input    Literal
+		expected string
+	}{
+		{
+			input:    String("hello"),
+			expected: "'hello'",
+		},
+		{
+			input:    String("it's 'quoted'"),
+			expected: "'it''s ''quoted'''",
+		},
+		{
+			input:    Bool(true),
+			expected: "true",
+		},
+		{
+			input:    Int32(-42),
+			expected: "-42",
+		},
+		{
+			input:    Int64(9223372036854775807),
+			expected: "9223372036854775807L",
+		},
+		{
+			input:    Double(2),
+			expected: "2.0",
+		},
+		{
+			input:    Double(1.25),
+			expected: "1.25",
+		},
+		{
+			input:    Double(math.NaN()),
+			expected: "NaN",
+		},
+		{
+			input:    Double(math.Inf(1)),
+			expected: "INF",
+		},
+		{
+			input:    Double(math.Inf(-1)),
+			expected: "-INF",
+		},
+		{
+			input:    DateTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("test", 3600))),
+			expected: "datetime'2024-01-02T02:04:05.0000000Z'",
+		},
+		{
+			input:    DateTime(time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)),
+			expected: "datetime'2024-01-02T03:04:05.1234567Z'",
+		},
+		{
+			input:    Guid(uuid.MustParse("4a6e9c3e-4b0f-4d6c-8d1a-0f0e6c2a9b1d")),
+			expected: "guid'4a6e9c3e-4b0f-4d6c-8d1a-0f0e6c2a9b1d'",
+		},
+		{
+			input:    Binary([]byte{0x0a, 0xff}),
+			expected: "X'0aff'",
+		},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.expected)
+		if actual := v.input.String(); actual != v.expected {
+			t.Fatalf("expected %q but got %q", v.expected, actual)
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	testData := []struct {
+		input    Filter
+		expected string
+	}{
+		{
+			input:    Filter{},
+			expected: "",
+		},
+		{
+			input:    Equal("PartitionKey", String("a")),
+			expected: "PartitionKey eq 'a'",
+		},
+		{
+			input:    NotEqual("Count", Int32(1)),
+			expected: "Count ne 1",
+		},
+		{
+			input:    GreaterThan("Count", Int64(1)),
+			expected: "Count gt 1L",
+		},
+		{
+			input:    GreaterThanOrEqual("RowKey", String("b")),
+			expected: "RowKey ge 'b'",
+		},
+		{
+			input:    LessThan("Ratio", Double(0.5)),
+			expected: "Ratio lt 0.5",
+		},
+		{
+			input:    LessThanOrEqual("Enabled", Bool(false)),
+			expected: "Enabled le false",
+		},
+		{
+			input:    Equal("PartitionKey", String("a")).And(GreaterThanOrEqual("RowKey", String("b"))),
+			expected: "(PartitionKey eq 'a') and (RowKey ge 'b')",
+		},
+		{
+			input:    Or(Equal("Count", Int32(1)), Equal("Count", Int32(2)), Equal("Count", Int32(3))),
+			expected: "(Count eq 1) or (Count eq 2) or (Count eq 3)",
+		},
+		{
+			input:    Not(Equal("Enabled", Bool(true)).Or(Equal("Count", Int32(0)))),
+			expected: "not ((Enabled eq true) or (Count eq 0))",
+		},
+		{
+			input:    And(Filter{}, Equal("PartitionKey", String("a")), Filter{}),
+			expected: "PartitionKey eq 'a'",
+		},
+		{
+			input:    Not(Filter{}),
+			expected: "",
+		},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.expected)
+		if actual := v.input.String(); actual != v.expected {
+			t.Fatalf("expected %q but got %q", v.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package tablefilter
+
+import (
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Literal is a typed OData value which a property can be compared against.
+type Literal struct {
+	value string
+}
+
+// String returns the OData representation of this Literal.
+func (l Literal) String() string {
+	return l.value
+}
+
+// Binary returns an Edm.Binary Literal, in the form `X'0aff'`.
+func Binary(input []byte) Literal {
+	return Literal{
+		value: "X'" + hex.EncodeToString(input) + "'",
+	}
+}
+
+// Bool returns an Edm.Boolean Literal.
+func Bool(input bool) Literal {
+	return Literal{
+		value: strconv.FormatBool(input),
+	}
+}
+
+// DateTime returns an Edm.DateTime Literal, in the form `datetime'2024-01-02T03:04:05.0000000Z'` - since an
+// Edm.DateTime has a precision of 100 nanoseconds, any further precision is truncated.
+func DateTime(input time.Time) Literal {
+	return Literal{
+		value: "datetime'" + input.UTC().Format("2006-01-02T15:04:05.0000000Z") + "'",
+	}
+}
+
+// Double returns an Edm.Double Literal - where NaN and ±Inf are represented as `NaN`, `INF` and `-INF`.
+func Double(input float64) Literal {
+	switch {
+	case math.IsNaN(input):
+		return Literal{value: "NaN"}
+	case math.IsInf(input, 1):
+		return Literal{value: "INF"}
+	case math.IsInf(input, -1):
+		return Literal{value: "-INF"}
+	}
+
+	value := strconv.FormatFloat(input, 'f', -1, 64)
+	if !strings.Contains(value, ".") {
+		// ensure whole numbers aren't interpreted as an Edm.Int32
+		value += ".0"
+	}
+	return Literal{
+		value: value,
+	}
+}
+
+// Guid returns an Edm.Guid Literal, in the form `guid'00000000-0000-0000-0000-000000000000'`.
+func Guid(input uuid.UUID) Literal {
+	return Literal{
+		value: "guid'" + input.String() + "'",
+	}
+}
+
+// Int32 returns an Edm.Int32 Literal.
+func Int32(input int32) Literal {
+	return Literal{
+		value: strconv.FormatInt(int64(input), 10),
+	}
+}
+
+// Int64 returns an Edm.Int64 Literal, in the form `123L`.
+func Int64(input int64) Literal {
+	return Literal{
+		value: strconv.FormatInt(input, 10) + "L",
+	}
+}
+
+// String returns an Edm.String Literal, in the form `'value'` - where any single quotes
+// within `input` are escaped.
+func String(input string) Literal {
+	return Literal{
+		value: "'" + strings.ReplaceAll(input, "'", "''") + "'",
+	}
+}
@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// HeaderClientRequestId is the header used to send a client-generated ID for each request, which
+	// is recorded in the Storage Analytics logs
+	HeaderClientRequestId = "x-ms-client-request-id"
+
+	// HeaderRequestId is the header containing the server-generated ID for each request
+	HeaderRequestId = "x-ms-request-id"
+)
+
+const (
+	AttributeOperation       = attribute.Key("azure.storage.operation")
+	AttributeAccountName     = attribute.Key("azure.storage.account")
+	AttributeContainerName   = attribute.Key("azure.storage.container")
+	AttributeFileSystemName  = attribute.Key("azure.storage.file_system")
+	AttributeShareName       = attribute.Key("azure.storage.share")
+	AttributeQueueName       = attribute.Key("azure.storage.queue")
+	AttributeTableName       = attribute.Key("azure.storage.table")
+	AttributeRequestId       = attribute.Key("azure.storage.request_id")
+	AttributeClientRequestId = attribute.Key("azure.storage.client_request_id")
+	AttributeErrorCode       = attribute.Key("azure.storage.error_code")
+)
+
+// ResourceType is the type of top-level resource within a Storage Account which an operation is performed against
+type ResourceType string
+
+const (
+	// AccountResourceType is used for operations against the Storage Account itself
+	AccountResourceType    ResourceType = "account"
+	ContainerResourceType  ResourceType = "container"
+	FileSystemResourceType ResourceType = "file_system"
+	ShareResourceType      ResourceType = "share"
+	QueueResourceType      ResourceType = "queue"
+	TableResourceType      ResourceType = "table"
+)
+
+func (t ResourceType) attributeKey() attribute.Key {
+	switch t {
+	case ContainerResourceType:
+		return AttributeContainerName
+	case FileSystemResourceType:
+		return AttributeFileSystemName
+	case ShareResourceType:
+		return AttributeShareName
+	case QueueResourceType:
+		return AttributeQueueName
+	case TableResourceType:
+		return AttributeTableName
+	}
+	return ""
+}
+
+type resource struct {
+	accountName string
+	name        string
+}
+
+// parseResource determines the Account Name and the name of the top-level resource from the request URI, which is
+// either in the format `https://{account}.{service}.{domain}/{resource}/...` or (for a Storage Emulator)
+// `http://{host}:{port}/{account}/{resource}/...`
+func parseResource(uri *url.URL, resourceType ResourceType) resource {
+	segments := strings.Split(strings.TrimPrefix(uri.EscapedPath(), "/"), "/")
+
+	var out resource
+	hostname := uri.Hostname()
+	if net.ParseIP(hostname) != nil || hostname == "localhost" {
+		out.accountName = segments[0]
+		segments = segments[1:]
+	} else {
+		out.accountName, _, _ = strings.Cut(hostname, ".")
+	}
+
+	if resourceType == AccountResourceType || len(segments) == 0 || segments[0] == "" {
+		return out
+	}
+
+	name, _ := url.PathUnescape(segments[0])
+	if resourceType == TableResourceType {
+		// Tables are referenced as `Tables('{name}')`, and Entities as `{name}(PartitionKey='..',RowKey='..')`
+		if strings.HasPrefix(name, "Tables(") {
+			name = strings.TrimSuffix(strings.TrimPrefix(name, "Tables('"), "')")
+		} else if name == "Tables" || name == "$batch" {
+			name = ""
+		} else if v, _, ok := strings.Cut(name, "("); ok {
+			name = v
+		}
+	}
+	out.name = name
+	return out
+}
@@ -0,0 +1,66 @@
+package telemetry
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseResource(t *testing.T) {
+	testData := []struct {
+		input        string
+		resourceType ResourceType
+		expected     resource
+	}{
+		{
+			input:        "https://example.blob.core.windows.net/container1/some/blob.txt",
+			resourceType: ContainerResourceType,
+			expected:     resource{accountName: "example", name: "container1"},
+		},
+		{
+			input:        "https://example.blob.core.windows.net/?comp=properties&restype=service",
+			resourceType: AccountResourceType,
+			expected:     resource{accountName: "example"},
+		},
+		{
+			input:        "http://127.0.0.1:10000/devstoreaccount1/container1/blob.txt",
+			resourceType: ContainerResourceType,
+			expected:     resource{accountName: "devstoreaccount1", name: "container1"},
+		},
+		{
+			input:        "https://example.file.core.windows.net/share1/dir/file.txt",
+			resourceType: ShareResourceType,
+			expected:     resource{accountName: "example", name: "share1"},
+		},
+		{
+			input:        "https://example.table.core.windows.net/Tables",
+			resourceType: TableResourceType,
+			expected:     resource{accountName: "example"},
+		},
+		{
+			input:        "https://example.table.core.windows.net/Tables('table1')",
+			resourceType: TableResourceType,
+			expected:     resource{accountName: "example", name: "table1"},
+		},
+		{
+			input:        "https://example.table.core.windows.net/table1(PartitionKey='a',%20RowKey='b')",
+			resourceType: TableResourceType,
+			expected:     resource{accountName: "example", name: "table1"},
+		},
+		{
+			input:        "https://example.table.core.windows.net/$batch",
+			resourceType: TableResourceType,
+			expected:     resource{accountName: "example"},
+		},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.input)
+		uri, err := url.Parse(v.input)
+		if err != nil {
+			t.Fatalf("parsing %q: %+v", v.input, err)
+		}
+		actual := parseResource(uri, v.resourceType)
+		if actual != v.expected {
+			t.Fatalf("expected %+v but got %+v", v.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the Tracer and Meter used by this SDK
+const instrumentationName = "github.com/tombuildsstuff/giovanni/storage"
+
+// Instrumentation records an OpenTelemetry Span and Metrics for each operation performed by a client.
+//
+// An Instrumentation can be set on any client (for example `blobs.Client.Instrumentation`) - when unset no
+// Spans or Metrics are recorded.
+type Instrumentation struct {
+	tracer trace.Tracer
+
+	duration     metric.Float64Histogram
+	requestSize  metric.Int64Histogram
+	responseSize metric.Int64Histogram
+}
+
+// New returns an Instrumentation which uses the specified TracerProvider and MeterProvider.
+func New(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Instrumentation, error) {
+	meter := meterProvider.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram("storage.client.operation.duration",
+		metric.WithDescription("The duration of each operation performed against the Storage API."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("building duration histogram: %+v", err)
+	}
+
+	requestSize, err := meter.Int64Histogram("storage.client.request.body.size",
+		metric.WithDescription("The number of bytes sent in the body of each request to the Storage API."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("building request size histogram: %+v", err)
+	}
+
+	responseSize, err := meter.Int64Histogram("storage.client.response.body.size",
+		metric.WithDescription("The number of bytes received in the body of each response from the Storage API."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("building response size histogram: %+v", err)
+	}
+
+	return &Instrumentation{
+		tracer:       tracerProvider.Tracer(instrumentationName),
+		duration:     duration,
+		requestSize:  requestSize,
+		responseSize: responseSize,
+	}, nil
+}
+
+// NewFromGlobal returns an Instrumentation which uses the global TracerProvider and MeterProvider.
+func NewFromGlobal() (*Instrumentation, error) {
+	return New(otel.GetTracerProvider(), otel.GetMeterProvider())
+}
+
+// Operation describes the operation being performed
+type Operation struct {
+	// Name is the name of the operation, in the format `{package}.{method}` (e.g. `blobs.PutBlock`).
+	Name string
+
+	// ResourceType is the type of the top-level resource (e.g. a Container or Share) which the operation
+	// is performed against - used to determine the name of that resource from the request.
+	ResourceType ResourceType
+}
+
+// Execute performs the request using `execute` - recording a Span and Metrics for the operation when the
+// Instrumentation is non-nil.
+//
+// A `x-ms-client-request-id` header is added to the request (if not already present), so that the Span can be
+// correlated with the Storage Analytics logs.
+func (i *Instrumentation) Execute(ctx context.Context, req *client.Request, operation Operation, execute func(ctx context.Context) (*client.Response, error)) (*client.Response, error) {
+	if i == nil {
+		return execute(ctx)
+	}
+
+	clientRequestId := req.Header.Get(HeaderClientRequestId)
+	if clientRequestId == "" {
+		clientRequestId = uuid.New().String()
+		req.Header.Set(HeaderClientRequestId, clientRequestId)
+	}
+
+	resource := parseResource(req.URL, operation.ResourceType)
+	attributes := []attribute.KeyValue{
+		AttributeOperation.String(operation.Name),
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Hostname()),
+	}
+	if resource.accountName != "" {
+		attributes = append(attributes, AttributeAccountName.String(resource.accountName))
+	}
+	if resource.name != "" {
+		attributes = append(attributes, operation.ResourceType.attributeKey().String(resource.name))
+	}
+
+	ctx, span := i.tracer.Start(ctx, operation.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+		trace.WithAttributes(AttributeClientRequestId.String(clientRequestId)))
+	defer span.End()
+
+	var sent *countingReader
+	if req.Body != nil {
+		sent = &countingReader{reader: req.Body}
+		req.Body = sent
+	}
+
+	start := time.Now()
+	resp, err := execute(ctx)
+	elapsed := time.Since(start)
+
+	metricAttributes := append([]attribute.KeyValue{}, attributes...)
+	if resp != nil && resp.Response != nil {
+		statusCode := attribute.Int("http.response.status_code", resp.StatusCode)
+		span.SetAttributes(statusCode)
+		metricAttributes = append(metricAttributes, statusCode)
+
+		if v := resp.Header.Get(HeaderRequestId); v != "" {
+			span.SetAttributes(AttributeRequestId.String(v))
+		}
+		if v := resp.Header.Get("x-ms-error-code"); v != "" {
+			errorCode := AttributeErrorCode.String(v)
+			span.SetAttributes(errorCode)
+			metricAttributes = append(metricAttributes, errorCode)
+		}
+		if resp.ContentLength >= 0 {
+			span.SetAttributes(attribute.Int64("http.response.body.size", resp.ContentLength))
+			i.responseSize.Record(ctx, resp.ContentLength, metric.WithAttributes(metricAttributes...))
+		}
+	}
+	if sent != nil {
+		span.SetAttributes(attribute.Int64("http.request.body.size", sent.count))
+		i.requestSize.Record(ctx, sent.count, metric.WithAttributes(metricAttributes...))
+	}
+	i.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(metricAttributes...))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return resp, err
+}
+
+// countingReader counts the number of bytes read from the request body
+type countingReader struct {
+	reader io.ReadCloser
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	return r.reader.Close()
+}
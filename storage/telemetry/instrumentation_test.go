@@ -0,0 +1,155 @@
+package telemetry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/blobs"
+	"github.com/tombuildsstuff/giovanni/storage/2023-11-03/blob/containers"
+	"github.com/tombuildsstuff/giovanni/storage/internal/testhelpers/blobserver"
+	"github.com/tombuildsstuff/giovanni/storage/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumentation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	instrumentation, err := telemetry.New(tracerProvider, meterProvider)
+	if err != nil {
+		t.Fatalf("building instrumentation: %+v", err)
+	}
+
+	server := blobserver.New(t)
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+
+	containersClient, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	containersClient.Client.SetAuthorizer(authorizer)
+	containersClient.Instrumentation = instrumentation
+
+	blobsClient, err := blobs.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	blobsClient.Client.SetAuthorizer(authorizer)
+	blobsClient.Instrumentation = instrumentation
+
+	if _, err := containersClient.Create(ctx, "container1", containers.CreateInput{}); err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	resp, err := blobsClient.PutBlockBlob(ctx, "container1", "example.txt", blobs.PutBlockBlobInput{Content: pointer.To([]byte("hello world"))})
+	if err != nil {
+		t.Fatalf("putting blob: %+v", err)
+	}
+	if _, err := blobsClient.GetProperties(ctx, "container1", "missing.txt", blobs.GetPropertiesInput{}); err == nil {
+		t.Fatalf("expected an error retrieving a missing blob but didn't get one")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("expected 3 spans but got %d", len(ended))
+	}
+	for i, name := range []string{"containers.Create", "blobs.PutBlockBlob", "blobs.GetProperties"} {
+		if ended[i].Name() != name {
+			t.Fatalf("expected span %d to be named %q but got %q", i, name, ended[i].Name())
+		}
+	}
+
+	putBlob := attributesOf(ended[1].Attributes())
+	expected := map[attribute.Key]string{
+		telemetry.AttributeOperation:     "blobs.PutBlockBlob",
+		telemetry.AttributeAccountName:   blobserver.DefaultAccountName,
+		telemetry.AttributeContainerName: "container1",
+		"http.request.method":            "PUT",
+		"http.response.status_code":      "201",
+		"http.request.body.size":         "11",
+	}
+	for k, v := range expected {
+		if putBlob[k] != v {
+			t.Fatalf("expected the attribute %q to be %q but got %q", k, v, putBlob[k])
+		}
+	}
+	requestId := resp.HttpResponse.Header.Get(telemetry.HeaderRequestId)
+	if requestId == "" || putBlob[telemetry.AttributeRequestId] != requestId {
+		t.Fatalf("expected the attribute %q to be %q but got %q", telemetry.AttributeRequestId, requestId, putBlob[telemetry.AttributeRequestId])
+	}
+	// the server echoes the client request id, so this confirms it was sent
+	clientRequestId := resp.HttpResponse.Header.Get(telemetry.HeaderClientRequestId)
+	if clientRequestId == "" || putBlob[telemetry.AttributeClientRequestId] != clientRequestId {
+		t.Fatalf("expected the attribute %q to be %q but got %q", telemetry.AttributeClientRequestId, clientRequestId, putBlob[telemetry.AttributeClientRequestId])
+	}
+
+	getProperties := attributesOf(ended[2].Attributes())
+	if getProperties["http.response.status_code"] != "404" || getProperties[telemetry.AttributeErrorCode] != "BlobNotFound" {
+		t.Fatalf("expected the failed span to have a 404 and the error code `BlobNotFound` but got %q and %q", getProperties["http.response.status_code"], getProperties[telemetry.AttributeErrorCode])
+	}
+	if ended[2].Status().Code != codes.Error {
+		t.Fatalf("expected the failed span to have an Error status but got %q", ended[2].Status().Code)
+	}
+
+	var metrics metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &metrics); err != nil {
+		t.Fatalf("collecting metrics: %+v", err)
+	}
+	names := map[string]bool{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			names[m.Name] = true
+		}
+	}
+	for _, name := range []string{"storage.client.operation.duration", "storage.client.request.body.size", "storage.client.response.body.size"} {
+		if !names[name] {
+			t.Fatalf("expected the metric %q to be recorded", name)
+		}
+	}
+}
+
+func TestInstrumentationNil(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	server := blobserver.New(t)
+	authorizer, err := server.Authorizer()
+	if err != nil {
+		t.Fatalf("building authorizer: %+v", err)
+	}
+	client, err := containers.NewWithBaseUri(server.BaseUri())
+	if err != nil {
+		t.Fatalf("building client: %+v", err)
+	}
+	client.Client.SetAuthorizer(authorizer)
+
+	resp, err := client.Create(ctx, "container1", containers.CreateInput{})
+	if err != nil {
+		t.Fatalf("creating container: %+v", err)
+	}
+	if v := resp.HttpResponse.Header.Get(telemetry.HeaderClientRequestId); v != "" {
+		t.Fatalf("expected no client request id to be sent when uninstrumented but got %q", v)
+	}
+}
+
+func attributesOf(input []attribute.KeyValue) map[attribute.Key]string {
+	out := make(map[attribute.Key]string)
+	for _, v := range input {
+		out[v.Key] = v.Value.Emit()
+	}
+	return out
+}